@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminService serves the AdminService, which lets presenters change demo
+// settings without restarting the service. Calls must carry token.
+type adminService struct {
+	token    string
+	settings *admin.Settings
+}
+
+func newAdminService(token string, r *faults.Registry) *adminService {
+	settings := admin.FaultSettings(r)
+	if _, ok := r.Scenario("request-cache-leak"); ok {
+		settings["cache_user_threshold"] = admin.Setting{
+			Get: func() string {
+				s, _ := r.Scenario("request-cache-leak")
+				return strconv.Itoa(s.Threshold)
+			},
+			Parse: func(v string) (func(), error) {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, err
+				}
+				return admin.ScenarioChange(r, "request-cache-leak", func(s *faults.Scenario) { s.Threshold = n })
+			},
+		}
+	}
+	settings["discount_database_lookup"] = admin.Bool(discountDatabaseLookup.Load, discountDatabaseLookup.Store)
+	return &adminService{token: token, settings: admin.NewSettings(settings)}
+}
+
+func (a *adminService) GetSettings(ctx context.Context, _ *pb.Empty) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	return &pb.AdminSettings{Settings: a.settings.Current()}, nil
+}
+
+func (a *adminService) UpdateSettings(ctx context.Context, in *pb.AdminSettings) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	current, err := a.settings.Update(ctx, log, in.Settings)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.AdminSettings{Settings: current}, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminUpdateSettings(t *testing.T) {
+	r, err := newFaultRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := newAdminService("secret", r)
+	ctx := adminContext("secret")
+	t.Cleanup(func() { discountDatabaseLookup.Store(false) })
+
+	got, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{
+		"cache_user_threshold":                 "1000",
+		"discount_database_lookup":             "true",
+		"fault.discount-n-plus-one.enabled":    "false",
+		"fault.request-cache-leak.probability": "0.5",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Settings["cache_user_threshold"] != "1000" || got.Settings["fault.discount-n-plus-one.enabled"] != "false" {
+		t.Errorf("settings not applied: %v", got.Settings)
+	}
+	if !discountDatabaseLookup.Load() {
+		t.Error("discount database lookup not turned on")
+	}
+	if s, _ := r.Scenario("request-cache-leak"); s.Threshold != 1000 || s.Probability != 0.5 {
+		t.Errorf("leak scenario not updated: %+v", s)
+	}
+
+	for _, bad := range []map[string]string{
+		{"no_such_setting": "1"},
+		{"cache_user_threshold": "lots"},
+		{"discount_database_lookup": "sometimes"},
+		{"cache_user_threshold": "60000"}, // above the leak's max size
+		{"cache_user_threshold": "2000", "discount_database_lookup": "sometimes"},
+	} {
+		_, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: bad})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: got %v, want InvalidArgument", bad, err)
+		}
+	}
+	if got, _ := admin.GetSettings(ctx, &pb.Empty{}); got.Settings["cache_user_threshold"] != "1000" {
+		t.Errorf("rejected update changed cache_user_threshold to %s", got.Settings["cache_user_threshold"])
+	}
+}
+
+// adminContext returns the context of an AdminService call carrying token.
+func adminContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	r, err := newFaultRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := &pb.AdminSettings{Settings: map[string]string{"discount_database_lookup": "true"}}
+	for _, ctx := range []context.Context{context.Background(), adminContext("wrong")} {
+		admin := newAdminService("secret", r)
+		if _, err := admin.GetSettings(ctx, &pb.Empty{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("GetSettings: got %v, want Unauthenticated", err)
+		}
+		if _, err := admin.UpdateSettings(ctx, update); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("UpdateSettings: got %v, want Unauthenticated", err)
+		}
+	}
+	if _, err := newAdminService("", r).UpdateSettings(adminContext(""), update); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v with no ADMIN_TOKEN, want PermissionDenied", err)
+	}
+	if discountDatabaseLookup.Load() {
+		t.Error("an unauthorized update was applied")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+)
+
+// TestResetCache drives the request cache past cacheUserThreshold and checks
+// that ResetCache brings it back, both to a target size and to empty.
+func TestResetCache(t *testing.T) {
+	requestCache.Flush()
+	defer requestCache.Flush()
+	cs, _ := newTestCheckoutService()
+	ctx := context.Background()
+
+	requestCacheSink{}.Leak(ctx, faults.Target{RequestID: "leak"}, cacheUserThreshold+1000)
+	size, _ := cs.GetCacheSize(ctx, &pb.Empty{})
+	if size.CacheSize <= int64(cacheUserThreshold) {
+		t.Fatalf("cache holds %d entries, want more than %d", size.CacheSize, cacheUserThreshold)
+	}
+
+	resp, err := cs.ResetCache(ctx, &pb.ResetCacheRequest{TargetSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.CacheSize != 100 || requestCache.ItemCount() != 100 {
+		t.Errorf("got cache size %d after reset, want 100", resp.CacheSize)
+	}
+
+	// a target above the current size leaves the cache alone
+	if resp, _ := cs.ResetCache(ctx, &pb.ResetCacheRequest{TargetSize: 500}); resp.CacheSize != 100 {
+		t.Errorf("got cache size %d, want 100", resp.CacheSize)
+	}
+
+	if resp, _ := cs.ResetCache(ctx, &pb.ResetCacheRequest{}); resp.CacheSize != 0 {
+		t.Errorf("got cache size %d after clearing, want 0", resp.CacheSize)
+	}
+	if _, err := cs.ResetCache(ctx, &pb.ResetCacheRequest{TargetSize: -1}); err == nil {
+		t.Error("negative target size should be rejected")
+	}
+}
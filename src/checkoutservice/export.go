@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultExportMaxBytes   = 10 << 20
+	defaultExportMaxBackups = 5
+	defaultExportTimeout    = 5 * time.Second
+)
+
+// OrderExporter hands completed orders to downstream consumers, such as the
+// invoice pipeline in src/invoiceservice.
+type OrderExporter interface {
+	Export(ctx context.Context, rec OrderExportRecord) error
+	Close() error
+}
+
+// OrderExportRecord is an order in the schema of src/invoiceservice/data/orders.json.
+// Amounts are in the order's currency.
+type OrderExportRecord struct {
+	OrderID         string       `json:"order_id"`
+	UserID          string       `json:"user_id"`
+	CustomerEmail   string       `json:"customer_email"`
+	CustomerAddress string       `json:"customer_address"`
+	Country         string       `json:"country"`
+	Currency        string       `json:"currency"`
+	TaxPercent      float64      `json:"tax_percent"`
+	Items           []ExportItem `json:"items"`
+	Subtotal        float64      `json:"subtotal"`
+	DiscountTotal   float64      `json:"discount_total"`
+	ShippingCost    float64      `json:"shipping_cost"`
+	TaxAmount       float64      `json:"tax_amount"`
+	TotalCharged    float64      `json:"total_charged"`
+	PlacedAt        time.Time    `json:"placed_at"`
+	// ConfirmationSent is false when the confirmation email failed, so it can
+	// be sent again later.
+	ConfirmationSent bool `json:"confirmation_sent"`
+	// TraceContext holds the W3C traceparent and tracestate of the checkout,
+	// so the invoice traces can link back to it.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+type ExportItem struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	UnitPrice   float64 `json:"unit_price"`
+	Quantity    int32   `json:"quantity"`
+	ItemTotal   float64 `json:"item_total"`
+}
+
+// newOrderExportRecord describes order as placed by req. products are the
+// catalog entries of the ordered items, for their names, and taxRate the
+// percentage the order was taxed at.
+func newOrderExportRecord(ctx context.Context, req *pb.PlaceOrderRequest, order *pb.OrderResult, products map[string]*pb.Product, total *pb.Money, taxRate float64) OrderExportRecord {
+	a := order.GetShippingAddress()
+	rec := OrderExportRecord{
+		OrderID:         order.GetOrderId(),
+		UserID:          req.GetUserId(),
+		CustomerEmail:   req.GetEmail(),
+		CustomerAddress: fmt.Sprintf("%s, %s, %s, %d", a.GetStreetAddress(), a.GetCity(), a.GetCountry(), a.GetZipCode()),
+		Country:         a.GetCountry(),
+		Currency:        total.GetCurrencyCode(),
+		TaxPercent:      taxRate,
+		Items:           make([]ExportItem, len(order.GetItems())),
+		ShippingCost:    moneyToFloat(order.GetShippingCost()),
+		TaxAmount:       moneyToFloat(order.GetTax()),
+		TotalCharged:    moneyToFloat(total),
+		PlacedAt:        time.Unix(order.GetPlacedAt(), 0).UTC(),
+		TraceContext:    map[string]string{},
+	}
+	for i, it := range order.GetItems() {
+		unit := moneyToFloat(it.GetCost())
+		item := ExportItem{
+			ProductID:   it.GetItem().GetProductId(),
+			ProductName: products[it.GetItem().GetProductId()].GetName(),
+			UnitPrice:   unit,
+			Quantity:    it.GetItem().GetQuantity(),
+			ItemTotal:   round2(unit * float64(it.GetItem().GetQuantity())),
+		}
+		rec.Items[i] = item
+		rec.Subtotal += item.ItemTotal
+	}
+	rec.Subtotal = round2(rec.Subtotal)
+	for _, d := range order.GetDiscounts() {
+		rec.DiscountTotal += moneyToFloat(d.GetAmount())
+	}
+	rec.DiscountTotal = round2(rec.DiscountTotal)
+	propagation.TraceContext{}.Inject(ctx, propagation.MapCarrier(rec.TraceContext))
+	return rec
+}
+
+func moneyToFloat(m *pb.Money) float64 {
+	return round2(float64(m.GetUnits()) + float64(m.GetNanos())/1e9)
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// newOrderExporterFromEnv creates the exporters named in ORDER_EXPORT, a comma
+// separated list of file and http. It returns nil if there are none.
+func newOrderExporterFromEnv() (OrderExporter, error) {
+	var exporters multiOrderExporter
+	for _, name := range strings.Split(os.Getenv("ORDER_EXPORT"), ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "file":
+			maxBytes := int64(defaultExportMaxBytes)
+			if v, err := strconv.ParseInt(os.Getenv("ORDER_EXPORT_MAX_BYTES"), 10, 64); err == nil {
+				maxBytes = v
+			}
+			maxBackups := defaultExportMaxBackups
+			if v, err := strconv.Atoi(os.Getenv("ORDER_EXPORT_MAX_BACKUPS")); err == nil {
+				maxBackups = v
+			}
+			e, err := newFileOrderExporter(os.Getenv("ORDER_EXPORT_PATH"), os.Getenv("ORDER_EXPORT_FORMAT"), maxBytes, maxBackups)
+			if err != nil {
+				return nil, err
+			}
+			exporters = append(exporters, e)
+		case "http":
+			url := os.Getenv("ORDER_EXPORT_URL")
+			if url == "" {
+				return nil, errors.New("the http order exporter needs ORDER_EXPORT_URL")
+			}
+			exporters = append(exporters, &httpOrderExporter{url: url, client: &http.Client{Timeout: defaultExportTimeout}})
+		default:
+			return nil, fmt.Errorf("unknown order exporter %q", name)
+		}
+	}
+	if len(exporters) == 0 {
+		return nil, nil
+	}
+	return exporters, nil
+}
+
+// exportOrder sends a placed order to the exporters. A failed export is
+// logged rather than failing an order that has already been charged.
+func (cs *checkoutService) exportOrder(ctx context.Context, rec OrderExportRecord) {
+	if cs.exporter == nil {
+		return
+	}
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "exportOrder")
+	defer span.End()
+	span.SetAttributes(attribute.String("app.order_id", rec.OrderID))
+
+	if err := cs.exporter.Export(ctx, rec); err != nil {
+		log.Warnf("failed to export order %s: %+v", rec.OrderID, err)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+}
+
+type multiOrderExporter []OrderExporter
+
+func (m multiOrderExporter) Export(ctx context.Context, rec OrderExportRecord) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.Export(ctx, rec))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiOrderExporter) Close() error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// fileOrderExporter appends orders to a file, which is rotated to path.1,
+// path.2 and so on when it would grow past maxBytes. The ndjson format writes
+// one order per line. The json format keeps the file a complete orders.json
+// document, {"orders": [...]}, that the invoice DAG can read as is, with one
+// order per line inside it.
+type fileOrderExporter struct {
+	lock       sync.Mutex
+	path       string
+	format     string
+	maxBytes   int64
+	maxBackups int
+}
+
+func newFileOrderExporter(path, format string, maxBytes int64, maxBackups int) (*fileOrderExporter, error) {
+	switch format {
+	case "", "ndjson":
+		format = "ndjson"
+		if path == "" {
+			path = "orders.ndjson"
+		}
+	case "json":
+		if path == "" {
+			path = "orders.json"
+		}
+	default:
+		return nil, fmt.Errorf("unknown order export format %q", format)
+	}
+	return &fileOrderExporter{path: path, format: format, maxBytes: maxBytes, maxBackups: maxBackups}, nil
+}
+
+func (e *fileOrderExporter) Export(ctx context.Context, rec OrderExportRecord) error {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("app.export.path", e.path))
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.format == "json" {
+		return e.exportDocument(rec)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if err := e.rotateFor(int64(len(line))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open order export %s: %w", e.path, err)
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// documentEnd closes the orders array of a json export. Each order is
+// written over it, followed by a new one, so exporting an order only writes
+// that order and the file stays a complete document.
+const documentEnd = "\n  ]\n}\n"
+
+func (e *fileOrderExporter) exportDocument(rec OrderExportRecord) error {
+	order, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	// what appending the order adds to an existing document
+	if err := e.rotateFor(int64(len(",\n    ") + len(order))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open order export %s: %w", e.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	var out []byte
+	offset := info.Size()
+	if offset == 0 {
+		out = append([]byte("{\n  \"orders\": [\n    "), order...)
+	} else {
+		offset -= int64(len(documentEnd))
+		end := make([]byte, len(documentEnd))
+		if _, err := f.ReadAt(end, offset); err != nil || string(end) != documentEnd {
+			_ = f.Close()
+			return fmt.Errorf("order export %s doesn't end like an orders document", e.path)
+		}
+		out = append([]byte(",\n    "), order...)
+	}
+	// a single write, so the DAG sees the document with or without this order
+	if _, err := f.WriteAt(append(out, documentEnd...), offset); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotateFor rotates the file if appending n bytes would take it past maxBytes.
+func (e *fileOrderExporter) rotateFor(n int64) error {
+	if e.maxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(e.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if info.Size() == 0 || info.Size()+n <= e.maxBytes {
+		return nil
+	}
+	return e.rotate()
+}
+
+// rotate moves path to path.1, path.1 to path.2 and so on, dropping the
+// oldest file past maxBackups.
+func (e *fileOrderExporter) rotate() error {
+	if e.maxBackups <= 0 {
+		return os.Remove(e.path)
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", e.path, e.maxBackups))
+	for i := e.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", e.path, i), fmt.Sprintf("%s.%d", e.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(e.path, e.path+".1")
+}
+
+func (e *fileOrderExporter) Close() error { return nil }
+
+// httpOrderExporter posts every order as JSON to a URL, propagating the trace
+// context in the request headers as well as in the record.
+type httpOrderExporter struct {
+	url    string
+	client *http.Client
+}
+
+func (e *httpOrderExporter) Export(ctx context.Context, rec OrderExportRecord) error {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push order: %w", err)
+	}
+	defer resp.Body.Close()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("order push returned %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func (e *httpOrderExporter) Close() error { return nil }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []OrderExportRecord
+}
+
+func (e *recordingExporter) Export(_ context.Context, rec OrderExportRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, rec)
+	return nil
+}
+
+func (e *recordingExporter) Close() error { return nil }
+
+func tracedContext(t *testing.T) (context.Context, trace.SpanContext) {
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("test").Start(context.Background(), "checkout")
+	t.Cleanup(func() { span.End() })
+	return ctx, span.SpanContext()
+}
+
+func TestPlaceOrderExportsOrder(t *testing.T) {
+	cs, _ := newTestCheckoutService()
+	exporter := &recordingExporter{}
+	cs.exporter = exporter
+
+	ctx, sc := tracedContext(t)
+	resp, err := cs.PlaceOrder(ctx, testOrderRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exporter.records) != 1 {
+		t.Fatalf("got %d exported orders, want 1", len(exporter.records))
+	}
+	rec := exporter.records[0]
+	if rec.OrderID != resp.GetOrder().GetOrderId() {
+		t.Errorf("got order %q, want %q", rec.OrderID, resp.GetOrder().GetOrderId())
+	}
+	if rec.CustomerEmail != "someone@example.com" || rec.Country != "United States" {
+		t.Errorf("got customer %q in %q", rec.CustomerEmail, rec.Country)
+	}
+	if want := "1600 Amphitheatre Parkway, Mountain View, United States, 94043"; rec.CustomerAddress != want {
+		t.Errorf("got address %q, want %q", rec.CustomerAddress, want)
+	}
+	if len(rec.Items) != 2 || rec.Items[1].ProductName != "Tank Top" || rec.Items[1].ItemTotal != 24.98 {
+		t.Errorf("got items %+v", rec.Items)
+	}
+	// 67.99 + 2 * 12.49 for the items, 8.99 shipping, and 7.25% California tax on the items
+	if rec.Subtotal != 92.97 || rec.TaxPercent != 7.25 || rec.TaxAmount != 6.74 || rec.TotalCharged != 108.70 {
+		t.Errorf("got subtotal %v, tax %v%% = %v and total %v, want 92.97, 7.25%% = 6.74 and 108.70",
+			rec.Subtotal, rec.TaxPercent, rec.TaxAmount, rec.TotalCharged)
+	}
+	if !strings.Contains(rec.TraceContext["traceparent"], sc.TraceID().String()) {
+		t.Errorf("got traceparent %q, want trace %s", rec.TraceContext["traceparent"], sc.TraceID())
+	}
+}
+
+func TestFileOrderExporterRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.ndjson")
+	rec := OrderExportRecord{OrderID: "order-1", Items: []ExportItem{}}
+	line, _ := json.Marshal(rec)
+	// room for two orders per file
+	e, err := newFileOrderExporter(path, "ndjson", int64(2*(len(line)+1)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 7; i++ {
+		if err := e.Export(context.Background(), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, want := range map[string]int{path: 1, path + ".1": 2, path + ".2": 2} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(string(b), "\n"); got != want {
+			t.Errorf("%s has %d orders, want %d", filepath.Base(file), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept more than 2 backups: %v", err)
+	}
+}
+
+func TestFileOrderExporterDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.json")
+	e, err := newFileOrderExporter(path, "json", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"order-1", "order-2"} {
+		if err := e.Export(context.Background(), OrderExportRecord{OrderID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var doc struct {
+		Orders []OrderExportRecord `json:"orders"`
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Orders) != 2 || doc.Orders[1].OrderID != "order-2" {
+		t.Errorf("got orders %+v", doc.Orders)
+	}
+}
+
+func TestFileOrderExporterDocumentRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.json")
+	rec := OrderExportRecord{OrderID: "order-1", Items: []ExportItem{}}
+	line, _ := json.Marshal(rec)
+	// room for two orders per document
+	e, err := newFileOrderExporter(path, "json", int64(len("{\n  \"orders\": [\n    ")+2*len(line)+len(",\n    ")+len(documentEnd)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := e.Export(context.Background(), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, want := range map[string]int{path: 1, path + ".1": 2} {
+		var doc struct {
+			Orders []OrderExportRecord `json:"orders"`
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(file), err)
+		}
+		if len(doc.Orders) != want {
+			t.Errorf("%s has %d orders, want %d", filepath.Base(file), len(doc.Orders), want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("not a document"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(context.Background(), rec); err == nil {
+		t.Error("appended to a file that isn't an orders document")
+	}
+}
+
+func TestHTTPOrderExporter(t *testing.T) {
+	var got OrderExportRecord
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	// main installs the propagator in initOtelTracing
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(prev)
+
+	ctx, sc := tracedContext(t)
+	e := &httpOrderExporter{url: srv.URL, client: srv.Client()}
+	if err := e.Export(ctx, OrderExportRecord{OrderID: "order-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.OrderID != "order-1" {
+		t.Errorf("got order %q", got.OrderID)
+	}
+	if !strings.Contains(traceparent, sc.TraceID().String()) {
+		t.Errorf("got traceparent %q, want trace %s", traceparent, sc.TraceID())
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "full", http.StatusServiceUnavailable)
+	})
+	if err := e.Export(ctx, OrderExportRecord{OrderID: "order-2"}); err == nil {
+		t.Error("expected an error for a 503")
+	}
+}
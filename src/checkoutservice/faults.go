@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"github.com/patrickmn/go-cache"
+)
+
+// requestCacheSink leaks OrderCache entries into requestCache, which is how
+// the checkout service runs out of memory in the demo.
+type requestCacheSink struct{}
+
+func (requestCacheSink) Size() int {
+	return requestCache.ItemCount()
+}
+
+func (requestCacheSink) Leak(_ context.Context, t faults.Target, n int) {
+	log.Debugf("increasing cache by: %d", n)
+	entry := &OrderCache{
+		OrderId:   t.Attrs["order_id"],
+		UserId:    t.UserID,
+		RequestId: t.RequestID,
+		Currency:  t.Attrs["currency"],
+	}
+	for i := 0; i < n; i++ {
+		requestCache.Set(t.RequestID+strconv.Itoa(i), entry, cache.NoExpiration)
+	}
+}
+
+// defaultFaultScenarios are the scenarios the checkout service runs when
+// FAULTS_CONFIG is not set. Together they tell the demo story: the request
+// cache grows with every order, and once it passes cacheUserThreshold the
+// discount lookup turns into an N+1 query that hits user 20109 hardest.
+func defaultFaultScenarios() []faults.Scenario {
+	return []faults.Scenario{
+		{
+			Name:        "request-cache-leak",
+			Kind:        faults.KindLeak,
+			Point:       "checkout.PlaceOrder",
+			Enabled:     true,
+			Probability: 1,
+			Sink:        "requestCache",
+			Base:        8,
+			Jitter:      1,
+			Threshold:   cacheUserThreshold,
+			MaxSize:     54000,
+			MaxExtra:    11,
+		},
+		{
+			Name:        "discount-n-plus-one",
+			Kind:        faults.KindNPlusOne,
+			Point:       "checkout.getDiscounts",
+			Enabled:     true,
+			Probability: 0.25,
+			Target: &faults.Targeting{
+				Users:       []string{"20109"},
+				Probability: 0.5,
+			},
+			SpanName:     "SELECT checkout.discounts",
+			Statement:    "SELECT * FROM discounts WHERE user = ?",
+			Database:     "checkout",
+			CallTime:     faults.Duration(250 * time.Millisecond),
+			LoadDivisor:  6000,
+			LoadExponent: 4,
+			LoadScale:    3000,
+		},
+	}
+}
+
+// newFaultRegistry loads the scenarios in path, or the defaults if path is empty.
+func newFaultRegistry(path string) (*faults.Registry, error) {
+	scenarios := defaultFaultScenarios()
+	if path != "" {
+		var err error
+		if scenarios, err = faults.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	r, err := faults.NewRegistry(scenarios)
+	if err != nil {
+		return nil, err
+	}
+	r.RegisterLeakSink("requestCache", requestCacheSink{})
+	return r, nil
+}
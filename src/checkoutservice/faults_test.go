@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestPlaceOrderLeaksIntoRequestCache(t *testing.T) {
+	requestCache.Flush()
+	defer requestCache.Flush()
+
+	cs, _ := newTestCheckoutService()
+	var err error
+	if cs.faults, err = newFaultRegistry(""); err != nil {
+		t.Fatalf("default scenarios are invalid: %v", err)
+	}
+
+	requestID, _ := baggage.NewMember("app.request_id", "request-1")
+	bags, _ := baggage.New(requestID)
+	ctx := baggage.ContextWithBaggage(context.Background(), bags)
+	if _, err := cs.PlaceOrder(ctx, testOrderRequest()); err != nil {
+		t.Fatal(err)
+	}
+	if n := requestCache.ItemCount(); n < 8 || n > 9 {
+		t.Errorf("request cache holds %d entries after one order, want 8 or 9", n)
+	}
+	entry, ok := requestCache.Get("request-10")
+	if !ok || entry.(*OrderCache).Currency != "USD" {
+		t.Errorf("got cache entry %+v, want the order's details", entry)
+	}
+}
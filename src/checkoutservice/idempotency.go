@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// idempotencyKeyHeader is the gRPC metadata key the frontend uses to pass the
+// client-supplied idempotency key of an order.
+const idempotencyKeyHeader = "idempotency-key"
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyStore remembers the outcome of PlaceOrder by idempotency key, so
+// a retried or double-submitted order returns the original result instead of
+// charging the customer again.
+type IdempotencyStore interface {
+	// Claim reserves key for a new order and returns claimed == true. If the
+	// key is already held, Claim waits for that order to finish and returns
+	// its response instead.
+	Claim(ctx context.Context, key string) (resp *pb.PlaceOrderResponse, claimed bool, err error)
+	// Complete records the response of the order that claimed key.
+	Complete(key string, resp *pb.PlaceOrderResponse)
+	// Release gives up a claim without recording a response, so that a
+	// failed order can be retried with the same key.
+	Release(key string)
+}
+
+// idempotencyEntry is an order in flight, whose done is closed once it has
+// finished, with resp set if it succeeded.
+type idempotencyEntry struct {
+	done chan struct{}
+	resp *pb.PlaceOrderResponse
+}
+
+// memoryIdempotencyStore is an IdempotencyStore that keeps order outcomes in
+// process memory until their TTL expires. Orders in flight are held apart from
+// the outcomes and never expire, however long they take, so that a duplicate
+// always waits for them.
+type memoryIdempotencyStore struct {
+	mu       sync.Mutex
+	inflight map[string]*idempotencyEntry
+	done     *cache.Cache
+	ttl      time.Duration
+}
+
+func newMemoryIdempotencyStore(ttl time.Duration) *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{
+		inflight: make(map[string]*idempotencyEntry),
+		done:     cache.New(ttl, ttl/2),
+		ttl:      ttl,
+	}
+}
+
+func (s *memoryIdempotencyStore) Claim(ctx context.Context, key string) (*pb.PlaceOrderResponse, bool, error) {
+	for {
+		s.mu.Lock()
+		if v, ok := s.done.Get(key); ok {
+			s.mu.Unlock()
+			return v.(*pb.PlaceOrderResponse), false, nil
+		}
+		entry, ok := s.inflight[key]
+		if !ok {
+			s.inflight[key] = &idempotencyEntry{done: make(chan struct{})}
+			s.mu.Unlock()
+			return nil, true, nil
+		}
+		s.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if entry.resp != nil {
+			return entry.resp, false, nil
+		}
+		// released, try to claim it again
+	}
+}
+
+func (s *memoryIdempotencyStore) Complete(key string, resp *pb.PlaceOrderResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.inflight[key]
+	if !ok {
+		return
+	}
+	delete(s.inflight, key)
+	// keep the outcome for a full TTL from when the order finished
+	s.done.Set(key, resp, s.ttl)
+	entry.resp = resp
+	close(entry.done)
+}
+
+func (s *memoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.inflight[key]
+	if !ok {
+		return
+	}
+	delete(s.inflight, key)
+	close(entry.done)
+}
+
+// idempotencyKey returns the idempotency key sent by the client, if any.
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(idempotencyKeyHeader); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// placeOrderOnce runs placeOrder at most once per user and idempotency key.
+// Orders sent without a key are always placed.
+func (cs *checkoutService) placeOrderOnce(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	key := idempotencyKey(ctx)
+	if key == "" || cs.idempotency == nil {
+		return cs.placeOrder(ctx, req)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.idempotency_key", key))
+
+	// keys are scoped to the user so one shopper can never replay another's order
+	storeKey := req.UserId + "/" + key
+	prev, claimed, err := cs.idempotency.Claim(ctx, storeKey)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	span.SetAttributes(attribute.Bool("app.idempotent_replay", !claimed))
+	if !claimed {
+		log.Infof("[PlaceOrder] replaying order %s for idempotency key %q", prev.GetOrder().GetOrderId(), key)
+		return prev, nil
+	}
+
+	resp, err := cs.placeOrder(ctx, req)
+	if err != nil {
+		cs.idempotency.Release(storeKey)
+		return nil, err
+	}
+	cs.idempotency.Complete(storeKey, resp)
+	return resp, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"google.golang.org/grpc/metadata"
+)
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, key))
+}
+
+// TestPlaceOrderConcurrentDuplicates submits the same order many times at once
+// and checks that the card is charged exactly once.
+func TestPlaceOrderConcurrentDuplicates(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+
+	const submissions = 10
+	var wg sync.WaitGroup
+	orderIDs := make([]string, submissions)
+	errs := make([]error, submissions)
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := cs.PlaceOrder(withIdempotencyKey("double-submit"), testOrderRequest())
+			errs[i] = err
+			orderIDs[i] = resp.GetOrder().GetOrderId()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("submission %d failed: %v", i, err)
+		}
+		if orderIDs[i] != orderIDs[0] {
+			t.Errorf("submission %d got order %q, want %q", i, orderIDs[i], orderIDs[0])
+		}
+	}
+	if got := fakes.payment.charges.Load(); got != 1 {
+		t.Errorf("got %d charges, want 1", got)
+	}
+	if got := fakes.shipping.shipped.Load(); got != 1 {
+		t.Errorf("got %d shipments, want 1", got)
+	}
+}
+
+func TestPlaceOrderWithoutKeyIsNotDeduplicated(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+
+	for i := 0; i < 2; i++ {
+		if _, err := cs.PlaceOrder(context.Background(), testOrderRequest()); err != nil {
+			t.Fatal(err)
+		}
+		// the first order empties the cart, put the items back
+		fakes.cart.items["42"] = []*pb.CartItem{{ProductId: "OLJCESPC7Z", Quantity: 1}}
+	}
+	if got := fakes.payment.charges.Load(); got != 2 {
+		t.Errorf("got %d charges, want 2", got)
+	}
+}
+
+func TestPlaceOrderRetryAfterFailure(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	fakes.shipping.shipErr = errors.New("shipping is down")
+
+	if _, err := cs.PlaceOrder(withIdempotencyKey("retry"), testOrderRequest()); err == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+	if got := fakes.payment.refunds.Load(); got != 1 {
+		t.Errorf("got %d refunds after the failed attempt, want 1", got)
+	}
+
+	fakes.shipping.shipErr = nil
+	if _, err := cs.PlaceOrder(withIdempotencyKey("retry"), testOrderRequest()); err != nil {
+		t.Fatalf("retry with the same key failed: %v", err)
+	}
+	if got := fakes.payment.charges.Load(); got != 2 {
+		t.Errorf("got %d charges, want 2", got)
+	}
+}
+
+func TestMemoryIdempotencyStoreRelease(t *testing.T) {
+	s := newMemoryIdempotencyStore(defaultIdempotencyTTL)
+	ctx := context.Background()
+
+	if _, claimed, _ := s.Claim(ctx, "k"); !claimed {
+		t.Fatal("first claim should succeed")
+	}
+	s.Release("k")
+	if _, claimed, _ := s.Claim(ctx, "k"); !claimed {
+		t.Fatal("claim after release should succeed")
+	}
+	want := &pb.PlaceOrderResponse{Order: &pb.OrderResult{OrderId: "order-1"}}
+	s.Complete("k", want)
+	got, claimed, err := s.Claim(ctx, "k")
+	if err != nil || claimed || got != want {
+		t.Errorf("got (%v, %v, %v), want the completed response", got, claimed, err)
+	}
+}
+
+// TestMemoryIdempotencyStoreSlowOrder claims a key for longer than the TTL and
+// checks that a duplicate still waits for the order instead of claiming it.
+func TestMemoryIdempotencyStoreSlowOrder(t *testing.T) {
+	s := newMemoryIdempotencyStore(10 * time.Millisecond)
+	ctx := context.Background()
+
+	if _, claimed, _ := s.Claim(ctx, "k"); !claimed {
+		t.Fatal("first claim should succeed")
+	}
+	// past the TTL and the cache's cleanup
+	time.Sleep(50 * time.Millisecond)
+
+	type result struct {
+		resp    *pb.PlaceOrderResponse
+		claimed bool
+		err     error
+	}
+	dup := make(chan result)
+	go func() {
+		resp, claimed, err := s.Claim(ctx, "k")
+		dup <- result{resp, claimed, err}
+	}()
+	select {
+	case r := <-dup:
+		t.Fatalf("got (%v, %v, %v) for a duplicate of an order in flight, want it to wait", r.resp, r.claimed, r.err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	want := &pb.PlaceOrderResponse{Order: &pb.OrderResult{OrderId: "order-1"}}
+	s.Complete("k", want)
+	select {
+	case r := <-dup:
+		if r.err != nil || r.claimed || r.resp != want {
+			t.Errorf("got (%v, %v, %v), want the completed response", r.resp, r.claimed, r.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the duplicate is still waiting after the order completed")
+	}
+	if got, claimed, _ := s.Claim(ctx, "k"); claimed || got != want {
+		t.Errorf("got (%v, %v) after completing, want the completed response", got, claimed)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPlaceOrderCommitsStock(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	res, err := cs.PlaceOrder(context.Background(), testOrderRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := fakes.inventory.level("OLJCESPC7Z"), fakes.inventory.level("66VCHSJNUP"); a != 9 || b != 8 {
+		t.Errorf("got stock %d and %d after the order, want 9 and 8", a, b)
+	}
+	if !fakes.inventory.committed[res.Order.OrderId] {
+		t.Error("the reservation wasn't committed")
+	}
+}
+
+func TestPlaceOrderOutOfStock(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	fakes.inventory.stock["66VCHSJNUP"] = 1
+
+	_, err := cs.PlaceOrder(context.Background(), testOrderRequest())
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want FailedPrecondition", err)
+	}
+	if got := fakes.payment.charges.Load(); got != 0 {
+		t.Errorf("charged the card %d times for an order that is out of stock", got)
+	}
+	if got := fakes.inventory.level("OLJCESPC7Z"); got != 10 {
+		t.Errorf("got %d of OLJCESPC7Z, want the stock untouched", got)
+	}
+}
+
+func TestPlaceOrderReleasesStockOnFailure(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	fakes.shipping.shipErr = errors.New("shipping is down")
+
+	if _, err := cs.PlaceOrder(context.Background(), testOrderRequest()); err == nil {
+		t.Fatal("expected the order to fail")
+	}
+	if a, b := fakes.inventory.level("OLJCESPC7Z"), fakes.inventory.level("66VCHSJNUP"); a != 10 || b != 10 {
+		t.Errorf("got stock %d and %d after the failed order, want it all released", a, b)
+	}
+	if len(fakes.inventory.reservations) != 0 {
+		t.Errorf("reservations left behind: %v", fakes.inventory.reservations)
+	}
+}
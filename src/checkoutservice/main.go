@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
 	"math/rand"
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
 	"github.com/honeycombio/microservices-demo/src/checkoutservice/money"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/promo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/tax"
+	"github.com/honeycombio/microservices-demo/src/internal/fanout"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
 	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -116,8 +121,21 @@ type checkoutService struct {
 	productCatalogSvcAddr   string
 	productCatalogSvcClient pb.ProductCatalogServiceClient
 
+	inventorySvcAddr   string
+	inventorySvcClient pb.InventoryServiceClient
+
 	shippingSvcAddr   string
 	shippingSvcClient pb.ShippingServiceClient
+
+	idempotency IdempotencyStore
+	orders      OrderRepository
+	exporter    OrderExporter
+	tax         *tax.Calculator
+	promotions  *promo.Engine
+	faults      *faults.Registry
+
+	// fanoutLimit caps how many cart items are looked up at once.
+	fanoutLimit int
 }
 
 func initOtelLogging(ctx context.Context) *sdklog.LoggerProvider {
@@ -204,7 +222,47 @@ func main() {
 		port = os.Getenv("PORT")
 	}
 
+	idempotencyTTL := defaultIdempotencyTTL
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL")); err == nil {
+		idempotencyTTL = ttl
+	}
+
 	svc := new(checkoutService)
+	if n, err := strconv.Atoi(os.Getenv("FANOUT_LIMIT")); err == nil {
+		svc.fanoutLimit = n
+	}
+	svc.idempotency = newMemoryIdempotencyStore(idempotencyTTL)
+	svc.faults, err = newFaultRegistry(os.Getenv("FAULTS_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load fault scenarios: %+v", err)
+	}
+	svc.tax, err = newTaxCalculator(os.Getenv("TAX_RULES"))
+	if err != nil {
+		log.Fatalf("failed to load tax rules: %+v", err)
+	}
+	svc.promotions, err = newPromotionEngine(os.Getenv("PROMOTIONS_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load promotions: %+v", err)
+	}
+	discountDatabaseLookup.Store(os.Getenv("DISCOUNT_DB_LOOKUP") != "false")
+
+	orderStorePath := "orders.db"
+	if os.Getenv("ORDER_STORE_PATH") != "" {
+		orderStorePath = os.Getenv("ORDER_STORE_PATH")
+	}
+	svc.orders, err = newOrderRepository(os.Getenv("ORDER_STORE"), orderStorePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer svc.orders.Close()
+	svc.exporter, err = newOrderExporterFromEnv()
+	if err != nil {
+		log.Fatalf("failed to set up order export: %+v", err)
+	}
+	if svc.exporter != nil {
+		defer svc.exporter.Close()
+	}
+
 	mustMapEnv(&svc.cartSvcAddr, "CART_SERVICE_ADDR")
 	c := mustCreateClientConn(svc.cartSvcAddr)
 	svc.cartSvcClient = pb.NewCartServiceClient(c)
@@ -230,6 +288,15 @@ func main() {
 	svc.productCatalogSvcClient = pb.NewProductCatalogServiceClient(c)
 	defer c.Close()
 
+	// the inventory is served by the product catalog service unless it moves
+	svc.inventorySvcAddr = os.Getenv("INVENTORY_SERVICE_ADDR")
+	if svc.inventorySvcAddr == "" {
+		svc.inventorySvcAddr = svc.productCatalogSvcAddr
+	}
+	c = mustCreateClientConn(svc.inventorySvcAddr)
+	svc.inventorySvcClient = pb.NewInventoryServiceClient(c)
+	defer c.Close()
+
 	mustMapEnv(&svc.shippingSvcAddr, "SHIPPING_SERVICE_ADDR")
 	c = mustCreateClientConn(svc.shippingSvcAddr)
 	svc.shippingSvcClient = pb.NewShippingServiceClient(c)
@@ -247,6 +314,7 @@ func main() {
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 	pb.RegisterCheckoutServiceServer(srv, svc)
+	pb.RegisterAdminServiceServer(srv, newAdminService(os.Getenv("ADMIN_TOKEN"), svc.faults))
 	healthpb.RegisterHealthServer(srv, svc)
 
 	log.Infof("starting to listen on tcp: %q", lis.Addr().String())
@@ -288,10 +356,47 @@ func (cs *checkoutService) GetCacheSize(_ context.Context, _ *pb.Empty) (*pb.Cac
 	}, nil
 }
 
+// ResetCache evicts entries from the request cache until at most
+// req.TargetSize remain, recovering from the leak without a restart.
+func (cs *checkoutService) ResetCache(ctx context.Context, req *pb.ResetCacheRequest) (*pb.CacheSizeResponse, error) {
+	if req.TargetSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "target_size must not be negative")
+	}
+	before := requestCache.ItemCount()
+	if req.TargetSize == 0 {
+		requestCache.Flush()
+	} else {
+		excess := before - int(req.TargetSize)
+		for key := range requestCache.Items() {
+			if excess <= 0 {
+				break
+			}
+			requestCache.Delete(key)
+			excess--
+		}
+	}
+	after := requestCache.ItemCount()
+
+	log.Infof("[ResetCache] cache size went from %d to %d", before, after)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("app.cache_size.before", before),
+		attribute.Int("app.cache_size", after),
+		attribute.Int64("app.cache_size.target", req.TargetSize),
+	)
+	return &pb.CacheSizeResponse{CacheSize: int64(after)}, nil
+}
+
 func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	return cs.placeOrderOnce(ctx, req)
+}
+
+func (cs *checkoutService) placeOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
 	log.Infof("[PlaceOrder] user_id=%q user_currency=%q", req.UserId, req.UserCurrency)
 
 	orderID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate order uuid")
+	}
 
 	var (
 		orderIDKey   = attribute.Key("app.order_id")
@@ -307,18 +412,17 @@ func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderReq
 	requestID := bags.Member("app.request_id").Value()
 	buildId := bags.Member("app.build_id").Value()
 
-	ordCache := &OrderCache{
-		OrderId:   orderID.String(),
-		UserId:    userID,
-		RequestId: requestID,
-		Currency:  req.UserCurrency,
-	}
-
 	// Okay we need to fake some problems some how...
-	cacheIncrease := determineCacheIncrease(requestCache.ItemCount())
-	log.Debugf("increasing cache by: %d", cacheIncrease)
-	for i := 0; i < cacheIncrease; i++ {
-		requestCache.Set(requestID+strconv.Itoa(i), ordCache, cache.NoExpiration)
+	faultErr := cs.faults.Inject(ctx, "checkout.PlaceOrder", faults.Target{
+		UserID:    userID,
+		RequestID: requestID,
+		Attrs: map[string]string{
+			"order_id": orderID.String(),
+			"currency": req.UserCurrency,
+		},
+	})
+	if faultErr != nil {
+		return nil, status.Errorf(codes.Internal, "%+v", faultErr)
 	}
 	cachesize := requestCache.ItemCount()
 	log.Debugf("cachesize: %d", cachesize)
@@ -337,37 +441,74 @@ func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderReq
 		buildIdKey.String(buildId),
 	)
 
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate order uuid")
-	}
+	// Every step below registers how to undo itself, so a failure part way
+	// through unwinds the order instead of leaving it half placed.
+	saga := newOrderSaga(orderID.String())
 
 	// Prepare Order
-	prep, err := cs.prepareOrderItemsAndShippingQuoteFromCart(ctx, req.UserId, req.UserCurrency, req.Address, cachesize)
+	prep, err := cs.prepareOrderItemsAndShippingQuoteFromCart(ctx, req.UserId, req.UserCurrency, req.Address, req.ShippingOptionId)
+	var missing *missingProductsError
+	var unknownOption *unknownShippingOptionError
+	if errors.As(err, &missing) {
+		span.SetAttributes(attribute.StringSlice("app.products.missing", missing.ids))
+		return nil, saga.abort(ctx, "prepare", codes.FailedPrecondition, err)
+	} else if errors.As(err, &unknownOption) {
+		return nil, saga.abort(ctx, "prepare", codes.InvalidArgument, err)
+	} else if err != nil {
+		return nil, saga.abort(ctx, "prepare", codes.Internal, err)
+	}
+	saga.completed("prepare", nil)
+
+	// Apply Promotions
+	prep.discounts, err = cs.getDiscounts(ctx, req, prep, cachesize)
+	if errors.Is(err, promo.ErrUnknownCode) || errors.Is(err, promo.ErrInactive) || errors.Is(err, promo.ErrNotEligible) {
+		return nil, saga.abort(ctx, "discount", codes.InvalidArgument, err)
+	} else if err != nil {
+		return nil, saga.abort(ctx, "discount", codes.Internal, fmt.Errorf("failed to apply promotions: %+v", err))
+	}
+	saga.completed("discount", nil)
+
+	// Calculate Tax
+	taxes, err := cs.calculateTax(ctx, req.Address, prep)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, saga.abort(ctx, "calculate tax", codes.Internal, fmt.Errorf("failed to calculate tax: %+v", err))
 	}
+	saga.completed("calculate tax", nil)
 
-	total := pb.Money{CurrencyCode: req.UserCurrency,
-		Units: 0,
-		Nanos: 0}
-	total = money.Must(money.Sum(total, *prep.shippingCostLocalized))
-	for _, it := range prep.orderItems {
-		multPrice := money.MultiplySlow(*it.Cost, uint32(it.GetItem().GetQuantity()))
-		total = money.Must(money.Sum(total, multPrice))
+	// Reserve Stock, so the card isn't charged for items that can't be sent
+	if err := cs.reserveStock(ctx, orderID.String(), prep.cartItems); status.Code(err) == codes.FailedPrecondition {
+		return nil, saga.abort(ctx, "reserve stock", codes.FailedPrecondition, err)
+	} else if err != nil {
+		return nil, saga.abort(ctx, "reserve stock", codes.Unavailable, err)
 	}
+	saga.completed("reserve stock", func(ctx context.Context) error {
+		return cs.releaseStock(ctx, orderID.String())
+	})
+
+	sum := money.Must(money.Sum(money.Value(taxes.Subtotal), money.Value(prep.shippingCostLocalized)))
+	for _, d := range prep.discounts {
+		sum = money.Must(money.Sum(money.Value(&sum), money.Negate(money.Value(d.Amount))))
+	}
+	sum = money.Must(money.Sum(money.Value(&sum), money.Value(taxes.Tax)))
+	total := &sum
 	span.AddEvent("prepared", trace.WithAttributes(
 		orderIDKey.String(orderID.String()),
 		userIDKey.String(userID),
 		attribute.String("currency", req.UserCurrency),
+		attribute.Int("discounts", len(prep.discounts)),
+		attribute.Float64("tax", moneyToFloat(taxes.Tax)),
 	))
 
 	// Charge Card
-	txID, err := cs.chargeCard(ctx, &total, req.CreditCard)
+	txID, err := cs.chargeCard(ctx, total, req.CreditCard)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to charge card: %+v", err)
+		return nil, saga.abort(ctx, "charge", codes.Internal, fmt.Errorf("failed to charge card: %+v", err))
 	}
+	saga.completed("charge", func(ctx context.Context) error {
+		return cs.refundCharge(ctx, txID, total)
+	})
 	log.Debugf("payment went through (transaction_id: %s)", txID)
-	amt := float64(total.Units) + (float64(total.Nanos) / 100)
+	amt := float64(total.GetUnits()) + (float64(total.GetNanos()) / 100)
 	span.AddEvent("charged", trace.WithAttributes(
 		orderIDKey.String(orderID.String()),
 		userIDKey.String(userID),
@@ -376,10 +517,13 @@ func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderReq
 	))
 
 	// Ship Order
-	shippingTrackingID, err := cs.shipOrder(ctx, req.Address, prep.cartItems)
+	shippingTrackingID, err := cs.shipOrder(ctx, req.Address, prep.cartItems, prep.shippingOption)
 	if err != nil {
-		return nil, status.Errorf(codes.Unavailable, "shipping error: %+v", err)
+		return nil, saga.abort(ctx, "ship", codes.Unavailable, fmt.Errorf("shipping error: %+v", err))
 	}
+	saga.completed("ship", func(ctx context.Context) error {
+		return cs.cancelShipment(ctx, shippingTrackingID)
+	})
 
 	span.AddEvent("shipped", trace.WithAttributes(
 		orderIDKey.String(orderID.String()),
@@ -394,26 +538,45 @@ func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderReq
 		ShippingCost:       prep.shippingCostLocalized,
 		ShippingAddress:    req.Address,
 		Items:              prep.orderItems,
+		PlacedAt:           time.Now().Unix(),
+		Tax:                taxes.Tax,
+		Subtotal:           taxes.Subtotal,
+		Discounts:          appliedDiscounts(prep.discounts),
 	}
 
-	// empty cart async
-	go func() {
-		ctx := trace.ContextWithSpan(context.Background(), span)
-		err := cs.emptyUserCart(ctx, req.UserId)
-		if err != nil {
+	// Record Order
+	if err := cs.orders.Save(ctx, req.UserId, orderResult); err != nil {
+		return nil, saga.abort(ctx, "record order", codes.Internal, err)
+	}
+	saga.completed("record order", func(ctx context.Context) error {
+		return cs.orders.Delete(ctx, req.UserId, orderResult.OrderId)
+	})
 
-		}
-	}()
-
-	// send order confirmation async
-	go func() {
-		ctx := trace.ContextWithSpan(context.Background(), span)
-		if err := cs.sendOrderConfirmation(ctx, req.Email, orderResult); err != nil {
-			log.Warnf("failed to send order confirmation to %q: %+v", req.Email, err)
-		} else {
-			log.Debugf("order confirmation email sent to %q", req.Email)
-		}
-	}()
+	// Empty Cart
+	if err := cs.emptyUserCart(ctx, req.UserId); err != nil {
+		return nil, saga.abort(ctx, "empty cart", codes.Internal, err)
+	}
+	saga.completed("empty cart", func(ctx context.Context) error {
+		return cs.restoreUserCart(ctx, req.UserId, prep.cartItems)
+	})
+
+	// Commit Stock
+	if err := cs.commitStock(ctx, orderID.String()); err != nil {
+		return nil, saga.abort(ctx, "commit stock", codes.Internal, err)
+	}
+	// releasing the reservation puts committed items back in stock too
+	saga.completed("commit stock", nil)
+
+	// Confirm Order. The order is placed by now, so a failed email doesn't
+	// unwind it: it is recorded on the span and in the export instead, so
+	// the email can be sent again later.
+	confirmed := cs.confirmOrder(ctx, req.Email, orderResult)
+	span.SetAttributes(attribute.Bool("app.order.confirmation_sent", confirmed))
+
+	// Export Order
+	rec := newOrderExportRecord(ctx, req, orderResult, prep.products, total, taxes.Rate)
+	rec.ConfirmationSent = confirmed
+	cs.exportOrder(ctx, rec)
 
 	resp := &pb.PlaceOrderResponse{Order: orderResult}
 	return resp, nil
@@ -422,23 +585,11 @@ func (cs *checkoutService) PlaceOrder(ctx context.Context, req *pb.PlaceOrderReq
 type orderPrep struct {
 	orderItems            []*pb.OrderItem
 	cartItems             []*pb.CartItem
+	products              map[string]*pb.Product
 	shippingCostLocalized *pb.Money
-}
-
-func determineCacheIncrease(curSize int) int {
-	if curSize < cacheUserThreshold {
-		// random number between 8 and 9
-		return rand.Intn(2) + 8
-	} else {
-		// we want to go from 9 to 20 (spread of 11) proportionately as we increase to our max of 54000
-		max := 54000
-		spread := max - cacheUserThreshold
-		pos := curSize - cacheUserThreshold
-		rndOffset := float64(pos) / float64(spread) * 10
-
-		// proportional increase + base (9) + random offset (which is also proportional to increase)
-		return ((pos * 11) / spread) + 9 + rand.Intn(int(rndOffset)+1)
-	}
+	// shippingOption is the ID of the shipping option the order is sent with
+	shippingOption string
+	discounts      []promo.Discount
 }
 
 func getRandomWaitTime(max int, buckets int) float32 {
@@ -465,82 +616,84 @@ func mockDatabaseCall(ctx context.Context, maxTime int, name, query string) {
 	sleepRandom(maxTime)
 }
 
-func loadDiscountFromDatabase(ctx context.Context, cachesize int) string {
-	numCalls := math.Max(1, math.Pow(float64(cachesize)/6000, 4)/3000)
-	for i := float64(0); i < numCalls; i++ {
-		mockDatabaseCall(ctx, 250, "SELECT checkout.discounts", "SELECT * FROM discounts WHERE user = ?")
-	}
-
-	discount := rand.Intn(20)
-	return strconv.Itoa(discount)
+// loadDiscountFromDatabase is the slow path of the discount lookup: a query
+// for the user's discounts that the demo turns into an N+1 as the cache grows.
+func loadDiscountFromDatabase(ctx context.Context) {
+	mockDatabaseCall(ctx, 250, "SELECT checkout.discounts", "SELECT * FROM discounts WHERE user = ?")
 }
 
-func getDiscounts(ctx context.Context, u string, cachesize int) string {
-	tracer := otel.GetTracerProvider().Tracer("")
-	ctx, span := tracer.Start(ctx, "getDiscounts")
-	var (
-		userIDKey = attribute.Key("app.user_id")
-	)
-	span.SetAttributes(userIDKey.String(u))
-	defer span.End()
-	rnd := rand.Float32()
-	if (u == "20109" && rnd < 0.5) || (rnd < 0.25) {
-		return loadDiscountFromDatabase(ctx, cachesize)
-	} else {
-		return loadDiscountFromDatabase(ctx, 0)
-	}
-
-}
-
-func (cs *checkoutService) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Context, userID, userCurrency string, address *pb.Address, cachesize int) (orderPrep, error) {
+func (cs *checkoutService) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Context, userID, userCurrency string, address *pb.Address, shippingOptionID string) (orderPrep, error) {
 	var out orderPrep
 	cartItems, err := cs.getUserCart(ctx, userID)
 	if err != nil {
 		return out, fmt.Errorf("cart failure: %+v", err)
 	}
-	orderItems, err := cs.prepOrderItems(ctx, cartItems, userCurrency)
+	orderItems, products, err := cs.prepOrderItems(ctx, cartItems, userCurrency)
 	if err != nil {
-		return out, fmt.Errorf("failed to prepare order: %+v", err)
+		return out, fmt.Errorf("failed to prepare order: %w", err)
 	}
 
-	discount := getDiscounts(ctx, userID, cachesize)
-	if discount != "" {
-		log.Debugf(fmt.Sprintf("Got a discount: %v.", discount))
-	}
-
-	span := trace.SpanFromContext(ctx)
-	span.AddEvent("discounted", trace.WithAttributes(
-		attribute.String("app.user_id", userID),
-		attribute.String("currency", userCurrency),
-		attribute.String("discount", discount),
-	))
-
-	shippingUSD, err := cs.quoteShipping(ctx, address, cartItems)
+	option, err := cs.quoteShipping(ctx, address, cartItems, products, shippingOptionID)
 	if err != nil {
-		return out, fmt.Errorf("shipping quote failure: %+v", err)
+		return out, fmt.Errorf("shipping quote failure: %w", err)
 	}
-	shippingPrice, err := cs.convertCurrency(ctx, shippingUSD, userCurrency)
+	shippingPrice, err := cs.convertCurrency(ctx, option.GetCostUsd(), userCurrency)
 	if err != nil {
 		return out, fmt.Errorf("failed to convert shipping cost to currency: %+v", err)
 	}
 
 	out.shippingCostLocalized = shippingPrice
+	out.shippingOption = option.GetId()
 	out.cartItems = cartItems
 	out.orderItems = orderItems
+	out.products = products
 	sleepRandom(25)
 	return out, nil
 }
 
-func (cs *checkoutService) quoteShipping(ctx context.Context, address *pb.Address, items []*pb.CartItem) (*pb.Money, error) {
+// unknownShippingOptionError is returned for a shipping option the shipping
+// service didn't quote.
+type unknownShippingOptionError struct {
+	id string
+}
+
+func (e *unknownShippingOptionError) Error() string {
+	return fmt.Sprintf("shipping option %q isn't available for this order", e.id)
+}
+
+// quoteShipping quotes shipping the items to address, with their subtotal in
+// USD so the order can qualify for free shipping, and returns the option with
+// the ID, or the default one if the ID is empty.
+func (cs *checkoutService) quoteShipping(ctx context.Context, address *pb.Address, items []*pb.CartItem, products map[string]*pb.Product, optionID string) (*pb.ShippingOption, error) {
+	subtotal := pb.Money{CurrencyCode: "USD"}
+	for _, it := range items {
+		p, ok := products[it.GetProductId()]
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "product %s of the cart wasn't looked up", it.GetProductId())
+		}
+		subtotal = money.Must(money.Sum(money.Value(&subtotal), money.MultiplySlow(money.Value(p.GetPriceUsd()), uint32(it.GetQuantity()))))
+	}
 	shippingQuote, err := cs.shippingSvcClient.
 		GetQuote(ctx, &pb.GetQuoteRequest{
-			Address: address,
-			Items:   items})
+			Address:     address,
+			Items:       items,
+			SubtotalUsd: &subtotal})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get shipping quote: %+v", err)
 	}
 	sleepRandom(40)
-	return shippingQuote.GetCostUsd(), nil
+	span := trace.SpanFromContext(ctx)
+	for _, o := range shippingQuote.GetOptions() {
+		if o.GetId() == optionID || optionID == "" {
+			span.SetAttributes(attribute.String("app.shipping.option_id", o.GetId()))
+			return o, nil
+		}
+	}
+	if optionID == "" {
+		// a shipping service that doesn't quote options only has the default
+		return &pb.ShippingOption{CostUsd: shippingQuote.GetCostUsd()}, nil
+	}
+	return nil, &unknownShippingOptionError{id: optionID}
 }
 
 func (cs *checkoutService) getUserCart(ctx context.Context, userID string) ([]*pb.CartItem, error) {
@@ -568,24 +721,56 @@ func (cs *checkoutService) emptyUserCart(ctx context.Context, userID string) err
 	return nil
 }
 
-func (cs *checkoutService) prepOrderItems(ctx context.Context, items []*pb.CartItem, userCurrency string) ([]*pb.OrderItem, error) {
-	out := make([]*pb.OrderItem, len(items))
+// missingProductsError is returned for a cart with products that are no
+// longer in the catalog.
+type missingProductsError struct {
+	ids []string
+}
+
+func (e *missingProductsError) Error() string {
+	return fmt.Sprintf("products are no longer available: #%s", strings.Join(e.ids, ", #"))
+}
 
+// prepOrderItems prices items in userCurrency. It also returns the catalog
+// entries of the items, keyed by product ID.
+func (cs *checkoutService) prepOrderItems(ctx context.Context, items []*pb.CartItem, userCurrency string) ([]*pb.OrderItem, map[string]*pb.Product, error) {
+	ids := make([]string, len(items))
 	for i, item := range items {
-		product, err := cs.productCatalogSvcClient.GetProduct(ctx, &pb.GetProductRequest{Id: item.GetProductId()})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get product #%q", item.GetProductId())
-		}
-		price, err := cs.convertCurrency(ctx, product.GetPriceUsd(), userCurrency)
+		ids[i] = item.GetProductId()
+	}
+	resp, err := cs.productCatalogSvcClient.GetProducts(ctx, &pb.GetProductsRequest{Ids: ids})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	if len(resp.GetMissingIds()) > 0 {
+		return nil, nil, &missingProductsError{ids: resp.GetMissingIds()}
+	}
+	products := make(map[string]*pb.Product, len(resp.GetProducts()))
+	for _, p := range resp.GetProducts() {
+		products[p.GetId()] = p
+	}
+
+	// convert every item's price at once; each gets its own span so the trace shows them side by side
+	tracer := otel.GetTracerProvider().Tracer("")
+	out, err := fanout.Map(ctx, cs.fanoutLimit, items, func(ctx context.Context, item *pb.CartItem) (*pb.OrderItem, error) {
+		ctx, span := tracer.Start(ctx, "prepOrderItem", trace.WithAttributes(
+			attribute.String("app.product_id", item.GetProductId()),
+			attribute.Int("app.quantity", int(item.GetQuantity())),
+		))
+		defer span.End()
+
+		price, err := cs.convertCurrency(ctx, products[item.GetProductId()].GetPriceUsd(), userCurrency)
 		if err != nil {
+			span.RecordError(err)
 			return nil, fmt.Errorf("failed to convert price of %q to %s", item.GetProductId(), userCurrency)
 		}
-		out[i] = &pb.OrderItem{
-			Item: item,
-			Cost: price}
+		return &pb.OrderItem{Item: item, Cost: price}, nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 	sleepRandom(30)
-	return out, nil
+	return out, products, nil
 }
 
 func (cs *checkoutService) convertCurrency(ctx context.Context, from *pb.Money, toCurrency string) (*pb.Money, error) {
@@ -610,6 +795,26 @@ func (cs *checkoutService) chargeCard(ctx context.Context, amount *pb.Money, pay
 	return paymentResp.GetTransactionId(), nil
 }
 
+// confirmOrder sends the order confirmation email in its own span, recording
+// a failure as a span event.
+// confirmOrder emails the order to the customer and returns whether it was
+// sent. A failure is logged and added to the span as an event.
+func (cs *checkoutService) confirmOrder(ctx context.Context, email string, order *pb.OrderResult) bool {
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "confirmOrder")
+	defer span.End()
+	if err := cs.sendOrderConfirmation(ctx, email, order); err != nil {
+		log.Warnf("failed to send order confirmation to %q: %+v", email, err)
+		span.AddEvent("order confirmation failed", trace.WithAttributes(
+			attribute.String("app.order_id", order.GetOrderId()),
+			attribute.String("error.message", err.Error()),
+		))
+		span.RecordError(err)
+		return false
+	}
+	log.Debugf("order confirmation email sent to %q", email)
+	return true
+}
+
 func (cs *checkoutService) sendOrderConfirmation(ctx context.Context, email string, order *pb.OrderResult) error {
 	_, err := cs.emailSvcClient.SendOrderConfirmation(ctx, &pb.SendOrderConfirmationRequest{
 		Email: email,
@@ -618,10 +823,33 @@ func (cs *checkoutService) sendOrderConfirmation(ctx context.Context, email stri
 	return err
 }
 
-func (cs *checkoutService) shipOrder(ctx context.Context, address *pb.Address, items []*pb.CartItem) (string, error) {
+// reserveStock holds the items of the order until it is committed or
+// released. Items that are out of stock fail with FailedPrecondition.
+func (cs *checkoutService) reserveStock(ctx context.Context, orderID string, items []*pb.CartItem) error {
+	resp, err := cs.inventorySvcClient.ReserveStock(ctx, &pb.ReserveStockRequest{
+		ReservationId: orderID,
+		Items:         items})
+	if err != nil {
+		return fmt.Errorf("could not reserve stock: %w", err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("app.reservation.expires_at", resp.GetExpiresAt()))
+	sleepRandom(20)
+	return nil
+}
+
+func (cs *checkoutService) commitStock(ctx context.Context, orderID string) error {
+	if _, err := cs.inventorySvcClient.CommitReservation(ctx, &pb.ReservationRequest{ReservationId: orderID}); err != nil {
+		return fmt.Errorf("could not commit stock reservation: %w", err)
+	}
+	sleepRandom(20)
+	return nil
+}
+
+func (cs *checkoutService) shipOrder(ctx context.Context, address *pb.Address, items []*pb.CartItem, optionID string) (string, error) {
 	resp, err := cs.shippingSvcClient.ShipOrder(ctx, &pb.ShipOrderRequest{
-		Address: address,
-		Items:   items})
+		Address:          address,
+		Items:            items,
+		ShippingOptionId: optionID})
 	if err != nil {
 		return "", fmt.Errorf("shipment failed: %+v", err)
 	}
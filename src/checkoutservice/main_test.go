@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/promo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/tax"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The fake backends below implement just the RPCs that PlaceOrder uses.
+// Calling anything else panics on the nil embedded client.
+
+type fakeCart struct {
+	pb.CartServiceClient
+	mu       sync.Mutex
+	items    map[string][]*pb.CartItem
+	emptyErr error
+}
+
+func (f *fakeCart) GetCart(_ context.Context, in *pb.GetCartRequest, _ ...grpc.CallOption) (*pb.Cart, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &pb.Cart{UserId: in.UserId, Items: f.items[in.UserId]}, nil
+}
+
+func (f *fakeCart) AddItem(_ context.Context, in *pb.AddItemRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[in.UserId] = append(f.items[in.UserId], in.Item)
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeCart) EmptyCart(_ context.Context, in *pb.EmptyCartRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.emptyErr != nil {
+		return nil, f.emptyErr
+	}
+	delete(f.items, in.UserId)
+	return &pb.Empty{}, nil
+}
+
+type fakeCatalog struct {
+	pb.ProductCatalogServiceClient
+	products map[string]*pb.Product
+	// delay is how long each lookup takes, for benchmarks
+	delay   time.Duration
+	batches atomic.Int32
+}
+
+func (f *fakeCatalog) GetProducts(_ context.Context, in *pb.GetProductsRequest, _ ...grpc.CallOption) (*pb.GetProductsResponse, error) {
+	time.Sleep(f.delay)
+	f.batches.Add(1)
+	res := &pb.GetProductsResponse{}
+	for _, id := range in.Ids {
+		if p, ok := f.products[id]; ok {
+			res.Products = append(res.Products, p)
+		} else {
+			res.MissingIds = append(res.MissingIds, id)
+		}
+	}
+	return res, nil
+}
+
+type fakeCurrency struct {
+	pb.CurrencyServiceClient
+	delay time.Duration
+}
+
+func (f *fakeCurrency) Convert(_ context.Context, in *pb.CurrencyConversionRequest, _ ...grpc.CallOption) (*pb.Money, error) {
+	time.Sleep(f.delay)
+	return &pb.Money{CurrencyCode: in.ToCode, Units: in.From.GetUnits(), Nanos: in.From.GetNanos()}, nil
+}
+
+// fakeShipping quotes standard shipping for 8.99 and express for 19.99.
+type fakeShipping struct {
+	pb.ShippingServiceClient
+	shipped   atomic.Int32
+	cancelled atomic.Int32
+	shipErr   error
+	// quoted and shippedWith are the last quote request and shipping option
+	quoted      atomic.Pointer[pb.GetQuoteRequest]
+	shippedWith atomic.Value
+}
+
+func (f *fakeShipping) GetQuote(_ context.Context, in *pb.GetQuoteRequest, _ ...grpc.CallOption) (*pb.GetQuoteResponse, error) {
+	f.quoted.Store(in)
+	standard := &pb.Money{CurrencyCode: "USD", Units: 8, Nanos: 990000000}
+	return &pb.GetQuoteResponse{
+		CostUsd: standard,
+		Options: []*pb.ShippingOption{
+			{Id: "standard", Name: "Standard", Carrier: "Parcel Post", CostUsd: standard, BusinessDays: 5},
+			{Id: "express", Name: "Express", Carrier: "Parcel Post", CostUsd: &pb.Money{CurrencyCode: "USD", Units: 19, Nanos: 990000000}, BusinessDays: 2},
+		},
+	}, nil
+}
+
+func (f *fakeShipping) ShipOrder(_ context.Context, in *pb.ShipOrderRequest, _ ...grpc.CallOption) (*pb.ShipOrderResponse, error) {
+	if f.shipErr != nil {
+		return nil, f.shipErr
+	}
+	f.shippedWith.Store(in.ShippingOptionId)
+	f.shipped.Add(1)
+	return &pb.ShipOrderResponse{TrackingId: "TR-ACKING-ID"}, nil
+}
+
+func (f *fakeShipping) CancelShipment(_ context.Context, _ *pb.CancelShipmentRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	f.cancelled.Add(1)
+	return &pb.Empty{}, nil
+}
+
+type fakePayment struct {
+	pb.PaymentServiceClient
+	charges   atomic.Int32
+	refunds   atomic.Int32
+	chargeErr error
+}
+
+func (f *fakePayment) Charge(_ context.Context, _ *pb.ChargeRequest, _ ...grpc.CallOption) (*pb.ChargeResponse, error) {
+	if f.chargeErr != nil {
+		return nil, f.chargeErr
+	}
+	f.charges.Add(1)
+	return &pb.ChargeResponse{TransactionId: "txn"}, nil
+}
+
+func (f *fakePayment) Refund(_ context.Context, _ *pb.RefundRequest, _ ...grpc.CallOption) (*pb.RefundResponse, error) {
+	f.refunds.Add(1)
+	return &pb.RefundResponse{RefundId: "refund"}, nil
+}
+
+type fakeEmail struct {
+	pb.EmailServiceClient
+	err  error
+	sent chan struct{}
+}
+
+func (f *fakeEmail) SendOrderConfirmation(_ context.Context, _ *pb.SendOrderConfirmationRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	if f.sent != nil {
+		defer func() { f.sent <- struct{}{} }()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.Empty{}, nil
+}
+
+// fakeInventory keeps stock like the inventory service, without expiry.
+type fakeInventory struct {
+	pb.InventoryServiceClient
+	mu           sync.Mutex
+	stock        map[string]int32
+	reservations map[string][]*pb.CartItem
+	committed    map[string]bool
+	commitErr    error
+}
+
+func (f *fakeInventory) ReserveStock(_ context.Context, in *pb.ReserveStockRequest, _ ...grpc.CallOption) (*pb.ReserveStockResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, it := range in.Items {
+		if f.stock[it.ProductId] < it.Quantity {
+			return nil, status.Errorf(codes.FailedPrecondition, "only %d of product %s available", f.stock[it.ProductId], it.ProductId)
+		}
+	}
+	for _, it := range in.Items {
+		f.stock[it.ProductId] -= it.Quantity
+	}
+	f.reservations[in.ReservationId] = in.Items
+	return &pb.ReserveStockResponse{ExpiresAt: time.Now().Add(time.Minute).Unix()}, nil
+}
+
+func (f *fakeInventory) CommitReservation(_ context.Context, in *pb.ReservationRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.commitErr != nil {
+		return nil, f.commitErr
+	}
+	if _, ok := f.reservations[in.ReservationId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown reservation %s", in.ReservationId)
+	}
+	f.committed[in.ReservationId] = true
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeInventory) ReleaseReservation(_ context.Context, in *pb.ReservationRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, it := range f.reservations[in.ReservationId] {
+		f.stock[it.ProductId] += it.Quantity
+	}
+	delete(f.reservations, in.ReservationId)
+	delete(f.committed, in.ReservationId)
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeInventory) level(productID string) int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stock[productID]
+}
+
+type fakeBackends struct {
+	cart      *fakeCart
+	catalog   *fakeCatalog
+	shipping  *fakeShipping
+	payment   *fakePayment
+	email     *fakeEmail
+	inventory *fakeInventory
+}
+
+// newTestCheckoutService returns a checkoutService wired to fake backends,
+// with user "42" holding two products in their cart and 10 of each in stock.
+func newTestCheckoutService() (*checkoutService, *fakeBackends) {
+	fakes := &fakeBackends{
+		cart: &fakeCart{items: map[string][]*pb.CartItem{
+			"42": {{ProductId: "OLJCESPC7Z", Quantity: 1}, {ProductId: "66VCHSJNUP", Quantity: 2}},
+		}},
+		catalog: &fakeCatalog{products: map[string]*pb.Product{
+			"OLJCESPC7Z": {Id: "OLJCESPC7Z", Name: "Sunglasses", Categories: []string{"vintage"}, PriceUsd: &pb.Money{CurrencyCode: "USD", Units: 67, Nanos: 990000000}},
+			"66VCHSJNUP": {Id: "66VCHSJNUP", Name: "Tank Top", Categories: []string{"clothing"}, PriceUsd: &pb.Money{CurrencyCode: "USD", Units: 12, Nanos: 490000000}},
+		}},
+		shipping: &fakeShipping{},
+		payment:  &fakePayment{},
+		email:    &fakeEmail{},
+		inventory: &fakeInventory{
+			stock:        map[string]int32{"OLJCESPC7Z": 10, "66VCHSJNUP": 10},
+			reservations: map[string][]*pb.CartItem{},
+			committed:    map[string]bool{},
+		},
+	}
+	noFaults, _ := faults.NewRegistry(nil)
+	taxes, _ := tax.NewCalculator(defaultTaxRules())
+	promotions, _ := promo.NewEngine(defaultPromotions())
+	cs := &checkoutService{
+		cartSvcClient:           fakes.cart,
+		currencySvcClient:       &fakeCurrency{},
+		emailSvcClient:          fakes.email,
+		paymentSvcClient:        fakes.payment,
+		productCatalogSvcClient: fakes.catalog,
+		shippingSvcClient:       fakes.shipping,
+		inventorySvcClient:      fakes.inventory,
+		idempotency:             newMemoryIdempotencyStore(defaultIdempotencyTTL),
+		orders:                  newMemoryOrderRepository(),
+		tax:                     taxes,
+		promotions:              promotions,
+		faults:                  noFaults,
+	}
+	return cs, fakes
+}
+
+func testOrderRequest() *pb.PlaceOrderRequest {
+	return &pb.PlaceOrderRequest{
+		UserId:       "42",
+		UserCurrency: "USD",
+		Email:        "someone@example.com",
+		Address: &pb.Address{
+			StreetAddress: "1600 Amphitheatre Parkway",
+			City:          "Mountain View",
+			State:         "CA",
+			Country:       "United States",
+			ZipCode:       94043,
+		},
+		CreditCard: &pb.CreditCardInfo{
+			CreditCardNumber:          "4432-8015-6152-0454",
+			CreditCardCvv:             672,
+			CreditCardExpirationYear:  2030,
+			CreditCardExpirationMonth: 1,
+		},
+	}
+}
@@ -148,6 +148,16 @@ func TestAreEquals(t *testing.T) {
 	}
 }
 
+func TestValue(t *testing.T) {
+	in := &pb.Money{Units: 3, Nanos: 50, CurrencyCode: "XXX"}
+	if got := Value(in); !AreEquals(got, mmc(3, 50, "XXX")) {
+		t.Errorf("Value(%v) = %v", in, got)
+	}
+	if got := Value(nil); !AreEquals(got, mm(0, 0)) {
+		t.Errorf("Value(nil) = %v, want zero", got)
+	}
+}
+
 func TestNegate(t *testing.T) {
 	tests := []struct {
 		name string
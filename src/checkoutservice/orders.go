@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var errOrderNotFound = errors.New("order not found")
+
+// OrderRepository persists placed orders so they can be looked up after
+// PlaceOrder has returned.
+type OrderRepository interface {
+	Save(ctx context.Context, userID string, order *pb.OrderResult) error
+	// Get returns errOrderNotFound unless the order exists and belongs to userID.
+	Get(ctx context.Context, userID, orderID string) (*pb.OrderResult, error)
+	// ListByUser returns the user's orders, most recently placed first.
+	ListByUser(ctx context.Context, userID string) ([]*pb.OrderResult, error)
+	Delete(ctx context.Context, userID, orderID string) error
+	Close() error
+}
+
+// newOrderRepository creates the repository named by kind, "bolt" (the
+// default) or "memory".
+func newOrderRepository(kind, path string) (OrderRepository, error) {
+	switch kind {
+	case "memory":
+		return newMemoryOrderRepository(), nil
+	case "", "bolt":
+		return newBoltOrderRepository(path)
+	default:
+		return nil, fmt.Errorf("unknown order store %q", kind)
+	}
+}
+
+type memoryOrderRepository struct {
+	lock   sync.RWMutex
+	orders map[string]*pb.OrderResult
+	owners map[string]string
+	byUser map[string][]string
+}
+
+func newMemoryOrderRepository() *memoryOrderRepository {
+	return &memoryOrderRepository{
+		orders: make(map[string]*pb.OrderResult),
+		owners: make(map[string]string),
+		byUser: make(map[string][]string),
+	}
+}
+
+func (r *memoryOrderRepository) Save(_ context.Context, userID string, order *pb.OrderResult) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.orders[order.OrderId]; !ok {
+		r.byUser[userID] = append(r.byUser[userID], order.OrderId)
+	}
+	r.orders[order.OrderId] = proto.Clone(order).(*pb.OrderResult)
+	r.owners[order.OrderId] = userID
+	return nil
+}
+
+func (r *memoryOrderRepository) Get(_ context.Context, userID, orderID string) (*pb.OrderResult, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	order, ok := r.orders[orderID]
+	if !ok || r.owners[orderID] != userID {
+		return nil, errOrderNotFound
+	}
+	return proto.Clone(order).(*pb.OrderResult), nil
+}
+
+func (r *memoryOrderRepository) ListByUser(_ context.Context, userID string) ([]*pb.OrderResult, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	ids := r.byUser[userID]
+	out := make([]*pb.OrderResult, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, proto.Clone(r.orders[id]).(*pb.OrderResult))
+	}
+	sortNewestFirst(out)
+	return out, nil
+}
+
+func (r *memoryOrderRepository) Delete(_ context.Context, userID, orderID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.owners[orderID] != userID {
+		return errOrderNotFound
+	}
+	delete(r.orders, orderID)
+	delete(r.owners, orderID)
+	ids := r.byUser[userID]
+	for i, id := range ids {
+		if id == orderID {
+			r.byUser[userID] = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryOrderRepository) Close() error { return nil }
+
+var (
+	// ordersBucket maps order ID to the marshaled OrderResult.
+	ordersBucket = []byte("orders")
+	// usersBucket holds one nested bucket per user, keyed by the IDs of their orders.
+	usersBucket = []byte("users")
+)
+
+// boltOrderRepository stores orders in an embedded BoltDB file so that they
+// survive restarts of the checkout service.
+type boltOrderRepository struct {
+	db *bolt.DB
+}
+
+func newBoltOrderRepository(path string) (*boltOrderRepository, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(ordersBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(usersBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize order store %s: %w", path, err)
+	}
+	return &boltOrderRepository{db: db}, nil
+}
+
+// startSpan starts a span describing a BoltDB operation on the orders buckets.
+func (r *boltOrderRepository) startSpan(ctx context.Context, operation, statement string) (context.Context, trace.Span) {
+	tracer := otel.GetTracerProvider().Tracer("")
+	ctx, span := tracer.Start(ctx, operation+" checkout.orders", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "boltdb"),
+		attribute.String("db.name", "checkout"),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", statement),
+	)
+	return ctx, span
+}
+
+func endOrderSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, errOrderNotFound) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *boltOrderRepository) Save(ctx context.Context, userID string, order *pb.OrderResult) (err error) {
+	_, span := r.startSpan(ctx, "INSERT", "PUT orders/{order_id}, users/{user_id}/{order_id}")
+	defer func() { endOrderSpan(span, err) }()
+
+	b, err := proto.Marshal(order)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(ordersBucket).Put([]byte(order.OrderId), b); err != nil {
+			return err
+		}
+		user, err := tx.Bucket(usersBucket).CreateBucketIfNotExists([]byte(userID))
+		if err != nil {
+			return err
+		}
+		return user.Put([]byte(order.OrderId), nil)
+	})
+}
+
+func (r *boltOrderRepository) Get(ctx context.Context, userID, orderID string) (order *pb.OrderResult, err error) {
+	_, span := r.startSpan(ctx, "SELECT", "GET users/{user_id}/{order_id}, orders/{order_id}")
+	defer func() { endOrderSpan(span, err) }()
+
+	err = r.db.View(func(tx *bolt.Tx) error {
+		user := tx.Bucket(usersBucket).Bucket([]byte(userID))
+		if user == nil || user.Get([]byte(orderID)) == nil {
+			return errOrderNotFound
+		}
+		order, err = getBoltOrder(tx, []byte(orderID))
+		return err
+	})
+	return order, err
+}
+
+func (r *boltOrderRepository) ListByUser(ctx context.Context, userID string) (orders []*pb.OrderResult, err error) {
+	_, span := r.startSpan(ctx, "SELECT", "SCAN users/{user_id}, GET orders/{order_id}")
+	defer func() { endOrderSpan(span, err) }()
+
+	err = r.db.View(func(tx *bolt.Tx) error {
+		user := tx.Bucket(usersBucket).Bucket([]byte(userID))
+		if user == nil {
+			return nil
+		}
+		return user.ForEach(func(orderID, _ []byte) error {
+			order, err := getBoltOrder(tx, orderID)
+			if err != nil {
+				return err
+			}
+			orders = append(orders, order)
+			return nil
+		})
+	})
+	sortNewestFirst(orders)
+	span.SetAttributes(attribute.Int("db.rows", len(orders)))
+	return orders, err
+}
+
+func (r *boltOrderRepository) Delete(ctx context.Context, userID, orderID string) (err error) {
+	_, span := r.startSpan(ctx, "DELETE", "DELETE orders/{order_id}, users/{user_id}/{order_id}")
+	defer func() { endOrderSpan(span, err) }()
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		user := tx.Bucket(usersBucket).Bucket([]byte(userID))
+		if user == nil || user.Get([]byte(orderID)) == nil {
+			return errOrderNotFound
+		}
+		if err := user.Delete([]byte(orderID)); err != nil {
+			return err
+		}
+		return tx.Bucket(ordersBucket).Delete([]byte(orderID))
+	})
+}
+
+func (r *boltOrderRepository) Close() error {
+	return r.db.Close()
+}
+
+func getBoltOrder(tx *bolt.Tx, orderID []byte) (*pb.OrderResult, error) {
+	b := tx.Bucket(ordersBucket).Get(orderID)
+	if b == nil {
+		return nil, errOrderNotFound
+	}
+	order := new(pb.OrderResult)
+	if err := proto.Unmarshal(b, order); err != nil {
+		return nil, fmt.Errorf("corrupt order %s: %w", orderID, err)
+	}
+	return order, nil
+}
+
+func sortNewestFirst(orders []*pb.OrderResult) {
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].PlacedAt > orders[j].PlacedAt
+	})
+}
+
+func (cs *checkoutService) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.OrderResult, error) {
+	if req.UserId == "" || req.OrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("app.user_id", req.UserId),
+		attribute.String("app.order_id", req.OrderId),
+	)
+
+	order, err := cs.orders.Get(ctx, req.UserId, req.OrderId)
+	if errors.Is(err, errOrderNotFound) {
+		return nil, status.Errorf(codes.NotFound, "no order with ID %s", req.OrderId)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load order: %+v", err)
+	}
+	return order, nil
+}
+
+func (cs *checkoutService) ListOrdersByUser(ctx context.Context, req *pb.ListOrdersByUserRequest) (*pb.ListOrdersByUserResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id is required")
+	}
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.user_id", req.UserId))
+
+	orders, err := cs.orders.ListByUser(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list orders: %+v", err)
+	}
+	span.SetAttributes(attribute.Int("app.orders.count", len(orders)))
+	return &pb.ListOrdersByUserResponse{Orders: orders}, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+)
+
+func testOrderRepository(t *testing.T, repo OrderRepository) {
+	ctx := context.Background()
+	for i, id := range []string{"first", "second", "third"} {
+		if err := repo.Save(ctx, "42", &pb.OrderResult{OrderId: id, PlacedAt: int64(1000 + i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Save(ctx, "7", &pb.OrderResult{OrderId: "other", PlacedAt: 999}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(ctx, "42", "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OrderId != "second" {
+		t.Errorf("got order %q, want %q", got.OrderId, "second")
+	}
+	if _, err := repo.Get(ctx, "42", "other"); !errors.Is(err, errOrderNotFound) {
+		t.Errorf("got %v for another user's order, want errOrderNotFound", err)
+	}
+
+	if err := repo.Delete(ctx, "42", "first"); err != nil {
+		t.Fatal(err)
+	}
+	orders, err := repo.ListByUser(ctx, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, o := range orders {
+		ids = append(ids, o.OrderId)
+	}
+	if len(ids) != 2 || ids[0] != "third" || ids[1] != "second" {
+		t.Errorf("got orders %v, want [third second]", ids)
+	}
+}
+
+func TestMemoryOrderRepository(t *testing.T) {
+	testOrderRepository(t, newMemoryOrderRepository())
+}
+
+func TestBoltOrderRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	repo, err := newBoltOrderRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testOrderRepository(t, repo)
+	if err := repo.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// orders survive reopening the file
+	repo, err = newBoltOrderRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	if _, err := repo.Get(context.Background(), "7", "other"); err != nil {
+		t.Errorf("order was not persisted: %v", err)
+	}
+}
+
+func TestPlaceOrderIsRecorded(t *testing.T) {
+	cs, _ := newTestCheckoutService()
+	ctx := context.Background()
+
+	resp, err := cs.PlaceOrder(ctx, testOrderRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cs.GetOrder(ctx, &pb.GetOrderRequest{UserId: "42", OrderId: resp.Order.OrderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ShippingTrackingId != resp.Order.ShippingTrackingId {
+		t.Errorf("got tracking ID %q, want %q", got.ShippingTrackingId, resp.Order.ShippingTrackingId)
+	}
+	list, err := cs.ListOrdersByUser(ctx, &pb.ListOrdersByUserRequest{UserId: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Orders) != 1 {
+		t.Errorf("got %d orders, want 1", len(list.Orders))
+	}
+}
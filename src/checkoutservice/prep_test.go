@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bigCart returns n cart items, cycling through the fake catalog's products.
+func bigCart(n int) []*pb.CartItem {
+	ids := []string{"OLJCESPC7Z", "66VCHSJNUP"}
+	items := make([]*pb.CartItem, n)
+	for i := range items {
+		items[i] = &pb.CartItem{ProductId: ids[i%len(ids)], Quantity: int32(i + 1)}
+	}
+	return items
+}
+
+func TestPrepOrderItemsKeepsCartOrder(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	cs.currencySvcClient = &fakeCurrency{delay: time.Millisecond}
+	cs.fanoutLimit = 3
+
+	items := bigCart(7)
+	out, products, err := cs.prepOrderItems(context.Background(), items, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, it := range out {
+		if it.GetItem() != items[i] {
+			t.Errorf("item %d is %v, want %v", i, it.GetItem(), items[i])
+		}
+		if it.GetCost().GetCurrencyCode() != "EUR" {
+			t.Errorf("item %d costs %v, want EUR", i, it.GetCost())
+		}
+	}
+	if len(products) != 2 || products["66VCHSJNUP"].GetName() != "Tank Top" {
+		t.Errorf("got products %v", products)
+	}
+	if got := fakes.catalog.batches.Load(); got != 1 {
+		t.Errorf("looked the products up in %d calls, want 1", got)
+	}
+}
+
+func TestPlaceOrderFailsOnMissingProducts(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+	fakes.cart.items["42"] = append(bigCart(2),
+		&pb.CartItem{ProductId: "GONE1", Quantity: 1},
+		&pb.CartItem{ProductId: "GONE2", Quantity: 1})
+
+	_, err := cs.PlaceOrder(context.Background(), testOrderRequest())
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want FailedPrecondition", err)
+	}
+	if msg := status.Convert(err).Message(); !strings.Contains(msg, "#GONE1, #GONE2") {
+		t.Errorf("error %q doesn't name the missing products", msg)
+	}
+	if fakes.payment.charges.Load() != 0 {
+		t.Error("charged for an order with missing products")
+	}
+}
+
+func TestPrepOrderItemsSpansRunInParallel(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	cs, _ := newTestCheckoutService()
+	cs.currencySvcClient = &fakeCurrency{delay: 20 * time.Millisecond}
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "PlaceOrder")
+	if _, _, err := cs.prepOrderItems(ctx, bigCart(4), "USD"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		if s.Name() == "prepOrderItem" {
+			spans = append(spans, s)
+		}
+	}
+	if len(spans) != 4 {
+		t.Fatalf("got %d prepOrderItem spans, want 4", len(spans))
+	}
+	// every item span is a child of the request and overlaps all the others
+	for _, a := range spans {
+		if a.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s has parent %s, want the request", a.SpanContext().SpanID(), a.Parent().SpanID())
+		}
+		for _, b := range spans {
+			if a.StartTime().After(b.EndTime()) {
+				t.Errorf("span %s started after %s ended", a.SpanContext().SpanID(), b.SpanContext().SpanID())
+			}
+		}
+	}
+}
+
+// BenchmarkPrepOrderItems prepares a ten item cart against backends that take
+// 2ms a call, converting prices one at a time and with the default limit.
+func BenchmarkPrepOrderItems(b *testing.B) {
+	for _, limit := range []int{1, 0} {
+		name := fmt.Sprintf("limit=%d", limit)
+		if limit == 0 {
+			name = "limit=default"
+		}
+		b.Run(name, func(b *testing.B) {
+			cs, fakes := newTestCheckoutService()
+			fakes.catalog.delay = 2 * time.Millisecond
+			cs.currencySvcClient = &fakeCurrency{delay: 2 * time.Millisecond}
+			cs.fanoutLimit = limit
+			items := bigCart(10)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := cs.prepOrderItems(context.Background(), items, "EUR"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceOrderShippingOption(t *testing.T) {
+	for option, want := range map[string]struct {
+		cost    string
+		shipped string
+	}{
+		"":        {"8.99", "standard"},
+		"express": {"19.99", "express"},
+	} {
+		cs, fakes := newTestCheckoutService()
+		req := testOrderRequest()
+		req.ShippingOptionId = option
+		res, err := cs.PlaceOrder(context.Background(), req)
+		if err != nil {
+			t.Fatalf("option %q: %v", option, err)
+		}
+		cost := res.Order.ShippingCost
+		if got := fmt.Sprintf("%d.%02d", cost.GetUnits(), cost.GetNanos()/10000000); got != want.cost {
+			t.Errorf("option %q: shipping cost %s, want %s", option, got, want.cost)
+		}
+		if got, _ := fakes.shipping.shippedWith.Load().(string); got != want.shipped {
+			t.Errorf("option %q: shipped with %q, want %q", option, got, want.shipped)
+		}
+		// one at 67.99 and two at 12.49
+		if sub := fakes.shipping.quoted.Load().GetSubtotalUsd(); sub.GetUnits() != 92 || sub.GetNanos() != 970000000 {
+			t.Errorf("option %q: quoted with a subtotal of %v, want 92.97", option, sub)
+		}
+	}
+
+	cs, fakes := newTestCheckoutService()
+	req := testOrderRequest()
+	req.ShippingOptionId = "teleport"
+	if _, err := cs.PlaceOrder(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for an unknown shipping option, want InvalidArgument", err)
+	}
+	if got := fakes.payment.charges.Load(); got != 0 {
+		t.Errorf("charged the card %d times for an unknown shipping option", got)
+	}
+}
+
+func TestQuoteShippingNeedsEveryProduct(t *testing.T) {
+	cs, _ := newTestCheckoutService()
+	_, err := cs.quoteShipping(context.Background(), testOrderRequest().Address, bigCart(2), map[string]*pb.Product{}, "")
+	if status.Code(err) != codes.Internal {
+		t.Errorf("got %v for a cart whose products weren't looked up, want Internal", err)
+	}
+}
@@ -0,0 +1,388 @@
+// Package promo applies promotions and coupon codes to orders.
+//
+// A promotion takes a percentage or a fixed amount off the items it covers,
+// or makes every Buy+Get units of an item cost Buy. It can be limited to
+// product categories or products, require a minimum spend, and run only
+// between two dates. Promotions without a code apply to every order that
+// qualifies; the others apply when the customer enters their code.
+package promo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/money"
+)
+
+// Kind identifies how a promotion discounts an order.
+type Kind string
+
+const (
+	KindPercentage  Kind = "percentage"
+	KindFixedAmount Kind = "fixed_amount"
+	KindBuyXGetY    Kind = "buy_x_get_y"
+)
+
+const nanosPerCent = 10000000
+
+var (
+	// ErrUnknownCode is returned for a code that no promotion has.
+	ErrUnknownCode = errors.New("unknown promo code")
+	// ErrInactive is returned for a code whose promotion hasn't started or has expired.
+	ErrInactive = errors.New("promo code is not active")
+	// ErrNotEligible is returned for a code that doesn't apply to the order.
+	ErrNotEligible = errors.New("order is not eligible for promo code")
+)
+
+// Promotion is the configuration of one promotion. Amounts are in any
+// currency, and are converted to the currency of the order.
+type Promotion struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Kind        Kind   `json:"kind"`
+	// Code is the coupon code that applies the promotion, matched ignoring
+	// case. Promotions without one apply automatically.
+	Code string `json:"code,omitempty"`
+
+	// percentage
+	Percent float64 `json:"percent,omitempty"`
+	// fixed_amount
+	Amount *pb.Money `json:"amount,omitempty"`
+	// buy_x_get_y: of every Buy+Get units of an item, Get are free.
+	Buy int32 `json:"buy,omitempty"`
+	Get int32 `json:"get,omitempty"`
+
+	// Categories and Products limit the items the promotion covers. An item
+	// is covered if it is in any of them, or always if both are empty.
+	Categories []string `json:"categories,omitempty"`
+	Products   []string `json:"products,omitempty"`
+	// MinSpend is the order subtotal the promotion needs.
+	MinSpend *pb.Money `json:"min_spend,omitempty"`
+	Starts   time.Time `json:"starts,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"`
+}
+
+// Validate reports whether the promotion can be applied.
+func (p Promotion) Validate() error {
+	if p.ID == "" {
+		return errors.New("promotion needs an id")
+	}
+	switch p.Kind {
+	case KindPercentage:
+		if p.Percent <= 0 || p.Percent > 100 {
+			return fmt.Errorf("promotion %q: percent must be above 0 and at most 100", p.ID)
+		}
+	case KindFixedAmount:
+		if p.Amount == nil || !money.IsValid(money.Value(p.Amount)) || !money.IsPositive(money.Value(p.Amount)) {
+			return fmt.Errorf("promotion %q: amount must be positive", p.ID)
+		}
+	case KindBuyXGetY:
+		if p.Buy <= 0 || p.Get <= 0 {
+			return fmt.Errorf("promotion %q: buy and get must be positive", p.ID)
+		}
+	default:
+		return fmt.Errorf("promotion %q: unknown kind %q", p.ID, p.Kind)
+	}
+	if p.MinSpend != nil && (!money.IsValid(money.Value(p.MinSpend)) || money.IsNegative(money.Value(p.MinSpend))) {
+		return fmt.Errorf("promotion %q: min_spend is invalid", p.ID)
+	}
+	if !p.Starts.IsZero() && !p.Expires.IsZero() && !p.Expires.After(p.Starts) {
+		return fmt.Errorf("promotion %q: expires before it starts", p.ID)
+	}
+	return nil
+}
+
+// Active reports whether the promotion runs at t.
+func (p Promotion) Active(t time.Time) bool {
+	return (p.Starts.IsZero() || !t.Before(p.Starts)) && (p.Expires.IsZero() || t.Before(p.Expires))
+}
+
+func (p Promotion) covers(l *Line) bool {
+	if len(p.Categories) == 0 && len(p.Products) == 0 {
+		return true
+	}
+	for _, id := range p.Products {
+		if id == l.ProductID {
+			return true
+		}
+	}
+	for _, c := range l.Categories {
+		for _, pc := range p.Categories {
+			if strings.EqualFold(c, pc) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Config is the format of a promotions file.
+type Config struct {
+	Promotions []Promotion `json:"promotions"`
+}
+
+// LoadConfig reads a promotions file. The promotions are validated when they
+// are given to NewEngine.
+func LoadConfig(path string) ([]Promotion, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c.Promotions, nil
+}
+
+// Line is an order item.
+type Line struct {
+	ProductID  string
+	Categories []string
+	UnitPrice  *pb.Money
+	Quantity   int32
+}
+
+// Converter converts an amount from the promotions config to the currency of the order.
+type Converter func(*pb.Money) (*pb.Money, error)
+
+// Discount is a promotion applied to an order.
+type Discount struct {
+	PromotionID string
+	Code        string
+	Description string
+	Amount      *pb.Money
+	// ByProduct splits Amount between the products it was taken off.
+	ByProduct map[string]*pb.Money
+}
+
+// ProductIDs returns the products the discount was taken off, sorted.
+func (d Discount) ProductIDs() []string {
+	ids := make([]string, 0, len(d.ByProduct))
+	for id := range d.ByProduct {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Engine applies a set of promotions. It is safe for concurrent use.
+type Engine struct {
+	promotions []Promotion
+	codes      map[string]int
+}
+
+// NewEngine returns an Engine for the given promotions. Automatic promotions
+// apply in the order given, before the promotion of a code.
+func NewEngine(promotions []Promotion) (*Engine, error) {
+	e := &Engine{promotions: promotions, codes: map[string]int{}}
+	ids := map[string]bool{}
+	for i, p := range promotions {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		if ids[p.ID] {
+			return nil, fmt.Errorf("promotion %q is defined more than once", p.ID)
+		}
+		ids[p.ID] = true
+		if p.Code == "" {
+			continue
+		}
+		code := strings.ToUpper(p.Code)
+		if _, ok := e.codes[code]; ok {
+			return nil, fmt.Errorf("promo code %q is used more than once", p.Code)
+		}
+		e.codes[code] = i
+	}
+	return e, nil
+}
+
+// Apply works out the discounts of an order placed at now with the given
+// code, which may be empty. All line prices must be in the same currency.
+// Each promotion applies to what is left of the items after the ones before
+// it, so discounts never add up to more than the order.
+func (e *Engine) Apply(lines []Line, code string, now time.Time, convert Converter) ([]Discount, error) {
+	o, err := newOrder(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Discount
+	for _, p := range e.promotions {
+		if p.Code != "" || !p.Active(now) {
+			continue
+		}
+		d, err := o.apply(p, convert)
+		if errors.Is(err, ErrNotEligible) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+
+	if code = strings.TrimSpace(code); code == "" {
+		return out, nil
+	}
+	i, ok := e.codes[strings.ToUpper(code)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCode, code)
+	}
+	p := e.promotions[i]
+	if !p.Active(now) {
+		return nil, fmt.Errorf("%w: %q", ErrInactive, code)
+	}
+	d, err := o.apply(p, convert)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", code, err)
+	}
+	d.Code = p.Code
+	return append(out, d), nil
+}
+
+// order tracks what is left to pay for each line, in nanos.
+type order struct {
+	currency  string
+	lines     []Line
+	remaining []int64
+	subtotal  int64
+}
+
+func newOrder(lines []Line) (*order, error) {
+	o := &order{lines: lines, remaining: make([]int64, len(lines))}
+	for i := range lines {
+		l := &lines[i]
+		if i == 0 {
+			o.currency = l.UnitPrice.GetCurrencyCode()
+		} else if l.UnitPrice.GetCurrencyCode() != o.currency {
+			return nil, money.ErrMismatchingCurrency
+		}
+		if !money.IsValid(money.Value(l.UnitPrice)) || money.IsNegative(money.Value(l.UnitPrice)) {
+			return nil, money.ErrInvalidValue
+		}
+		o.remaining[i] = toNanos(l.UnitPrice) * int64(l.Quantity)
+		o.subtotal += o.remaining[i]
+	}
+	return o, nil
+}
+
+// apply takes p off the order, or returns ErrNotEligible if it doesn't apply.
+func (o *order) apply(p Promotion, convert Converter) (Discount, error) {
+	if p.MinSpend != nil {
+		min, err := o.convert(p.MinSpend, convert)
+		if err != nil {
+			return Discount{}, err
+		}
+		if o.subtotal < min {
+			return Discount{}, fmt.Errorf("%w: needs a subtotal of %s", ErrNotEligible, format(fromNanos(min, o.currency)))
+		}
+	}
+
+	off := make([]int64, len(o.lines))
+	switch p.Kind {
+	case KindPercentage:
+		for i := range o.lines {
+			if p.covers(&o.lines[i]) {
+				off[i] = roundToCent(float64(o.remaining[i]) * p.Percent / 100)
+			}
+		}
+	case KindFixedAmount:
+		amount, err := o.convert(p.Amount, convert)
+		if err != nil {
+			return Discount{}, err
+		}
+		o.spread(p, roundToCent(float64(amount)), off)
+	case KindBuyXGetY:
+		for i := range o.lines {
+			if l := &o.lines[i]; p.covers(l) {
+				free := l.Quantity / (p.Buy + p.Get) * p.Get
+				off[i] = toNanos(l.UnitPrice) * int64(free)
+			}
+		}
+	}
+
+	d := Discount{PromotionID: p.ID, Description: p.Description, ByProduct: map[string]*pb.Money{}}
+	var total int64
+	for i, n := range off {
+		if n > o.remaining[i] {
+			n = o.remaining[i]
+		}
+		if n <= 0 {
+			continue
+		}
+		o.remaining[i] -= n
+		total += n
+		id := o.lines[i].ProductID
+		d.ByProduct[id] = fromNanos(toNanos(d.ByProduct[id])+n, o.currency)
+	}
+	if total == 0 {
+		return Discount{}, fmt.Errorf("%w: nothing in the order is covered", ErrNotEligible)
+	}
+	d.Amount = fromNanos(total, o.currency)
+	return d, nil
+}
+
+// spread splits amount between the lines p covers in proportion to what is
+// left of them, so each line's share can be taken off it for tax.
+func (o *order) spread(p Promotion, amount int64, off []int64) {
+	var covered int64
+	last := -1
+	for i := range o.lines {
+		if p.covers(&o.lines[i]) && o.remaining[i] > 0 {
+			covered += o.remaining[i]
+			last = i
+		}
+	}
+	if last < 0 {
+		return
+	}
+	if amount > covered {
+		amount = covered
+	}
+	left := amount
+	for i := range o.lines {
+		if !p.covers(&o.lines[i]) || o.remaining[i] <= 0 {
+			continue
+		}
+		if i == last {
+			off[i] = left
+			break
+		}
+		off[i] = roundToCent(float64(amount) * float64(o.remaining[i]) / float64(covered))
+		left -= off[i]
+	}
+}
+
+func (o *order) convert(m *pb.Money, convert Converter) (int64, error) {
+	if m.GetCurrencyCode() == o.currency || m.GetCurrencyCode() == "" {
+		return toNanos(m), nil
+	}
+	converted, err := convert(m)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s: %w", format(m), err)
+	}
+	return toNanos(converted), nil
+}
+
+func toNanos(m *pb.Money) int64 {
+	return m.GetUnits()*1e9 + int64(m.GetNanos())
+}
+
+func fromNanos(n int64, currency string) *pb.Money {
+	return &pb.Money{CurrencyCode: currency, Units: n / 1e9, Nanos: int32(n % 1e9)}
+}
+
+func roundToCent(nanos float64) int64 {
+	return int64(math.Round(nanos/nanosPerCent)) * nanosPerCent
+}
+
+func format(m *pb.Money) string {
+	return fmt.Sprintf("%s %d.%02d", m.GetCurrencyCode(), m.GetUnits(), m.GetNanos()/nanosPerCent)
+}
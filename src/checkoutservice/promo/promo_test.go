@@ -0,0 +1,188 @@
+package promo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+)
+
+var now = time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func usd(units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+func testLines() []Line {
+	return []Line{
+		{ProductID: "OLJCESPC7Z", Categories: []string{"vintage"}, UnitPrice: usd(67, 990000000), Quantity: 1},
+		{ProductID: "6E92ZMYYFZ", Categories: []string{"gardening"}, UnitPrice: usd(12, 300000000), Quantity: 5},
+	}
+}
+
+func noConversion(m *pb.Money) (*pb.Money, error) {
+	return nil, errors.New("unexpected conversion")
+}
+
+func mustEngine(t *testing.T, promotions ...Promotion) *Engine {
+	t.Helper()
+	e, err := NewEngine(promotions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func cents(m *pb.Money) int64 {
+	return m.GetUnits()*100 + int64(m.GetNanos())/nanosPerCent
+}
+
+func TestApply(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		promotion Promotion
+		amount    int64 // cents
+		products  []string
+	}{
+		{
+			name:      "percentage",
+			promotion: Promotion{ID: "p", Kind: KindPercentage, Code: "TEN", Percent: 10},
+			amount:    680 + 615, // 6.799 and 6.15
+			products:  []string{"6E92ZMYYFZ", "OLJCESPC7Z"},
+		},
+		{
+			name:      "category",
+			promotion: Promotion{ID: "p", Kind: KindPercentage, Code: "TEN", Percent: 10, Categories: []string{"Vintage"}},
+			amount:    680,
+			products:  []string{"OLJCESPC7Z"},
+		},
+		{
+			name:      "fixed amount",
+			promotion: Promotion{ID: "p", Kind: KindFixedAmount, Code: "TEN", Amount: usd(10, 0), Products: []string{"6E92ZMYYFZ"}},
+			amount:    1000,
+			products:  []string{"6E92ZMYYFZ"},
+		},
+		{
+			name:      "fixed amount over the order",
+			promotion: Promotion{ID: "p", Kind: KindFixedAmount, Code: "TEN", Amount: usd(1000, 0)},
+			amount:    6799 + 6150,
+			products:  []string{"6E92ZMYYFZ", "OLJCESPC7Z"},
+		},
+		{
+			name:      "buy x get y",
+			promotion: Promotion{ID: "p", Kind: KindBuyXGetY, Code: "TEN", Buy: 2, Get: 1, Categories: []string{"gardening"}},
+			amount:    1230, // 5 plants is one free
+			products:  []string{"6E92ZMYYFZ"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mustEngine(t, tc.promotion).Apply(testLines(), "ten", now, noConversion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d discounts, want 1", len(got))
+			}
+			d := got[0]
+			if cents(d.Amount) != tc.amount || d.Code != "TEN" || d.Amount.CurrencyCode != "USD" {
+				t.Errorf("got %+v, want %d cents off", d, tc.amount)
+			}
+			var split int64
+			for _, m := range d.ByProduct {
+				split += cents(m)
+			}
+			if split != tc.amount {
+				t.Errorf("split %v adds up to %d cents, want %d", d.ByProduct, split, tc.amount)
+			}
+			if ids := d.ProductIDs(); len(ids) != len(tc.products) || ids[0] != tc.products[0] {
+				t.Errorf("got products %v, want %v", ids, tc.products)
+			}
+		})
+	}
+}
+
+func TestApplyStacksAutomaticPromotions(t *testing.T) {
+	e := mustEngine(t,
+		Promotion{ID: "half", Kind: KindPercentage, Percent: 50},
+		Promotion{ID: "expired", Kind: KindPercentage, Percent: 50, Expires: now.Add(-time.Hour)},
+		Promotion{ID: "big-spender", Kind: KindFixedAmount, Amount: usd(5, 0), MinSpend: usd(500, 0)},
+		Promotion{ID: "all", Kind: KindFixedAmount, Code: "ALL", Amount: usd(1000, 0)},
+	)
+
+	got, err := e.Apply(testLines(), "", now, noConversion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].PromotionID != "half" || cents(got[0].Amount) != 6475 {
+		t.Errorf("got %+v, want just half off 129.49", got)
+	}
+
+	// the code applies to what is left after the automatic promotion
+	got, err = e.Apply(testLines(), "ALL", now, noConversion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || cents(got[0].Amount)+cents(got[1].Amount) != 12949 {
+		t.Errorf("got %+v, want the whole 129.49 off", got)
+	}
+}
+
+func TestApplyRejectsCodes(t *testing.T) {
+	e := mustEngine(t,
+		Promotion{ID: "later", Kind: KindPercentage, Code: "LATER", Percent: 5, Starts: now.Add(time.Hour)},
+		Promotion{ID: "gone", Kind: KindPercentage, Code: "GONE", Percent: 5, Expires: now},
+		Promotion{ID: "min", Kind: KindPercentage, Code: "MIN", Percent: 5, MinSpend: usd(200, 0)},
+		Promotion{ID: "toys", Kind: KindPercentage, Code: "TOYS", Percent: 5, Categories: []string{"toy"}},
+	)
+	for code, want := range map[string]error{
+		"NOPE":  ErrUnknownCode,
+		"LATER": ErrInactive,
+		"gone":  ErrInactive,
+		"MIN":   ErrNotEligible,
+		"TOYS":  ErrNotEligible,
+	} {
+		if _, err := e.Apply(testLines(), code, now, noConversion); !errors.Is(err, want) {
+			t.Errorf("%s: got %v, want %v", code, err, want)
+		}
+	}
+}
+
+func TestApplyConvertsAmounts(t *testing.T) {
+	e := mustEngine(t, Promotion{ID: "five", Kind: KindFixedAmount, Code: "FIVE", Amount: usd(5, 0), MinSpend: usd(100, 0)})
+	lines := []Line{{ProductID: "OLJCESPC7Z", UnitPrice: &pb.Money{CurrencyCode: "EUR", Units: 250}, Quantity: 1}}
+	toEUR := func(m *pb.Money) (*pb.Money, error) {
+		return &pb.Money{CurrencyCode: "EUR", Units: m.Units * 2}, nil
+	}
+
+	got, err := e.Apply(lines, "FIVE", now, toEUR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Amount.CurrencyCode != "EUR" || got[0].Amount.Units != 10 {
+		t.Errorf("got %v, want EUR 10", got[0].Amount)
+	}
+	// 100 USD is 200 EUR
+	lines[0].UnitPrice.Units = 199
+	if _, err := e.Apply(lines, "FIVE", now, toEUR); !errors.Is(err, ErrNotEligible) {
+		t.Errorf("got %v, want the min spend to be converted", err)
+	}
+}
+
+func TestNewEngineValidates(t *testing.T) {
+	for name, promotions := range map[string][]Promotion{
+		"no id":          {{Kind: KindPercentage, Percent: 5}},
+		"unknown kind":   {{ID: "a", Kind: "bogo"}},
+		"percent over":   {{ID: "a", Kind: KindPercentage, Percent: 150}},
+		"no amount":      {{ID: "a", Kind: KindFixedAmount}},
+		"buy nothing":    {{ID: "a", Kind: KindBuyXGetY, Get: 1}},
+		"expires early":  {{ID: "a", Kind: KindPercentage, Percent: 5, Starts: now, Expires: now.Add(-time.Hour)}},
+		"duplicate id":   {{ID: "a", Kind: KindPercentage, Percent: 5}, {ID: "a", Kind: KindPercentage, Percent: 5}},
+		"duplicate code": {{ID: "a", Kind: KindPercentage, Percent: 5, Code: "X"}, {ID: "b", Kind: KindPercentage, Percent: 5, Code: "x"}},
+		"negative spend": {{ID: "a", Kind: KindPercentage, Percent: 5, MinSpend: usd(-1, 0)}},
+	} {
+		if _, err := NewEngine(promotions); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/promo"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// discountDatabaseLookup turns on the demo's slow discount lookup, which the
+// discount-n-plus-one scenario turns into an N+1 query. Promotions themselves
+// come from the promotions config either way.
+var discountDatabaseLookup atomic.Bool
+
+// defaultPromotions are the promotions the checkout service runs when
+// PROMOTIONS_CONFIG is not set. They all need a code, so orders without one
+// cost what they did before promotions existed.
+func defaultPromotions() []promo.Promotion {
+	return []promo.Promotion{
+		{
+			ID:          "welcome-10",
+			Description: "10% off your first order over $50",
+			Kind:        promo.KindPercentage,
+			Code:        "WELCOME10",
+			Percent:     10,
+			MinSpend:    &pb.Money{CurrencyCode: "USD", Units: 50},
+		},
+		{
+			ID:          "five-off",
+			Description: "$5 off orders over $25",
+			Kind:        promo.KindFixedAmount,
+			Code:        "FIVEOFF",
+			Amount:      &pb.Money{CurrencyCode: "USD", Units: 5},
+			MinSpend:    &pb.Money{CurrencyCode: "USD", Units: 25},
+		},
+		{
+			ID:          "vintage-20",
+			Description: "20% off everything vintage",
+			Kind:        promo.KindPercentage,
+			Code:        "VINTAGE20",
+			Percent:     20,
+			Categories:  []string{"vintage"},
+		},
+		{
+			ID:          "plants-3-for-2",
+			Description: "Buy 2 plants, get a third free",
+			Kind:        promo.KindBuyXGetY,
+			Code:        "PLANTS3FOR2",
+			Buy:         2,
+			Get:         1,
+			Categories:  []string{"gardening"},
+		},
+		{
+			ID:          "summer-sale-2024",
+			Description: "15% off for the summer",
+			Kind:        promo.KindPercentage,
+			Code:        "SUMMER15",
+			Percent:     15,
+			Expires:     time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+// newPromotionEngine loads the promotions in path, or the defaults if path is empty.
+func newPromotionEngine(path string) (*promo.Engine, error) {
+	promotions := defaultPromotions()
+	if path != "" {
+		var err error
+		if promotions, err = promo.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	return promo.NewEngine(promotions)
+}
+
+// getDiscounts applies the promotions, and the code in req, to the prepared order.
+func (cs *checkoutService) getDiscounts(ctx context.Context, req *pb.PlaceOrderRequest, prep orderPrep, cachesize int) ([]promo.Discount, error) {
+	tracer := otel.GetTracerProvider().Tracer("")
+	ctx, span := tracer.Start(ctx, "getDiscounts")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("app.user_id", req.GetUserId()),
+		attribute.String("app.promo_code", req.GetPromoCode()),
+	)
+
+	if discountDatabaseLookup.Load() {
+		// the discount-n-plus-one scenario slows this down as the cache grows
+		if err := cs.faults.Inject(ctx, "checkout.getDiscounts", faults.Target{UserID: req.GetUserId(), Load: cachesize}); err != nil {
+			log.Warnf("failed to load discounts for %q: %+v", req.GetUserId(), err)
+		} else {
+			loadDiscountFromDatabase(ctx)
+		}
+	}
+
+	lines := make([]promo.Line, len(prep.orderItems))
+	for i, it := range prep.orderItems {
+		lines[i] = promo.Line{
+			ProductID:  it.GetItem().GetProductId(),
+			Categories: prep.products[it.GetItem().GetProductId()].GetCategories(),
+			UnitPrice:  it.GetCost(),
+			Quantity:   it.GetItem().GetQuantity(),
+		}
+	}
+	convert := func(m *pb.Money) (*pb.Money, error) {
+		return cs.convertCurrency(ctx, m, req.GetUserCurrency())
+	}
+	discounts, err := cs.promotions.Apply(lines, req.GetPromoCode(), time.Now(), convert)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	ids := make([]string, len(discounts))
+	var total float64
+	for i, d := range discounts {
+		ids[i] = d.PromotionID
+		total += moneyToFloat(d.Amount)
+		span.AddEvent("discount applied", trace.WithAttributes(
+			attribute.String("app.promotion_id", d.PromotionID),
+			attribute.String("app.promo_code", d.Code),
+			attribute.Float64("app.discount.amount", moneyToFloat(d.Amount)),
+			attribute.StringSlice("app.discount.product_ids", d.ProductIDs()),
+		))
+	}
+	span.SetAttributes(
+		attribute.StringSlice("app.discount.promotions", ids),
+		attribute.Int("app.discount.count", len(discounts)),
+		attribute.Float64("app.discount.total", round2(total)),
+	)
+	return discounts, nil
+}
+
+// appliedDiscounts describes discounts for the OrderResult.
+func appliedDiscounts(discounts []promo.Discount) []*pb.AppliedDiscount {
+	out := make([]*pb.AppliedDiscount, len(discounts))
+	for i, d := range discounts {
+		out[i] = &pb.AppliedDiscount{
+			PromotionId: d.PromotionID,
+			Code:        d.Code,
+			Description: d.Description,
+			Amount:      d.Amount,
+			ProductIds:  d.ProductIDs(),
+		}
+	}
+	return out
+}
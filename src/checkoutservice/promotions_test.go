@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPlaceOrderAppliesPromoCode(t *testing.T) {
+	cs, _ := newTestCheckoutService()
+	exporter := &recordingExporter{}
+	cs.exporter = exporter
+
+	req := testOrderRequest()
+	req.PromoCode = "vintage20"
+	resp, err := cs.PlaceOrder(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discounts := resp.GetOrder().GetDiscounts()
+	if len(discounts) != 1 || discounts[0].GetPromotionId() != "vintage-20" || discounts[0].GetCode() != "VINTAGE20" {
+		t.Fatalf("got discounts %v, want VINTAGE20", discounts)
+	}
+	// 20% off the 67.99 vintage item only
+	if d := discounts[0]; d.GetAmount().GetUnits() != 13 || d.GetAmount().GetNanos() != 600000000 || len(d.GetProductIds()) != 1 {
+		t.Errorf("got %v, want 13.60 off OLJCESPC7Z", d)
+	}
+	// 7.25% tax on the discounted 79.37, then 8.99 shipping
+	rec := exporter.records[0]
+	if rec.DiscountTotal != 13.60 || rec.TaxAmount != 5.75 || rec.TotalCharged != 94.11 {
+		t.Errorf("got discount %v, tax %v and total %v, want 13.60, 5.75 and 94.11", rec.DiscountTotal, rec.TaxAmount, rec.TotalCharged)
+	}
+}
+
+func TestPlaceOrderRejectsInvalidPromoCode(t *testing.T) {
+	cs, fakes := newTestCheckoutService()
+
+	for _, code := range []string{"NOT-A-CODE", "SUMMER15"} {
+		req := testOrderRequest()
+		req.PromoCode = code
+		_, err := cs.PlaceOrder(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", code, err)
+		}
+	}
+	if got := fakes.payment.charges.Load(); got != 0 {
+		t.Errorf("got %d charges, want none", got)
+	}
+}
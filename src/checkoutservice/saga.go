@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// compensationTimeout bounds how long unwinding a failed order may take.
+const compensationTimeout = 10 * time.Second
+
+// orderSaga records the steps of PlaceOrder that have completed along with the
+// action that undoes each of them, so a failure part way through an order can
+// be unwound instead of leaving the customer charged for an order that never ships.
+type orderSaga struct {
+	orderID string
+	steps   []sagaStep
+}
+
+type sagaStep struct {
+	name string
+	// compensate undoes the step. A nil compensate means the step had no side
+	// effects, the unwind still records a span for it so the trace shows the
+	// complete saga.
+	compensate func(ctx context.Context) error
+}
+
+func newOrderSaga(orderID string) *orderSaga {
+	return &orderSaga{orderID: orderID}
+}
+
+// completed records that the named step succeeded and how to undo it.
+func (s *orderSaga) completed(name string, compensate func(ctx context.Context) error) {
+	s.steps = append(s.steps, sagaStep{name: name, compensate: compensate})
+}
+
+// abort unwinds every completed step in reverse order and returns a gRPC
+// status error for the step that failed.
+func (s *orderSaga) abort(ctx context.Context, failedStep string, code codes.Code, cause error) error {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("app.saga.failed_step", failedStep),
+		attribute.Int("app.saga.compensations", len(s.steps)),
+	)
+	span.AddEvent("saga aborted", trace.WithAttributes(
+		attribute.String("app.order_id", s.orderID),
+		attribute.String("app.saga.failed_step", failedStep),
+		attribute.String("error.message", cause.Error()),
+	))
+
+	if failed := s.unwind(ctx); failed > 0 {
+		log.Errorf("[PlaceOrder] order %s left %d step(s) uncompensated after %s failed", s.orderID, failed, failedStep)
+		span.SetAttributes(attribute.Int("app.saga.compensations_failed", failed))
+	}
+	return status.Errorf(code, "%s failed: %+v", failedStep, cause)
+}
+
+// unwind runs the compensating actions in reverse order, each in its own span,
+// and returns how many of them failed. A failed compensation does not stop the
+// rest from running.
+func (s *orderSaga) unwind(ctx context.Context) int {
+	// the incoming request may already be cancelled, but the compensations
+	// still have to run and should stay in the same trace
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	tracer := otel.GetTracerProvider().Tracer("")
+	failed := 0
+	for i := len(s.steps) - 1; i >= 0; i-- {
+		step := s.steps[i]
+		stepCtx, span := tracer.Start(ctx, "compensate "+step.name)
+		span.SetAttributes(
+			attribute.String("app.order_id", s.orderID),
+			attribute.String("app.saga.step", step.name),
+			attribute.Bool("app.saga.noop", step.compensate == nil),
+		)
+		if step.compensate != nil {
+			if err := step.compensate(stepCtx); err != nil {
+				failed++
+				span.RecordError(err)
+				span.SetStatus(otelcodes.Error, fmt.Sprintf("compensation for %s failed", step.name))
+				log.Errorf("[PlaceOrder] failed to compensate %s for order %s: %+v", step.name, s.orderID, err)
+			} else {
+				log.Infof("[PlaceOrder] compensated %s for order %s", step.name, s.orderID)
+			}
+		}
+		span.End()
+	}
+	return failed
+}
+
+func (cs *checkoutService) refundCharge(ctx context.Context, transactionID string, amount *pb.Money) error {
+	resp, err := cs.paymentSvcClient.Refund(ctx, &pb.RefundRequest{
+		TransactionId: transactionID,
+		Amount:        amount})
+	if err != nil {
+		return fmt.Errorf("could not refund transaction %s: %+v", transactionID, err)
+	}
+	log.Debugf("payment refunded (transaction_id: %s, refund_id: %s)", transactionID, resp.GetRefundId())
+	sleepRandom(50)
+	return nil
+}
+
+func (cs *checkoutService) releaseStock(ctx context.Context, orderID string) error {
+	if _, err := cs.inventorySvcClient.ReleaseReservation(ctx, &pb.ReservationRequest{ReservationId: orderID}); err != nil {
+		return fmt.Errorf("could not release stock reservation %s: %+v", orderID, err)
+	}
+	sleepRandom(20)
+	return nil
+}
+
+func (cs *checkoutService) cancelShipment(ctx context.Context, trackingID string) error {
+	if _, err := cs.shippingSvcClient.CancelShipment(ctx, &pb.CancelShipmentRequest{TrackingId: trackingID}); err != nil {
+		return fmt.Errorf("could not cancel shipment %s: %+v", trackingID, err)
+	}
+	sleepRandom(25)
+	return nil
+}
+
+func (cs *checkoutService) restoreUserCart(ctx context.Context, userID string, items []*pb.CartItem) error {
+	for _, item := range items {
+		if _, err := cs.cartSvcClient.AddItem(ctx, &pb.AddItemRequest{UserId: userID, Item: item}); err != nil {
+			return fmt.Errorf("failed to restore %q to user cart: %+v", item.GetProductId(), err)
+		}
+	}
+	sleepRandom(20)
+	return nil
+}
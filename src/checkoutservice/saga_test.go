@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingOrders is an order repository that can't save orders.
+type failingOrders struct {
+	OrderRepository
+}
+
+func (failingOrders) Save(context.Context, string, *pb.OrderResult) error {
+	return errors.New("disk full")
+}
+
+// TestPlaceOrderCompensates fails PlaceOrder at each step and checks that the
+// steps before it are undone in reverse order, each in its own span.
+func TestPlaceOrderCompensates(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "backend down")
+	for _, tc := range []struct {
+		step  string
+		fail  func(cs *checkoutService, fakes *fakeBackends, req *pb.PlaceOrderRequest)
+		code  codes.Code
+		spans []string
+	}{
+		{
+			step: "prepare",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				delete(fakes.catalog.products, "66VCHSJNUP")
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			step: "discount",
+			fail: func(_ *checkoutService, _ *fakeBackends, req *pb.PlaceOrderRequest) {
+				req.PromoCode = "NOSUCHCODE"
+			},
+			code:  codes.InvalidArgument,
+			spans: []string{"prepare"},
+		},
+		{
+			step: "calculate tax",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				// too large for the tax to be worked out exactly
+				fakes.catalog.products["66VCHSJNUP"].PriceUsd.Units = 2_000_000_000_000
+			},
+			code:  codes.Internal,
+			spans: []string{"discount", "prepare"},
+		},
+		{
+			step: "reserve stock",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				fakes.inventory.stock["66VCHSJNUP"] = 1
+			},
+			code:  codes.FailedPrecondition,
+			spans: []string{"calculate tax", "discount", "prepare"},
+		},
+		{
+			step: "charge",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				fakes.payment.chargeErr = unavailable
+			},
+			code:  codes.Internal,
+			spans: []string{"reserve stock", "calculate tax", "discount", "prepare"},
+		},
+		{
+			step: "ship",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				fakes.shipping.shipErr = unavailable
+			},
+			code:  codes.Unavailable,
+			spans: []string{"charge", "reserve stock", "calculate tax", "discount", "prepare"},
+		},
+		{
+			step: "record order",
+			fail: func(cs *checkoutService, _ *fakeBackends, _ *pb.PlaceOrderRequest) {
+				cs.orders = failingOrders{cs.orders}
+			},
+			code:  codes.Internal,
+			spans: []string{"ship", "charge", "reserve stock", "calculate tax", "discount", "prepare"},
+		},
+		{
+			step: "empty cart",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				fakes.cart.emptyErr = unavailable
+			},
+			code:  codes.Internal,
+			spans: []string{"record order", "ship", "charge", "reserve stock", "calculate tax", "discount", "prepare"},
+		},
+		{
+			step: "commit stock",
+			fail: func(_ *checkoutService, fakes *fakeBackends, _ *pb.PlaceOrderRequest) {
+				fakes.inventory.commitErr = unavailable
+			},
+			code:  codes.Internal,
+			spans: []string{"empty cart", "record order", "ship", "charge", "reserve stock", "calculate tax", "discount", "prepare"},
+		},
+	} {
+		t.Run(tc.step, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			prev := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+			t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+			cs, fakes := newTestCheckoutService()
+			req := testOrderRequest()
+			tc.fail(cs, fakes, req)
+			cart := append([]*pb.CartItem(nil), fakes.cart.items["42"]...)
+
+			_, err := cs.PlaceOrder(context.Background(), req)
+			if status.Code(err) != tc.code || !strings.Contains(status.Convert(err).Message(), tc.step+" failed") {
+				t.Fatalf("got %v, want %s for the %s step", err, tc.code, tc.step)
+			}
+
+			var spans []string
+			for _, s := range sr.Ended() {
+				if name, ok := strings.CutPrefix(s.Name(), "compensate "); ok {
+					spans = append(spans, name)
+				}
+			}
+			if !reflect.DeepEqual(spans, tc.spans) {
+				t.Errorf("compensated %q, want %q", spans, tc.spans)
+			}
+
+			ran := func(step string) bool {
+				for _, s := range tc.spans {
+					if s == step {
+						return true
+					}
+				}
+				return false
+			}
+			if got, want := fakes.payment.refunds.Load() == 1, ran("charge"); got != want {
+				t.Errorf("refunded: %v, want %v", got, want)
+			}
+			if got, want := fakes.shipping.cancelled.Load() == 1, ran("ship"); got != want {
+				t.Errorf("shipment cancelled: %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(fakes.cart.items["42"], cart) {
+				t.Errorf("cart is %v, want it restored to %v", fakes.cart.items["42"], cart)
+			}
+			if orders, _ := cs.orders.ListByUser(context.Background(), "42"); len(orders) != 0 {
+				t.Errorf("%d orders left recorded", len(orders))
+			}
+			if len(fakes.inventory.reservations) != 0 {
+				t.Errorf("reservations left behind: %v", fakes.inventory.reservations)
+			}
+		})
+	}
+}
+
+// TestPlaceOrderEmailIsBestEffort checks a failed confirmation email doesn't
+// lose or unwind an order that has been paid for and shipped, and that the
+// failure is recorded on the span and in the export.
+func TestPlaceOrderEmailIsBestEffort(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	cs, fakes := newTestCheckoutService()
+	exporter := &recordingExporter{}
+	cs.exporter = exporter
+	fakes.email.err = status.Error(codes.Unavailable, "email is down")
+	fakes.email.sent = make(chan struct{}, 1)
+
+	res, err := cs.PlaceOrder(context.Background(), testOrderRequest())
+	if err != nil {
+		t.Fatalf("got %v, want the order placed without its email", err)
+	}
+	select {
+	case <-fakes.email.sent:
+	default:
+		t.Fatal("no confirmation email was attempted before PlaceOrder returned")
+	}
+	if fakes.payment.refunds.Load() != 0 || fakes.shipping.cancelled.Load() != 0 {
+		t.Error("compensated an order whose email failed")
+	}
+	if _, err := cs.orders.Get(context.Background(), "42", res.Order.OrderId); err != nil {
+		t.Errorf("order wasn't kept: %v", err)
+	}
+	if len(exporter.records) != 1 || exporter.records[0].ConfirmationSent {
+		t.Errorf("got exports %+v, want the order exported without its confirmation", exporter.records)
+	}
+	failed := false
+	for _, s := range sr.Ended() {
+		for _, e := range s.Events() {
+			failed = failed || (s.Name() == "confirmOrder" && e.Name == "order confirmation failed")
+		}
+	}
+	if !failed {
+		t.Error("the failed email isn't recorded on the confirmOrder span")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/money"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/tax"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultTaxRules are the rules the checkout service uses when TAX_RULES is
+// not set. The rates are illustrative, not tax advice.
+func defaultTaxRules() tax.Config {
+	return tax.Config{
+		Countries: map[string]tax.Country{
+			"United States": {
+				Aliases: []string{"USA", "US"},
+				States: map[string]tax.Rule{
+					"CA": {Rate: 7.25},
+					"NY": {Rate: 4, TaxShipping: true},
+					"TX": {Rate: 6.25},
+					"WA": {Rate: 6.5, TaxShipping: true},
+					// plants that grow food aren't taxed in Pennsylvania
+					"PA": {Rate: 6, ExemptCategories: []string{"gardening"}},
+				},
+			},
+			"Canada": {Rule: tax.Rule{Rate: 5, TaxShipping: true}, Aliases: []string{"CA"}},
+			"United Kingdom": {
+				Rule:    tax.Rule{Rate: 20, TaxShipping: true},
+				Aliases: []string{"UK", "GB"},
+			},
+			"Germany": {Rule: tax.Rule{Rate: 19, TaxShipping: true}, Aliases: []string{"DE"}},
+			"France":  {Rule: tax.Rule{Rate: 20, TaxShipping: true}, Aliases: []string{"FR"}},
+			"Japan":   {Rule: tax.Rule{Rate: 10, TaxShipping: true}, Aliases: []string{"JP"}},
+		},
+	}
+}
+
+// newTaxCalculator loads the tax rules in path, or the defaults if path is empty.
+func newTaxCalculator(path string) (*tax.Calculator, error) {
+	rules := defaultTaxRules()
+	if path != "" {
+		var err error
+		if rules, err = tax.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	return tax.NewCalculator(rules)
+}
+
+// calculateTax works out the tax of the prepared and discounted order shipped to address.
+func (cs *checkoutService) calculateTax(ctx context.Context, address *pb.Address, prep orderPrep) (tax.Result, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "calculateTax")
+	defer span.End()
+
+	lines := make([]tax.Line, len(prep.orderItems))
+	for i, it := range prep.orderItems {
+		id := it.GetItem().GetProductId()
+		lines[i] = tax.Line{
+			ProductID:  id,
+			Categories: prep.products[id].GetCategories(),
+		}
+		amount := money.MultiplySlow(money.Value(it.GetCost()), uint32(it.GetItem().GetQuantity()))
+		lines[i].Amount = &amount
+		// tax is due on what the customer pays, after promotions
+		for _, d := range prep.discounts {
+			if off, ok := d.ByProduct[id]; ok {
+				if lines[i].Discount == nil {
+					lines[i].Discount = off
+				} else {
+					discount := money.Must(money.Sum(money.Value(lines[i].Discount), money.Value(off)))
+					lines[i].Discount = &discount
+				}
+			}
+		}
+	}
+	res, err := cs.tax.Calculate(address, lines, prep.shippingCostLocalized)
+	if err != nil {
+		return tax.Result{}, err
+	}
+	span.SetAttributes(
+		attribute.String("app.tax.jurisdiction", res.Jurisdiction),
+		attribute.Float64("app.tax.rate", res.Rate),
+		attribute.Float64("app.tax.amount", moneyToFloat(res.Tax)),
+		attribute.StringSlice("app.tax.exempt_products", res.Exempt),
+	)
+	return res, nil
+}
@@ -0,0 +1,220 @@
+// Package tax calculates the sales tax of an order from per-country rules.
+//
+// Rules are keyed by the country of the shipping address, and a country can
+// override its rule for individual states. A rule has a rate and a list of
+// product categories it exempts, so the tax of an order depends on both
+// where it ships and what is in it. Countries without a rule use the default.
+package tax
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/checkoutservice/money"
+)
+
+// Rule is the tax that applies in one jurisdiction.
+type Rule struct {
+	// Rate is the tax rate in percent.
+	Rate float64 `json:"rate"`
+	// ExemptCategories are product categories that are not taxed.
+	ExemptCategories []string `json:"exempt_categories,omitempty"`
+	// TaxShipping taxes the shipping cost as well as the items.
+	TaxShipping bool `json:"tax_shipping,omitempty"`
+}
+
+// Validate reports whether the rule can be applied.
+func (r Rule) Validate() error {
+	if r.Rate < 0 || r.Rate > 100 || math.IsNaN(r.Rate) {
+		return fmt.Errorf("rate %v is not between 0 and 100", r.Rate)
+	}
+	return nil
+}
+
+func (r Rule) exempts(categories []string) bool {
+	for _, c := range categories {
+		for _, e := range r.ExemptCategories {
+			if strings.EqualFold(c, e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Country is the rule for a country, and the rules of states that differ from it.
+type Country struct {
+	Rule
+	// Aliases are other names the country is written as, such as "USA".
+	Aliases []string `json:"aliases,omitempty"`
+	// States are keyed by the state of the address, such as "CA".
+	States map[string]Rule `json:"states,omitempty"`
+}
+
+// Config is the format of a tax rules file.
+type Config struct {
+	Default   Rule               `json:"default"`
+	Countries map[string]Country `json:"countries"`
+}
+
+// LoadConfig reads a tax rules file. The rules are validated when they are
+// given to NewCalculator.
+func LoadConfig(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Config{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Line is an order item to be taxed.
+type Line struct {
+	ProductID  string
+	Categories []string
+	// Amount is the cost of the line, its unit price times the quantity.
+	Amount *pb.Money
+	// Discount, if not nil, is taken off Amount before it is taxed.
+	Discount *pb.Money
+}
+
+// Result is the tax of an order.
+type Result struct {
+	// Jurisdiction names the rule that applied, such as "United States/CA".
+	Jurisdiction string
+	Rate         float64
+	// Subtotal is the cost of all lines before discounts, Taxable the part
+	// of it and of shipping that the rate applies to.
+	Subtotal *pb.Money
+	Taxable  *pb.Money
+	Tax      *pb.Money
+	// Exempt lists the products that were not taxed.
+	Exempt []string
+}
+
+type jurisdiction struct {
+	name   string
+	rule   Rule
+	states map[string]Rule
+}
+
+// Calculator applies a set of tax rules. It is safe for concurrent use.
+type Calculator struct {
+	def       Rule
+	countries map[string]jurisdiction
+}
+
+// NewCalculator returns a Calculator for the given rules. Country and state
+// names are matched ignoring case.
+func NewCalculator(c Config) (*Calculator, error) {
+	if err := c.Default.Validate(); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	calc := &Calculator{def: c.Default, countries: map[string]jurisdiction{}}
+	names := make([]string, 0, len(c.Countries))
+	for name := range c.Countries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		country := c.Countries[name]
+		if err := country.Rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		j := jurisdiction{name: name, rule: country.Rule, states: map[string]Rule{}}
+		for state, r := range country.States {
+			if err := r.Validate(); err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", name, state, err)
+			}
+			j.states[strings.ToLower(state)] = r
+		}
+		for _, key := range append([]string{name}, country.Aliases...) {
+			key = strings.ToLower(strings.TrimSpace(key))
+			if _, ok := calc.countries[key]; ok {
+				return nil, fmt.Errorf("country %q has more than one rule", key)
+			}
+			calc.countries[key] = j
+		}
+	}
+	return calc, nil
+}
+
+// Rule returns the rule for an address and the name of its jurisdiction.
+func (c *Calculator) Rule(address *pb.Address) (Rule, string) {
+	j, ok := c.countries[strings.ToLower(strings.TrimSpace(address.GetCountry()))]
+	if !ok {
+		return c.def, "default"
+	}
+	if r, ok := j.states[strings.ToLower(strings.TrimSpace(address.GetState()))]; ok {
+		return r, j.name + "/" + address.GetState()
+	}
+	return j.rule, j.name
+}
+
+// Calculate works out the tax of lines shipped to address. All amounts must
+// be in the same currency as shipping.
+func (c *Calculator) Calculate(address *pb.Address, lines []Line, shipping *pb.Money) (Result, error) {
+	rule, name := c.Rule(address)
+	subtotal := pb.Money{CurrencyCode: shipping.GetCurrencyCode()}
+	taxable := pb.Money{CurrencyCode: shipping.GetCurrencyCode()}
+	res := Result{Jurisdiction: name, Rate: rule.Rate, Subtotal: &subtotal, Taxable: &taxable}
+
+	for _, l := range lines {
+		var err error
+		if subtotal, err = money.Sum(money.Value(&subtotal), money.Value(l.Amount)); err != nil {
+			return Result{}, fmt.Errorf("line %s: %w", l.ProductID, err)
+		}
+		if rule.exempts(l.Categories) {
+			res.Exempt = append(res.Exempt, l.ProductID)
+			continue
+		}
+		amount := money.Value(l.Amount)
+		if l.Discount != nil {
+			if amount, err = money.Sum(money.Value(&amount), money.Negate(money.Value(l.Discount))); err != nil {
+				return Result{}, fmt.Errorf("line %s: %w", l.ProductID, err)
+			}
+		}
+		taxable = money.Must(money.Sum(money.Value(&taxable), money.Value(&amount)))
+	}
+	if rule.TaxShipping {
+		var err error
+		if taxable, err = money.Sum(money.Value(&taxable), money.Value(shipping)); err != nil {
+			return Result{}, fmt.Errorf("shipping: %w", err)
+		}
+	}
+
+	tax, err := Percent(&taxable, rule.Rate)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Tax = tax
+	return res, nil
+}
+
+// Percent returns rate percent of m, rounded half up to the cent.
+func Percent(m *pb.Money, rate float64) (*pb.Money, error) {
+	if !money.IsValid(money.Value(m)) || money.IsNegative(money.Value(m)) {
+		return nil, money.ErrInvalidValue
+	}
+	// work in cents and parts per million so the result is exact
+	cents := m.GetUnits()*100 + int64(math.Round(float64(m.GetNanos())/1e7))
+	rateMicros := int64(math.Round(rate * 1e4))
+	if rateMicros != 0 && cents > math.MaxInt64/rateMicros {
+		return nil, errors.New("amount is too large to tax")
+	}
+	taxCents := (cents*rateMicros + 500000) / 1000000
+	return &pb.Money{
+		CurrencyCode: m.GetCurrencyCode(),
+		Units:        taxCents / 100,
+		Nanos:        int32(taxCents%100) * 1e7,
+	}, nil
+}
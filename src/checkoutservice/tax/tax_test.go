@@ -0,0 +1,139 @@
+package tax
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/checkoutservice/demo/msdemo"
+)
+
+func usd(units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+func testCalculator(t *testing.T) *Calculator {
+	t.Helper()
+	c, err := NewCalculator(Config{
+		Default: Rule{Rate: 1},
+		Countries: map[string]Country{
+			"United States": {
+				Aliases: []string{"USA"},
+				States: map[string]Rule{
+					"CA": {Rate: 7.25},
+					"PA": {Rate: 6, ExemptCategories: []string{"gardening"}},
+					"NY": {Rate: 4, TaxShipping: true},
+				},
+			},
+			"Germany": {Rule: Rule{Rate: 19, TaxShipping: true}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCalculate(t *testing.T) {
+	lines := []Line{
+		{ProductID: "OLJCESPC7Z", Categories: []string{"vintage"}, Amount: usd(67, 990000000)},
+		{ProductID: "L9ECAV7KIM", Categories: []string{"Gardening"}, Amount: usd(36, 450000000)},
+	}
+	shipping := usd(8, 990000000)
+
+	for _, tc := range []struct {
+		country, state string
+		jurisdiction   string
+		tax            *pb.Money
+		exempt         []string
+	}{
+		// 7.25% of 104.44
+		{"United States", "CA", "United States/CA", usd(7, 570000000), nil},
+		{"usa", "ca", "United States/ca", usd(7, 570000000), nil},
+		// 6% of 67.99, the terrarium is exempt
+		{"United States", "PA", "United States/PA", usd(4, 80000000), []string{"L9ECAV7KIM"}},
+		// 4% of 104.44 + 8.99
+		{"United States", "NY", "United States/NY", usd(4, 540000000), nil},
+		// no rule for the state or the country
+		{"United States", "OR", "United States", usd(0, 0), nil},
+		{"Germany", "", "Germany", usd(21, 550000000), nil},
+		{"Atlantis", "", "default", usd(1, 40000000), nil},
+	} {
+		addr := &pb.Address{Country: tc.country, State: tc.state}
+		res, err := testCalculator(t).Calculate(addr, lines, shipping)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Jurisdiction != tc.jurisdiction {
+			t.Errorf("%s/%s: got jurisdiction %q, want %q", tc.country, tc.state, res.Jurisdiction, tc.jurisdiction)
+		}
+		if res.Tax.Units != tc.tax.Units || res.Tax.Nanos != tc.tax.Nanos {
+			t.Errorf("%s/%s: got tax %d.%09d, want %d.%09d", tc.country, tc.state, res.Tax.Units, res.Tax.Nanos, tc.tax.Units, tc.tax.Nanos)
+		}
+		if !reflect.DeepEqual(res.Exempt, tc.exempt) {
+			t.Errorf("%s/%s: got exempt %v, want %v", tc.country, tc.state, res.Exempt, tc.exempt)
+		}
+		if res.Subtotal.Units != 104 || res.Subtotal.Nanos != 440000000 {
+			t.Errorf("%s/%s: got subtotal %d.%09d, want 104.44", tc.country, tc.state, res.Subtotal.Units, res.Subtotal.Nanos)
+		}
+	}
+}
+
+func TestCalculateAfterDiscounts(t *testing.T) {
+	lines := []Line{{ProductID: "OLJCESPC7Z", Amount: usd(100, 0), Discount: usd(20, 0)}}
+	res, err := testCalculator(t).Calculate(&pb.Address{Country: "United States", State: "CA"}, lines, usd(5, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 7.25% of 80, while the subtotal is before the discount
+	if res.Tax.Units != 5 || res.Tax.Nanos != 800000000 || res.Subtotal.Units != 100 {
+		t.Errorf("got tax %v on subtotal %v, want 5.80 on 100", res.Tax, res.Subtotal)
+	}
+}
+
+func TestCalculateRejectsMixedCurrencies(t *testing.T) {
+	lines := []Line{{ProductID: "OLJCESPC7Z", Amount: &pb.Money{CurrencyCode: "EUR", Units: 1}}}
+	if _, err := testCalculator(t).Calculate(&pb.Address{Country: "Germany"}, lines, usd(1, 0)); err == nil {
+		t.Error("expected an error for items and shipping in different currencies")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	for _, tc := range []struct {
+		in    *pb.Money
+		rate  float64
+		units int64
+		nanos int32
+	}{
+		{usd(100, 0), 7.25, 7, 250000000},
+		{usd(0, 10000000), 50, 0, 10000000}, // half a cent rounds up
+		{usd(19, 990000000), 0, 0, 0},
+		{usd(33, 330000000), 100, 33, 330000000},
+	} {
+		got, err := Percent(tc.in, tc.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Units != tc.units || got.Nanos != tc.nanos || got.CurrencyCode != "USD" {
+			t.Errorf("%v%% of %d.%09d: got %d.%09d, want %d.%09d", tc.rate, tc.in.Units, tc.in.Nanos, got.Units, got.Nanos, tc.units, tc.nanos)
+		}
+	}
+	if _, err := Percent(usd(-1, 0), 10); err == nil {
+		t.Error("expected an error for a negative amount")
+	}
+}
+
+func TestNewCalculatorValidates(t *testing.T) {
+	for name, c := range map[string]Config{
+		"negative default": {Default: Rule{Rate: -1}},
+		"rate over 100":    {Countries: map[string]Country{"Germany": {Rule: Rule{Rate: 119}}}},
+		"bad state":        {Countries: map[string]Country{"United States": {States: map[string]Rule{"CA": {Rate: -7}}}}},
+		"duplicate alias": {Countries: map[string]Country{
+			"United States":  {Aliases: []string{"US"}},
+			"United Kingdom": {Aliases: []string{"us"}},
+		}},
+	} {
+		if _, err := NewCalculator(c); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
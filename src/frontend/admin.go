@@ -0,0 +1,196 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newAdminSettings returns the frontend's own demo settings, served on
+// /admin/settings.
+func newAdminSettings(r *faults.Registry) *admin.Settings {
+	settings := admin.FaultSettings(r)
+	settings["percent_normal"] = admin.Setting{
+		Get: func() string { return strconv.Itoa(int(PercentNormal.Load())) },
+		Parse: func(v string) (func(), error) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, err
+			}
+			if n < 0 || n > 100 {
+				return nil, fmt.Errorf("%d is not a percentage", n)
+			}
+			return func() { PercentNormal.Store(int32(n)) }, nil
+		},
+	}
+	settings["cache_user_threshold"] = admin.Setting{
+		Get: func() string {
+			user, _ := CacheTrack.Thresholds()
+			return strconv.Itoa(user)
+		},
+		Parse: func(v string) (func(), error) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, err
+			}
+			if n < 1 {
+				return nil, fmt.Errorf("%d is not a positive threshold", n)
+			}
+			return func() { CacheTrack.SetUserThreshold(n) }, nil
+		},
+	}
+	settings["cache_marker_threshold"] = admin.Setting{
+		Get: func() string {
+			_, marker := CacheTrack.Thresholds()
+			return strconv.Itoa(marker)
+		},
+		Parse: func(v string) (func(), error) {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, err
+			}
+			if n < 1 {
+				return nil, fmt.Errorf("%d is not a positive threshold", n)
+			}
+			return func() { CacheTrack.SetMarkerThreshold(n) }, nil
+		},
+	}
+	return admin.NewSettings(settings)
+}
+
+// requireAdmin only lets requests through that carry ADMIN_TOKEN as a bearer token.
+func (fe *frontendServer) requireAdmin(next httpHandler) httpHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if fe.adminToken == "" {
+			writeJSONError(w, http.StatusForbidden, errors.New("the admin API is disabled, set ADMIN_TOKEN to enable it"))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(fe.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeJSONError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (fe *frontendServer) getAdminSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, fe.admin.Current())
+}
+
+func (fe *frontendServer) updateAdminSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	var values map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errors.Wrap(err, "settings must be a JSON object of strings"))
+		return
+	}
+	current, err := fe.admin.Update(r.Context(), log, values)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, current)
+}
+
+func (fe *frontendServer) getServiceSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	client, ok := fe.adminSvcClient[mux.Vars(r)["service"]]
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, errors.Errorf("no admin service for %q", mux.Vars(r)["service"]))
+		return
+	}
+	resp, err := client.GetSettings(admin.WithToken(r.Context(), fe.adminToken), &pb.Empty{})
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(err), errors.Wrap(err, "failed to get settings"))
+		return
+	}
+	writeJSON(w, http.StatusOK, resp.Settings)
+}
+
+func (fe *frontendServer) updateServiceSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	service := mux.Vars(r)["service"]
+	client, ok := fe.adminSvcClient[service]
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, errors.Errorf("no admin service for %q", service))
+		return
+	}
+	var values map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errors.Wrap(err, "settings must be a JSON object of strings"))
+		return
+	}
+	resp, err := client.UpdateSettings(admin.WithToken(r.Context(), fe.adminToken), &pb.AdminSettings{Settings: values})
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(err), errors.Wrap(err, "failed to update settings"))
+		return
+	}
+	log.WithField("service", service).WithField("settings", values).Info("updated service settings")
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("app.admin.service", service))
+	writeJSON(w, http.StatusOK, resp.Settings)
+}
+
+// resetCacheHandler shrinks the checkout service's request cache to the
+// optional target_size, then refreshes CacheTrack so the frontend stops
+// treating the cache as full straight away.
+func (fe *frontendServer) resetCacheHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	var req struct {
+		TargetSize int64 `json:"target_size"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, errors.Wrap(err, "invalid reset request"))
+			return
+		}
+	}
+	resp, err := fe.resetCache(r.Context(), req.TargetSize)
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(errors.Cause(err)), err)
+		return
+	}
+	if err := CacheTrack.Refresh(r.Context(), fe); err != nil {
+		log.Warn(err)
+	}
+	log.WithField("cache_size", resp.CacheSize).Info("reset checkout cache")
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int64("app.cache_size", resp.CacheSize))
+	writeJSON(w, http.StatusOK, map[string]int64{"cache_size": resp.CacheSize})
+}
+
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case grpccodes.InvalidArgument:
+		return http.StatusBadRequest
+	case grpccodes.NotFound:
+		return http.StatusNotFound
+	case grpccodes.FailedPrecondition, grpccodes.AlreadyExists:
+		return http.StatusConflict
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeAdminClient is a backend's AdminService that accepts token, or any
+// token when it is empty.
+type fakeAdminClient struct {
+	token    string
+	settings map[string]string
+}
+
+func (f *fakeAdminClient) authorize(ctx context.Context) error {
+	return fakeAuthorize(ctx, f.token)
+}
+
+// fakeAuthorize is admin.Authorize for a fake backend, which accepts any
+// token when token is empty.
+func fakeAuthorize(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	if _, ok := metadata.FromIncomingContext(ctx); !ok {
+		// called directly rather than through a gRPC server
+		md, _ := metadata.FromOutgoingContext(ctx)
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	return admin.Authorize(ctx, token)
+}
+
+func (f *fakeAdminClient) GetSettings(ctx context.Context, _ *pb.Empty, _ ...grpc.CallOption) (*pb.AdminSettings, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.AdminSettings{Settings: f.settings}, nil
+}
+
+func (f *fakeAdminClient) UpdateSettings(ctx context.Context, in *pb.AdminSettings, _ ...grpc.CallOption) (*pb.AdminSettings, error) {
+	if err := f.authorize(ctx); err != nil {
+		return nil, err
+	}
+	for k, v := range in.Settings {
+		f.settings[k] = v
+	}
+	return &pb.AdminSettings{Settings: f.settings}, nil
+}
+
+func newTestAdminServer(t *testing.T) (*frontendServer, http.Handler) {
+	t.Helper()
+	CacheTrack = NewCacheTracker(35000, 30000, nil, logrus.New())
+	PercentNormal.Store(75)
+	r, err := newFaultRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fe := &frontendServer{
+		faults:         r,
+		adminToken:     "secret",
+		admin:          newAdminSettings(r),
+		adminSvcClient: map[string]pb.AdminServiceClient{"checkout": &fakeAdminClient{token: "secret", settings: map[string]string{}}},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/settings", fe.requireAdmin(fe.getAdminSettingsHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/admin/settings", fe.requireAdmin(fe.updateAdminSettingsHandler)).Methods(http.MethodPut)
+	router.HandleFunc("/admin/settings/{service}", fe.requireAdmin(fe.updateServiceSettingsHandler)).Methods(http.MethodPut)
+	return fe, withTestLog(router)
+}
+
+// withTestLog adds the request logger that logHandler would.
+func withTestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ctxKeyLog{}, logrus.New())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func adminRequest(t *testing.T, h http.Handler, method, path, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	b, _ := io.ReadAll(rec.Body)
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("%s %s returned invalid JSON %q", method, path, b)
+	}
+	return rec.Code, out
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	fe, h := newTestAdminServer(t)
+
+	if code, _ := adminRequest(t, h, http.MethodGet, "/admin/settings", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("got %d without a token, want 401", code)
+	}
+	if code, _ := adminRequest(t, h, http.MethodGet, "/admin/settings", "wrong", ""); code != http.StatusUnauthorized {
+		t.Errorf("got %d with the wrong token, want 401", code)
+	}
+	fe.adminToken = ""
+	if code, _ := adminRequest(t, h, http.MethodGet, "/admin/settings", "", ""); code != http.StatusForbidden {
+		t.Errorf("got %d with the admin API disabled, want 403", code)
+	}
+}
+
+func TestAdminUpdateSettings(t *testing.T) {
+	_, h := newTestAdminServer(t)
+
+	code, got := adminRequest(t, h, http.MethodPut, "/admin/settings", "secret",
+		`{"percent_normal": "10", "cache_user_threshold": "100", "fault.product-timeout.enabled": "false"}`)
+	if code != http.StatusOK {
+		t.Fatalf("got %d: %v", code, got)
+	}
+	if PercentNormal.Load() != 10 || got["percent_normal"] != "10" {
+		t.Errorf("percent_normal not applied: %v", got)
+	}
+	if user, _ := CacheTrack.Thresholds(); user != 100 {
+		t.Errorf("got user threshold %d, want 100", user)
+	}
+	if got["fault.product-timeout.enabled"] != "false" {
+		t.Errorf("product-timeout still enabled: %v", got)
+	}
+
+	if code, _ := adminRequest(t, h, http.MethodPut, "/admin/settings", "secret",
+		`{"percent_normal": "200", "cache_user_threshold": "7"}`); code != http.StatusBadRequest {
+		t.Errorf("got %d for an invalid value, want 400", code)
+	}
+	if user, _ := CacheTrack.Thresholds(); user != 100 {
+		t.Errorf("a rejected update changed the user threshold to %d", user)
+	}
+
+	code, got = adminRequest(t, h, http.MethodPut, "/admin/settings/checkout", "secret", `{"cache_user_threshold": "5"}`)
+	if code != http.StatusOK || got["cache_user_threshold"] != "5" {
+		t.Errorf("got %d %v from checkout", code, got)
+	}
+	if code, _ := adminRequest(t, h, http.MethodPut, "/admin/settings/nope", "secret", `{}`); code != http.StatusNotFound {
+		t.Errorf("got %d for an unknown service, want 404", code)
+	}
+}
+
+func TestAdminRejectsInvalidSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name, body string
+	}{
+		{"percent_normal above 100", `{"percent_normal": "101"}`},
+		{"negative percent_normal", `{"percent_normal": "-1"}`},
+		{"cache_user_threshold not a number", `{"cache_user_threshold": "many"}`},
+		{"zero cache_user_threshold", `{"cache_user_threshold": "0"}`},
+		{"negative cache_user_threshold", `{"cache_user_threshold": "-5"}`},
+		{"zero cache_marker_threshold", `{"cache_marker_threshold": "0"}`},
+		{"negative cache_marker_threshold", `{"cache_marker_threshold": "-5"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, h := newTestAdminServer(t)
+			if code, got := adminRequest(t, h, http.MethodPut, "/admin/settings", "secret", tc.body); code != http.StatusBadRequest {
+				t.Errorf("got %d %v, want 400", code, got)
+			}
+			if user, marker := CacheTrack.Thresholds(); user != 35000 || marker != 30000 || PercentNormal.Load() != 75 {
+				t.Errorf("a rejected update changed the settings to %d, %d and %d%%", user, marker, PercentNormal.Load())
+			}
+		})
+	}
+}
+
+func TestAdminCatalog(t *testing.T) {
+	shop := newFakeShop()
+	shop.adminToken = "secret"
+	fe := shop.server()
+	fe.adminToken = "secret"
+	h := fe.handler(&logrus.Logger{Out: io.Discard, Formatter: new(logrus.TextFormatter), Level: logrus.PanicLevel})
+
+	product := `{"name": "Mug", "categories": ["kitchen"], "price": {"currency_code": "USD", "units": 8, "nanos": 0}}`
+	if code, _ := adminRequest(t, h, http.MethodPost, "/admin/catalog/products", "", product); code != http.StatusUnauthorized {
+		t.Errorf("got %d without a token, want 401", code)
+	}
+	code, got := adminRequest(t, h, http.MethodPost, "/admin/catalog/products", "secret", product)
+	if code != http.StatusCreated || got["id"] != "NEWPRODUCT" {
+		t.Fatalf("got %d %v", code, got)
+	}
+	if code, got := adminRequest(t, h, http.MethodPut, "/admin/catalog/products/NEWPRODUCT", "secret",
+		`{"id": "OLJCESPC7Z", "name": "Mug"}`); code != http.StatusBadRequest {
+		t.Errorf("got %d %v for an ID that doesn't match the path, want 400", code, got)
+	}
+	code, got = adminRequest(t, h, http.MethodPut, "/admin/catalog/products/NEWPRODUCT/price", "secret",
+		`{"currency_code": "USD", "units": 9, "nanos": 500000000}`)
+	if price, _ := got["price"].(map[string]interface{}); code != http.StatusOK || price["units"] != 9.0 {
+		t.Errorf("got %d %v", code, got)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/catalog", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d", rec.Code)
+	}
+	for _, want := range []string{`<tr data-id="NEWPRODUCT">`, `value="9.50" aria-label="Price of Mug"`, "Catalog (3 products)", `<form id="reset-cache"`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("the page doesn't contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,541 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/frontend/money"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The /api/v1 routes serve the shop as JSON for clients that cannot use the
+// HTML pages. The cart and orders belong to the session cookie, like the pages.
+
+type apiMoney struct {
+	CurrencyCode string `json:"currency_code"`
+	Units        int64  `json:"units"`
+	Nanos        int32  `json:"nanos"`
+}
+
+func newAPIMoney(m *pb.Money) apiMoney {
+	return apiMoney{CurrencyCode: m.GetCurrencyCode(), Units: m.GetUnits(), Nanos: m.GetNanos()}
+}
+
+type apiProduct struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Picture     string   `json:"picture"`
+	Categories  []string `json:"categories"`
+	Price       apiMoney `json:"price"`
+}
+
+type apiCartItem struct {
+	Product  apiProduct `json:"product"`
+	Quantity int32      `json:"quantity"`
+	Total    apiMoney   `json:"total"`
+}
+
+// apiUnavailableItem is a cart item whose product is no longer in the catalog.
+type apiUnavailableItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int32  `json:"quantity"`
+}
+
+type apiShippingOption struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Carrier           string   `json:"carrier"`
+	Cost              apiMoney `json:"cost"`
+	BusinessDays      int32    `json:"business_days"`
+	EstimatedDelivery string   `json:"estimated_delivery_date"`
+}
+
+type apiCart struct {
+	Items            []apiCartItem        `json:"items"`
+	UnavailableItems []apiUnavailableItem `json:"unavailable_items,omitempty"`
+	ShippingOptions  []apiShippingOption  `json:"shipping_options"`
+	ShippingOptionID string               `json:"shipping_option_id"`
+	ShippingCost     apiMoney             `json:"shipping_cost"`
+	Total            apiMoney             `json:"total"`
+}
+
+type apiAddress struct {
+	StreetAddress string `json:"street_address"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	Country       string `json:"country"`
+	ZipCode       int32  `json:"zip_code"`
+}
+
+type apiCreditCard struct {
+	Number          string `json:"number"`
+	ExpirationMonth int32  `json:"expiration_month"`
+	ExpirationYear  int32  `json:"expiration_year"`
+	CVV             int32  `json:"cvv"`
+}
+
+type apiOrderItem struct {
+	ProductID string   `json:"product_id"`
+	Quantity  int32    `json:"quantity"`
+	Cost      apiMoney `json:"cost"`
+}
+
+type apiDiscount struct {
+	PromotionID string   `json:"promotion_id"`
+	Code        string   `json:"code,omitempty"`
+	Description string   `json:"description"`
+	Amount      apiMoney `json:"amount"`
+	ProductIDs  []string `json:"product_ids"`
+}
+
+type apiOrder struct {
+	OrderID            string         `json:"order_id"`
+	ShippingTrackingID string         `json:"shipping_tracking_id"`
+	ShippingCost       apiMoney       `json:"shipping_cost"`
+	ShippingAddress    apiAddress     `json:"shipping_address"`
+	Items              []apiOrderItem `json:"items"`
+	Subtotal           apiMoney       `json:"subtotal"`
+	Discounts          []apiDiscount  `json:"discounts"`
+	Tax                apiMoney       `json:"tax"`
+	Total              apiMoney       `json:"total"`
+	PlacedAt           int64          `json:"placed_at"`
+}
+
+func newAPIOrder(o *pb.OrderResult) apiOrder {
+	a := o.GetShippingAddress()
+	out := apiOrder{
+		OrderID:            o.GetOrderId(),
+		ShippingTrackingID: o.GetShippingTrackingId(),
+		ShippingCost:       newAPIMoney(o.GetShippingCost()),
+		ShippingAddress: apiAddress{
+			StreetAddress: a.GetStreetAddress(),
+			City:          a.GetCity(),
+			State:         a.GetState(),
+			Country:       a.GetCountry(),
+			ZipCode:       a.GetZipCode(),
+		},
+		Items:     make([]apiOrderItem, len(o.GetItems())),
+		Discounts: make([]apiDiscount, len(o.GetDiscounts())),
+		PlacedAt:  o.GetPlacedAt(),
+	}
+	for i, it := range o.GetItems() {
+		out.Items[i] = apiOrderItem{
+			ProductID: it.GetItem().GetProductId(),
+			Quantity:  it.GetItem().GetQuantity(),
+			Cost:      newAPIMoney(it.GetCost()),
+		}
+	}
+	for i, d := range o.GetDiscounts() {
+		out.Discounts[i] = apiDiscount{
+			PromotionID: d.GetPromotionId(),
+			Code:        d.GetCode(),
+			Description: d.GetDescription(),
+			Amount:      newAPIMoney(d.GetAmount()),
+			ProductIDs:  append([]string{}, d.GetProductIds()...),
+		}
+	}
+	subtotal, total := orderSubtotal(o), orderTotal(o)
+	out.Subtotal = newAPIMoney(subtotal)
+	out.Tax = apiMoney{CurrencyCode: total.GetCurrencyCode()}
+	if o.GetTax() != nil {
+		out.Tax = newAPIMoney(o.GetTax())
+	}
+	out.Total = newAPIMoney(total)
+	return out
+}
+
+type apiAddToCartRequest struct {
+	ProductID string `json:"product_id"`
+	Quantity  int64  `json:"quantity"`
+}
+
+type apiUpdateCartItemRequest struct {
+	Quantity int64 `json:"quantity"`
+}
+
+type apiCheckoutRequest struct {
+	Email      string        `json:"email"`
+	Address    apiAddress    `json:"address"`
+	CreditCard apiCreditCard `json:"credit_card"`
+	PromoCode  string        `json:"promo_code"`
+	// ShippingOptionID is one of the cart's shipping_options, or empty for
+	// the first.
+	ShippingOptionID string `json:"shipping_option_id"`
+}
+
+// apiRoutes registers the /api/v1 routes on r.
+func (fe *frontendServer) apiRoutes(r *mux.Router) {
+	api := r.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/products", instrumentHandler(fe.apiListProductsHandler)).Methods(http.MethodGet)
+	api.HandleFunc("/products/{id}", instrumentHandler(fe.apiGetProductHandler)).Methods(http.MethodGet)
+	api.HandleFunc("/cart", instrumentHandler(fe.apiGetCartHandler)).Methods(http.MethodGet)
+	api.HandleFunc("/cart", instrumentHandler(fe.apiEmptyCartHandler)).Methods(http.MethodDelete)
+	api.HandleFunc("/cart/items", instrumentHandler(fe.apiAddToCartHandler)).Methods(http.MethodPost)
+	api.HandleFunc("/cart/items/{id}", instrumentHandler(fe.apiUpdateCartItemHandler)).Methods(http.MethodPut)
+	api.HandleFunc("/cart/items/{id}", instrumentHandler(fe.apiRemoveCartItemHandler)).Methods(http.MethodDelete)
+	api.HandleFunc("/currencies", instrumentHandler(fe.apiCurrenciesHandler)).Methods(http.MethodGet)
+	api.HandleFunc("/checkout", instrumentHandler(fe.apiCheckoutHandler)).Methods(http.MethodPost)
+	api.HandleFunc("/recommendations", instrumentHandler(fe.apiRecommendationsHandler)).Methods(http.MethodGet)
+}
+
+// apiCurrency is the currency prices are converted to: the currency query
+// parameter if given, otherwise the currency the session chose in the shop.
+func apiCurrency(r *http.Request) (string, error) {
+	cur := r.URL.Query().Get("currency")
+	if cur == "" {
+		return currentCurrency(r), nil
+	}
+	if !whitelistedCurrencies[cur] {
+		return "", errors.Errorf("unsupported currency %q", cur)
+	}
+	return cur, nil
+}
+
+func newAPIProduct(p *pb.Product, price *pb.Money) apiProduct {
+	return apiProduct{
+		ID:          p.GetId(),
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Picture:     p.GetPicture(),
+		Categories:  p.GetCategories(),
+		Price:       newAPIMoney(price),
+	}
+}
+
+// localizedProducts converts the prices of products to currency.
+func (fe *frontendServer) localizedProducts(ctx context.Context, products []*pb.Product, currency string) ([]apiProduct, error) {
+	prices, err := fe.localizedPrices(ctx, products, currency)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]apiProduct, len(products))
+	for i, p := range products {
+		out[i] = newAPIProduct(p, prices[i])
+	}
+	return out, nil
+}
+
+func (fe *frontendServer) apiListProductsHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	products, err := fe.getProducts(r.Context())
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "could not retrieve products"), httpStatusFromGRPC(err))
+		return
+	}
+	out, err := fe.localizedProducts(r.Context(), products, currency)
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"products": out})
+}
+
+func (fe *frontendServer) apiGetProductHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	id := mux.Vars(r)["id"]
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	p, err := fe.getProduct(r.Context(), id)
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrapf(err, "could not retrieve product %s", id), httpStatusFromGRPC(err))
+		return
+	}
+	out, err := fe.localizedProducts(r.Context(), []*pb.Product{p}, currency)
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, out[0])
+}
+
+func (fe *frontendServer) apiGetCartHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	cart, err := fe.apiCart(r.Context(), sessionID(r), currency, r.URL.Query().Get("shipping_option"))
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, cart)
+}
+
+// apiCart returns the user's cart with prices, shipping and total in currency.
+// The total includes the shipping option with ID shippingOption, or the first
+// one if the cart can't be shipped that way.
+func (fe *frontendServer) apiCart(ctx context.Context, userID, currency, shippingOption string) (apiCart, error) {
+	cart, err := fe.getCart(ctx, userID)
+	if err != nil {
+		return apiCart{}, errors.Wrap(err, "could not retrieve cart")
+	}
+	products, _, err := fe.getProductsByID(ctx, cartIDs(cart))
+	if err != nil {
+		return apiCart{}, errors.Wrap(err, "could not retrieve products")
+	}
+	shippingOptions, selected, err := fe.getShippingQuote(ctx, cart, products, cartQuoteAddress, currency, shippingOption)
+	if err != nil {
+		return apiCart{}, errors.Wrap(err, "failed to get shipping quote")
+	}
+	cartProducts, err := fe.getCartProducts(ctx, cart, products, currency)
+	if err != nil {
+		return apiCart{}, err
+	}
+	out := apiCart{Items: make([]apiCartItem, 0, len(cart))}
+	total := &pb.Money{CurrencyCode: currency}
+	for _, cp := range cartProducts {
+		if cp.product == nil {
+			out.UnavailableItems = append(out.UnavailableItems, apiUnavailableItem{ProductID: cp.item.GetProductId(), Quantity: cp.item.GetQuantity()})
+			continue
+		}
+		itemTotal := money.MultiplySlow(money.Value(cp.price), uint32(cp.item.GetQuantity()))
+		out.Items = append(out.Items, apiCartItem{Product: newAPIProduct(cp.product, cp.price), Quantity: cp.item.GetQuantity(), Total: newAPIMoney(&itemTotal)})
+		total = addMoney(total, &itemTotal)
+	}
+	for _, o := range shippingOptions {
+		out.ShippingOptions = append(out.ShippingOptions, apiShippingOption{
+			ID:                o.ID,
+			Name:              o.Name,
+			Carrier:           o.Carrier,
+			Cost:              newAPIMoney(o.Cost),
+			BusinessDays:      o.BusinessDays,
+			EstimatedDelivery: o.EstimatedDelivery,
+		})
+	}
+	shipping := shippingOptions[selected]
+	total = addMoney(total, shipping.Cost)
+	out.ShippingOptionID = shipping.ID
+	out.ShippingCost = newAPIMoney(shipping.Cost)
+	out.Total = newAPIMoney(total)
+	return out, nil
+}
+
+func (fe *frontendServer) apiAddToCartHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	var req apiAddToCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+	if req.ProductID == "" || req.Quantity <= 0 || req.Quantity > math.MaxInt32 {
+		renderJSONError(log, r, w, errors.New("product_id and a positive quantity are required"), http.StatusBadRequest)
+		return
+	}
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	log.WithField("product", req.ProductID).WithField("quantity", req.Quantity).Debug("adding to cart")
+
+	p, err := fe.getProduct(r.Context(), req.ProductID)
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "could not retrieve product"), httpStatusFromGRPC(err))
+		return
+	}
+	if err := fe.checkStock(r.Context(), log, sessionID(r), p.GetId(), int32(req.Quantity)); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "could not add to cart"), stockErrorStatus(err, httpStatusFromGRPC(err)))
+		return
+	}
+	if err := fe.insertCart(r.Context(), sessionID(r), p.GetId(), int32(req.Quantity)); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "failed to add to cart"), httpStatusFromGRPC(err))
+		return
+	}
+	cart, err := fe.apiCart(r.Context(), sessionID(r), currency, "")
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, cart)
+}
+
+func (fe *frontendServer) apiUpdateCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	var req apiUpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+	if req.Quantity <= 0 || req.Quantity > math.MaxInt32 {
+		renderJSONError(log, r, w, errors.New("a positive quantity is required, DELETE the item to remove it"), http.StatusBadRequest)
+		return
+	}
+	fe.apiSetCartQuantity(w, r, int32(req.Quantity))
+}
+
+func (fe *frontendServer) apiRemoveCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	fe.apiSetCartQuantity(w, r, 0)
+}
+
+// apiSetCartQuantity changes the quantity of the cart item in the path and
+// responds with the cart. More of an item must be in stock, like adding it.
+func (fe *frontendServer) apiSetCartQuantity(w http.ResponseWriter, r *http.Request, quantity int32) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	productID := mux.Vars(r)["id"]
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	log.WithField("product", productID).WithField("quantity", quantity).Debug("changing cart quantity")
+
+	defer fe.lockCart(sessionID(r))()
+	cart, err := fe.getCart(r.Context(), sessionID(r))
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "could not retrieve cart"), httpStatusFromGRPC(err))
+		return
+	}
+	inCart := int64(0)
+	for _, it := range cart {
+		if it.GetProductId() == productID {
+			inCart += int64(it.GetQuantity())
+		}
+	}
+	if inCart == 0 {
+		renderJSONError(log, r, w, errors.Errorf("product %s is not in the cart", productID), http.StatusNotFound)
+		return
+	}
+	if more := int64(quantity) - inCart; more > 0 {
+		if err := fe.checkStock(r.Context(), log, sessionID(r), productID, int32(more)); err != nil {
+			renderJSONError(log, r, w, errors.Wrap(err, "could not change the quantity"), stockErrorStatus(err, httpStatusFromGRPC(err)))
+			return
+		}
+	}
+	if err := fe.setCartQuantity(r.Context(), sessionID(r), cart, productID, quantity); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "failed to change the cart"), httpStatusFromGRPC(err))
+		return
+	}
+	out, err := fe.apiCart(r.Context(), sessionID(r), currency, "")
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (fe *frontendServer) apiEmptyCartHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	log.Debug("emptying cart")
+	if err := fe.emptyCart(r.Context(), sessionID(r)); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "failed to empty cart"), httpStatusFromGRPC(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (fe *frontendServer) apiCurrenciesHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	currencies, err := fe.getCurrencies(r.Context())
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "could not retrieve currencies"), httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"currencies": currencies})
+}
+
+func (fe *frontendServer) apiCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	var req apiCheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+	if req.Email == "" || req.CreditCard.Number == "" {
+		renderJSONError(log, r, w, errors.New("email and credit_card.number are required"), http.StatusBadRequest)
+		return
+	}
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	log.Debug("placing order")
+
+	order, err := fe.placeOrder(orderContext(r), &pb.PlaceOrderRequest{
+		Email: req.Email,
+		CreditCard: &pb.CreditCardInfo{
+			CreditCardNumber:          req.CreditCard.Number,
+			CreditCardExpirationMonth: req.CreditCard.ExpirationMonth,
+			CreditCardExpirationYear:  req.CreditCard.ExpirationYear,
+			CreditCardCvv:             req.CreditCard.CVV},
+		UserId:       sessionID(r),
+		UserCurrency: currency,
+		Address: &pb.Address{
+			StreetAddress: req.Address.StreetAddress,
+			City:          req.Address.City,
+			State:         req.Address.State,
+			ZipCode:       req.Address.ZipCode,
+			Country:       req.Address.Country},
+		PromoCode:        req.PromoCode,
+		ShippingOptionId: req.ShippingOptionID,
+	})
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "failed to complete the order"), httpStatusFromGRPC(err))
+		return
+	}
+	log.WithField("order", order.GetOrderId()).Info("order placed")
+	total := orderTotal(order)
+	trace.SpanFromContext(r.Context()).SetAttributes(cartTotalAttribute(total))
+
+	writeJSON(w, http.StatusCreated, newAPIOrder(order))
+}
+
+func (fe *frontendServer) apiRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	currency, err := apiCurrency(r)
+	if err != nil {
+		renderJSONError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	products, err := fe.getRecommendations(r.Context(), sessionID(r), r.URL.Query()["product_id"])
+	if err != nil {
+		renderJSONError(log, r, w, errors.Wrap(err, "failed to get product recommendations"), httpStatusFromGRPC(err))
+		return
+	}
+	out, err := fe.localizedProducts(r.Context(), products, currency)
+	if err != nil {
+		renderJSONError(log, r, w, err, httpStatusFromGRPC(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"products": out})
+}
+
+// renderJSONError is renderHTTPError for the API: it logs the error and
+// responds with it as a JSON object.
+func renderJSONError(log logrus.FieldLogger, r *http.Request, w http.ResponseWriter, err error, code int) {
+	span := trace.SpanFromContext(r.Context())
+	traceID := span.SpanContext().TraceID().String()
+	log.WithFields(map[string]interface{}{
+		"error":    err,
+		"trace_id": traceID,
+		"span_id":  span.SpanContext().SpanID().String(),
+	}).Error("HTTP request error")
+	if code >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	writeJSON(w, code, map[string]interface{}{
+		"error":      err.Error(),
+		"status":     code,
+		"request_id": r.Context().Value(ctxKeyRequestID{}),
+		"trace_id":   traceID,
+	})
+}
@@ -0,0 +1,666 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeShop stands in for the backends behind the shop pages and the API. It
+// converts to EUR at a rate of 2 and charges 5 USD shipping.
+type fakeShop struct {
+	pb.ProductCatalogServiceClient
+	pb.CurrencyServiceClient
+	pb.CartServiceClient
+	pb.ShippingServiceClient
+	pb.RecommendationServiceClient
+	pb.CheckoutServiceClient
+	pb.AdServiceClient
+	pb.InventoryServiceClient
+
+	lock     sync.Mutex
+	products []*pb.Product
+	stock    map[string]int32
+	carts    map[string][]*pb.CartItem
+	placed   []*pb.PlaceOrderRequest
+	orders   map[string][]*pb.OrderResult
+	// addErrs are returned by the next calls to AddItem, which fail when
+	// theirs isn't nil
+	addErrs []error
+	// quoted is the last shipping quote request
+	quoted *pb.GetQuoteRequest
+	// adminToken is the token the catalog changes need, or empty for none
+	adminToken string
+	// delay is how long each product lookup and conversion takes, for benchmarks
+	delay time.Duration
+	// cartDelay is how long each change to a cart takes
+	cartDelay time.Duration
+}
+
+func newFakeShop() *fakeShop {
+	return &fakeShop{
+		products: []*pb.Product{
+			{Id: "OLJCESPC7Z", Name: "Sunglasses", PriceUsd: &pb.Money{CurrencyCode: "USD", Units: 19, Nanos: 990000000}, Categories: []string{"accessories"}},
+			{Id: "66VCHSJNUP", Name: "Tank Top", PriceUsd: &pb.Money{CurrencyCode: "USD", Units: 18, Nanos: 990000000}, Categories: []string{"clothing"}},
+		},
+		stock:  map[string]int32{"OLJCESPC7Z": 100, "66VCHSJNUP": 3},
+		carts:  map[string][]*pb.CartItem{},
+		orders: map[string][]*pb.OrderResult{},
+	}
+}
+
+func (f *fakeShop) server() *frontendServer {
+	return &frontendServer{
+		productCatalogSvcClient: f,
+		currencySvcClient:       f,
+		cartSvcClient:           f,
+		shippingSvcClient:       f,
+		recommendationSvcClient: f,
+		checkoutSvcClient:       f,
+		adSvcClient:             f,
+		inventorySvcClient:      f,
+	}
+}
+
+func (f *fakeShop) ListProducts(_ context.Context, _ *pb.Empty, _ ...grpc.CallOption) (*pb.ListProductsResponse, error) {
+	return &pb.ListProductsResponse{Products: f.products}, nil
+}
+
+func (f *fakeShop) GetProduct(_ context.Context, in *pb.GetProductRequest, _ ...grpc.CallOption) (*pb.Product, error) {
+	time.Sleep(f.delay)
+	for _, p := range f.products {
+		if p.Id == in.Id {
+			return p, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "no product with ID %s", in.Id)
+}
+
+func (f *fakeShop) GetProducts(_ context.Context, in *pb.GetProductsRequest, _ ...grpc.CallOption) (*pb.GetProductsResponse, error) {
+	time.Sleep(f.delay)
+	res := &pb.GetProductsResponse{}
+	for _, id := range in.Ids {
+		found := false
+		for _, p := range f.products {
+			if p.Id == id {
+				res.Products = append(res.Products, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.MissingIds = append(res.MissingIds, id)
+		}
+	}
+	return res, nil
+}
+
+// GetStock returns the stock of the products in the stock map.
+func (f *fakeShop) GetStock(_ context.Context, in *pb.GetStockRequest, _ ...grpc.CallOption) (*pb.GetStockResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	res := &pb.GetStockResponse{}
+	for _, id := range in.ProductIds {
+		if n, ok := f.stock[id]; ok {
+			res.Levels = append(res.Levels, &pb.StockLevel{ProductId: id, Available: n})
+		}
+	}
+	return res, nil
+}
+
+// SearchProducts matches the query against product names, one result per
+// page.
+func (f *fakeShop) SearchProducts(_ context.Context, in *pb.SearchProductsRequest, _ ...grpc.CallOption) (*pb.SearchProductsResponse, error) {
+	if in.PageToken != "" && in.PageToken != "page-2" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", in.PageToken)
+	}
+	res := &pb.SearchProductsResponse{}
+	categories := map[string]int32{}
+	for _, p := range f.products {
+		if !strings.Contains(strings.ToLower(p.Name), strings.ToLower(in.Query)) {
+			continue
+		}
+		for _, c := range p.Categories {
+			categories[c]++
+		}
+		if len(in.Categories) > 0 && in.Categories[0] != p.Categories[0] {
+			continue
+		}
+		res.Results = append(res.Results, p)
+	}
+	for c, n := range categories {
+		res.CategoryFacets = append(res.CategoryFacets, &pb.CategoryFacet{Category: c, Count: n})
+	}
+	sort.Slice(res.CategoryFacets, func(i, j int) bool { return res.CategoryFacets[i].Category < res.CategoryFacets[j].Category })
+	res.TotalSize = int32(len(res.Results))
+	if len(res.Results) > 0 {
+		res.PriceFacets = []*pb.PriceFacet{{Min: &pb.Money{CurrencyCode: "USD"}, Max: &pb.Money{CurrencyCode: "USD", Units: 25}, Count: res.TotalSize}}
+	}
+	if in.PageToken == "page-2" {
+		res.Results = res.Results[min(1, len(res.Results)):]
+	} else if len(res.Results) > 1 {
+		res.Results = res.Results[:1]
+		res.NextPageToken = "page-2"
+	}
+	return res, nil
+}
+
+// CreateProduct, UpdateProduct, DeleteProduct and SetPrice only check that
+// prices are in USD and that the product is (or isn't) in the catalog.
+func (f *fakeShop) CreateProduct(ctx context.Context, in *pb.CreateProductRequest, _ ...grpc.CallOption) (*pb.Product, error) {
+	if err := fakeAuthorize(ctx, f.adminToken); err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	p := proto.Clone(in.Product).(*pb.Product)
+	if p.Id == "" {
+		p.Id = "NEWPRODUCT"
+	}
+	if p.PriceUsd.GetCurrencyCode() != "USD" {
+		return nil, status.Error(codes.InvalidArgument, "prices must be in USD")
+	}
+	if f.indexOfProduct(p.Id) >= 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "product %s already exists", p.Id)
+	}
+	f.products = append(f.products, p)
+	return p, nil
+}
+
+func (f *fakeShop) UpdateProduct(ctx context.Context, in *pb.UpdateProductRequest, _ ...grpc.CallOption) (*pb.Product, error) {
+	if err := fakeAuthorize(ctx, f.adminToken); err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	i := f.indexOfProduct(in.Product.Id)
+	if i < 0 {
+		return nil, status.Errorf(codes.NotFound, "no product with ID %s", in.Product.Id)
+	}
+	f.products[i] = proto.Clone(in.Product).(*pb.Product)
+	return f.products[i], nil
+}
+
+func (f *fakeShop) DeleteProduct(ctx context.Context, in *pb.DeleteProductRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	if err := fakeAuthorize(ctx, f.adminToken); err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	i := f.indexOfProduct(in.Id)
+	if i < 0 {
+		return nil, status.Errorf(codes.NotFound, "no product with ID %s", in.Id)
+	}
+	f.products = append(f.products[:i:i], f.products[i+1:]...)
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeShop) SetPrice(ctx context.Context, in *pb.SetPriceRequest, _ ...grpc.CallOption) (*pb.Product, error) {
+	if err := fakeAuthorize(ctx, f.adminToken); err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if in.PriceUsd.GetCurrencyCode() != "USD" {
+		return nil, status.Error(codes.InvalidArgument, "prices must be in USD")
+	}
+	i := f.indexOfProduct(in.Id)
+	if i < 0 {
+		return nil, status.Errorf(codes.NotFound, "no product with ID %s", in.Id)
+	}
+	p := proto.Clone(f.products[i]).(*pb.Product)
+	p.PriceUsd = in.PriceUsd
+	f.products[i] = p
+	return p, nil
+}
+
+func (f *fakeShop) indexOfProduct(id string) int {
+	for i, p := range f.products {
+		if p.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakeShop) GetSupportedCurrencies(_ context.Context, _ *pb.Empty, _ ...grpc.CallOption) (*pb.GetSupportedCurrenciesResponse, error) {
+	return &pb.GetSupportedCurrenciesResponse{CurrencyCodes: []string{"USD", "EUR", "XXX"}}, nil
+}
+
+func (f *fakeShop) Convert(_ context.Context, in *pb.CurrencyConversionRequest, _ ...grpc.CallOption) (*pb.Money, error) {
+	time.Sleep(f.delay)
+	out := &pb.Money{CurrencyCode: in.ToCode, Units: in.From.Units, Nanos: in.From.Nanos}
+	if in.ToCode == "EUR" {
+		nanos := int64(in.From.Nanos) * 2
+		out.Units = in.From.Units*2 + nanos/1e9
+		out.Nanos = int32(nanos % 1e9)
+	}
+	return out, nil
+}
+
+func (f *fakeShop) AddItem(_ context.Context, in *pb.AddItemRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	time.Sleep(f.cartDelay)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.addErrs) > 0 {
+		err := f.addErrs[0]
+		f.addErrs = f.addErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.carts[in.UserId] = append(f.carts[in.UserId], in.Item)
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeShop) GetCart(_ context.Context, in *pb.GetCartRequest, _ ...grpc.CallOption) (*pb.Cart, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return &pb.Cart{UserId: in.UserId, Items: f.carts[in.UserId]}, nil
+}
+
+func (f *fakeShop) EmptyCart(_ context.Context, in *pb.EmptyCartRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	time.Sleep(f.cartDelay)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.carts, in.UserId)
+	return &pb.Empty{}, nil
+}
+
+func (f *fakeShop) GetQuote(_ context.Context, in *pb.GetQuoteRequest, _ ...grpc.CallOption) (*pb.GetQuoteResponse, error) {
+	f.lock.Lock()
+	f.quoted = in
+	f.lock.Unlock()
+	standard := &pb.Money{CurrencyCode: "USD", Units: 5}
+	options := []*pb.ShippingOption{
+		{Id: "standard", Name: "Standard", Carrier: "Parcel Post", CostUsd: standard, BusinessDays: 5, EstimatedDeliveryDate: "2026-10-23"},
+	}
+	if domestic(in.GetAddress()) {
+		options = append(options, &pb.ShippingOption{Id: "express", Name: "Express", Carrier: "Parcel Post", CostUsd: &pb.Money{CurrencyCode: "USD", Units: 12}, BusinessDays: 2, EstimatedDeliveryDate: "2026-10-20"})
+	}
+	return &pb.GetQuoteResponse{CostUsd: standard, Options: options}, nil
+}
+
+// domestic is whether express shipping is offered to a, which is anywhere in
+// the United States or without a country.
+func domestic(a *pb.Address) bool {
+	switch a.GetCountry() {
+	case "", "United States", "USA":
+		return true
+	}
+	return false
+}
+
+// TrackShipment knows only TRACK-1, which is in transit.
+func (f *fakeShop) TrackShipment(_ context.Context, in *pb.TrackShipmentRequest, _ ...grpc.CallOption) (*pb.TrackShipmentResponse, error) {
+	if in.TrackingId != "TRACK-1" {
+		return nil, status.Errorf(codes.NotFound, "no shipment with tracking ID %q", in.TrackingId)
+	}
+	return &pb.TrackShipmentResponse{
+		TrackingId:            in.TrackingId,
+		Status:                pb.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT,
+		Carrier:               "Parcel Post",
+		ShippingOptionId:      "standard",
+		EstimatedDeliveryDate: "2026-10-23",
+		Events: []*pb.ShipmentEvent{
+			{Status: pb.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED, Time: 1792400400, Location: "NV 89502, US", Description: "Shipping label created"},
+			{Status: pb.ShipmentStatus_SHIPMENT_STATUS_PICKED_UP, Time: 1792407600, Location: "NV 89502, US", Description: "Picked up by Parcel Post"},
+			{Status: pb.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT, Time: 1792422000, Description: "Departed the origin facility"},
+		},
+	}, nil
+}
+
+func (f *fakeShop) ListRecommendations(_ context.Context, in *pb.ListRecommendationsRequest, _ ...grpc.CallOption) (*pb.ListRecommendationsResponse, error) {
+	var ids []string
+	for _, p := range f.products {
+		if len(in.ProductIds) == 0 || p.Id != in.ProductIds[0] {
+			ids = append(ids, p.Id)
+		}
+	}
+	return &pb.ListRecommendationsResponse{ProductIds: ids}, nil
+}
+
+func (f *fakeShop) PlaceOrder(_ context.Context, in *pb.PlaceOrderRequest, _ ...grpc.CallOption) (*pb.PlaceOrderResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if in.CreditCard.GetCreditCardNumber() == "declined" {
+		return nil, status.Error(codes.InvalidArgument, "credit card declined")
+	}
+	if in.PromoCode != "" && in.PromoCode != "SAVE1" {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown promo code %q", in.PromoCode)
+	}
+	shipping := int64(5)
+	switch in.ShippingOptionId {
+	case "", "standard":
+	case "express":
+		if !domestic(in.Address) {
+			return nil, status.Errorf(codes.InvalidArgument, "shipping option %q isn't available for this order", in.ShippingOptionId)
+		}
+		shipping = 12
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown shipping option %q", in.ShippingOptionId)
+	}
+	f.placed = append(f.placed, in)
+	var items []*pb.OrderItem
+	var units int64
+	for _, it := range f.carts[in.UserId] {
+		items = append(items, &pb.OrderItem{Item: it, Cost: &pb.Money{CurrencyCode: in.UserCurrency, Units: 10}})
+		units += 10 * int64(it.GetQuantity())
+	}
+	delete(f.carts, in.UserId)
+	order := &pb.OrderResult{
+		OrderId:            fmt.Sprintf("order-%d", len(f.placed)),
+		ShippingTrackingId: "TRACK-1",
+		ShippingCost:       &pb.Money{CurrencyCode: in.UserCurrency, Units: shipping},
+		ShippingAddress:    in.Address,
+		Items:              items,
+		PlacedAt:           1700000000,
+		Subtotal:           &pb.Money{CurrencyCode: in.UserCurrency, Units: units},
+		Tax:                &pb.Money{CurrencyCode: in.UserCurrency, Units: units / 10},
+	}
+	if in.PromoCode != "" {
+		order.Discounts = []*pb.AppliedDiscount{{
+			PromotionId: "save-1",
+			Code:        in.PromoCode,
+			Description: "1 off",
+			Amount:      &pb.Money{CurrencyCode: in.UserCurrency, Units: 1},
+			ProductIds:  []string{"OLJCESPC7Z"},
+		}}
+	}
+	f.orders[in.UserId] = append(f.orders[in.UserId], order)
+	return &pb.PlaceOrderResponse{Order: order}, nil
+}
+
+func (f *fakeShop) GetOrder(_ context.Context, in *pb.GetOrderRequest, _ ...grpc.CallOption) (*pb.OrderResult, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, o := range f.orders[in.UserId] {
+		if o.OrderId == in.OrderId {
+			return o, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "no order %s", in.OrderId)
+}
+
+func (f *fakeShop) ListOrdersByUser(_ context.Context, in *pb.ListOrdersByUserRequest, _ ...grpc.CallOption) (*pb.ListOrdersByUserResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return &pb.ListOrdersByUserResponse{Orders: f.orders[in.UserId]}, nil
+}
+
+func (f *fakeShop) GetAds(_ context.Context, _ *pb.AdRequest, _ ...grpc.CallOption) (*pb.AdResponse, error) {
+	return &pb.AdResponse{Ads: []*pb.Ad{{RedirectUrl: "/product/OLJCESPC7Z", Text: "Sunglasses for half price"}}}, nil
+}
+
+// withTestSession adds the session and request ids that ensureSessionID and
+// logHandler would.
+func withTestSession(userID string, next http.Handler) http.Handler {
+	return withTestLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ctxKeySessionID{}, userID)
+		ctx = context.WithValue(ctx, ctxKeyRequestID{}, "req-1")
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}))
+}
+
+func newTestAPI(t *testing.T) (*fakeShop, http.Handler) {
+	t.Helper()
+	shop := newFakeShop()
+	r := mux.NewRouter()
+	shop.server().apiRoutes(r)
+	return shop, withTestSession("user-1", r)
+}
+
+func apiRequest(t *testing.T, h http.Handler, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if rec.Code == http.StatusNoContent {
+		return rec.Code, nil
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%s %s returned invalid JSON %q", method, path, rec.Body.String())
+	}
+	return rec.Code, out
+}
+
+func TestAPIProducts(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	code, got := apiRequest(t, h, http.MethodGet, "/api/v1/products?currency=EUR", "")
+	if code != http.StatusOK {
+		t.Fatalf("got %d: %v", code, got)
+	}
+	products := got["products"].([]interface{})
+	if len(products) != 2 {
+		t.Fatalf("got %d products, want 2", len(products))
+	}
+	price := products[0].(map[string]interface{})["price"].(map[string]interface{})
+	if price["currency_code"] != "EUR" || price["units"] != 39.0 || price["nanos"] != 980000000.0 {
+		t.Errorf("got price %v, want EUR 39.98", price)
+	}
+
+	code, got = apiRequest(t, h, http.MethodGet, "/api/v1/products/66VCHSJNUP", "")
+	if code != http.StatusOK || got["name"] != "Tank Top" {
+		t.Errorf("got %d %v for a product", code, got)
+	}
+	code, got = apiRequest(t, h, http.MethodGet, "/api/v1/products/nope", "")
+	if code != http.StatusNotFound || got["error"] == nil || got["request_id"] != "req-1" {
+		t.Errorf("got %d %v for a missing product, want a 404 error body", code, got)
+	}
+	if code, _ := apiRequest(t, h, http.MethodGet, "/api/v1/products?currency=XXX", ""); code != http.StatusBadRequest {
+		t.Errorf("got %d for an unsupported currency, want 400", code)
+	}
+
+	code, got = apiRequest(t, h, http.MethodGet, "/api/v1/currencies", "")
+	if currencies := got["currencies"].([]interface{}); code != http.StatusOK || len(currencies) != 2 {
+		t.Errorf("got %d %v, want only the whitelisted currencies", code, got)
+	}
+	code, got = apiRequest(t, h, http.MethodGet, "/api/v1/recommendations?product_id=OLJCESPC7Z", "")
+	if products := got["products"].([]interface{}); code != http.StatusOK || len(products) != 1 {
+		t.Errorf("got %d %v, want one recommendation", code, got)
+	}
+}
+
+func TestAPICartAndCheckout(t *testing.T) {
+	shop, h := newTestAPI(t)
+
+	if code, _ := apiRequest(t, h, http.MethodPost, "/api/v1/cart/items", `{"product_id": "OLJCESPC7Z", "quantity": 0}`); code != http.StatusBadRequest {
+		t.Errorf("got %d for a zero quantity, want 400", code)
+	}
+	code, got := apiRequest(t, h, http.MethodPost, "/api/v1/cart/items", `{"product_id": "OLJCESPC7Z", "quantity": 2}`)
+	if code != http.StatusOK {
+		t.Fatalf("got %d: %v", code, got)
+	}
+	total := got["total"].(map[string]interface{})
+	if items := got["items"].([]interface{}); len(items) != 1 || total["units"] != 44.0 || total["nanos"] != 980000000.0 {
+		t.Errorf("got cart %v, want 2 sunglasses and shipping totalling 44.98", got)
+	}
+	if q := shop.quoted; q.GetAddress().GetZipCode() != 94043 || q.GetSubtotalUsd().GetUnits() != 39 || q.GetSubtotalUsd().GetNanos() != 980000000 {
+		t.Errorf("shipping was quoted for %v, want the default address and a subtotal of 39.98", q)
+	}
+
+	code, got = apiRequest(t, h, http.MethodPost, "/api/v1/checkout", `{"email": "a@example.com", "credit_card": {"number": "declined"}}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("got %d %v for a declined card, want 400", code, got)
+	}
+	code, got = apiRequest(t, h, http.MethodPost, "/api/v1/checkout", `{"email": "a@example.com", "credit_card": {"number": "1"}, "promo_code": "BOGUS"}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("got %d %v for an unknown promo code, want 400", code, got)
+	}
+	code, got = apiRequest(t, h, http.MethodPost, "/api/v1/checkout?currency=EUR", `{
+		"email": "a@example.com",
+		"address": {"street_address": "1600 Amphitheatre Parkway", "city": "Mountain View", "state": "CA", "country": "USA", "zip_code": 94043},
+		"credit_card": {"number": "4432-8015-6152-0454", "expiration_month": 1, "expiration_year": 2030, "cvv": 672},
+		"promo_code": "SAVE1"
+	}`)
+	if code != http.StatusCreated || got["order_id"] != "order-1" {
+		t.Fatalf("got %d %v for checkout", code, got)
+	}
+	// two items at 10, 1 off, 2 tax and 5 shipping
+	if total := got["total"].(map[string]interface{}); total["units"] != 26.0 || got["tax"].(map[string]interface{})["units"] != 2.0 {
+		t.Errorf("got order %v, want a tax of 2 and a total of 26", got)
+	}
+	if d := got["discounts"].([]interface{}); len(d) != 1 || d[0].(map[string]interface{})["code"] != "SAVE1" {
+		t.Errorf("got discounts %v, want SAVE1", got["discounts"])
+	}
+	if req := shop.placed[len(shop.placed)-1]; req.UserId != "user-1" || req.UserCurrency != "EUR" || req.Address.ZipCode != 94043 {
+		t.Errorf("placed order %v", req)
+	}
+
+	if code, _ := apiRequest(t, h, http.MethodDelete, "/api/v1/cart", ""); code != http.StatusNoContent {
+		t.Errorf("got %d emptying the cart, want 204", code)
+	}
+	if code, got := apiRequest(t, h, http.MethodGet, "/api/v1/cart", ""); code != http.StatusOK || len(got["items"].([]interface{})) != 0 {
+		t.Errorf("got %d %v, want an empty cart", code, got)
+	}
+}
+
+func TestAPIUpdateCartItems(t *testing.T) {
+	shop, h := newTestAPI(t)
+	shop.carts["user-1"] = []*pb.CartItem{
+		{ProductId: "OLJCESPC7Z", Quantity: 1},
+		{ProductId: "66VCHSJNUP", Quantity: 1},
+		{ProductId: "OLJCESPC7Z", Quantity: 2},
+	}
+	quantities := func() map[string]int32 {
+		out := map[string]int32{}
+		for _, it := range shop.carts["user-1"] {
+			out[it.ProductId] += it.Quantity
+		}
+		return out
+	}
+
+	code, got := apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/OLJCESPC7Z", `{"quantity": 5}`)
+	if code != http.StatusOK || len(got["items"].([]interface{})) != 2 {
+		t.Fatalf("got %d %v", code, got)
+	}
+	if q := quantities(); q["OLJCESPC7Z"] != 5 || q["66VCHSJNUP"] != 1 || len(shop.carts["user-1"]) != 2 {
+		t.Errorf("got cart %v, want 5 sunglasses in one item and the tank top left alone", shop.carts["user-1"])
+	}
+	if code, _ := apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/66VCHSJNUP", `{"quantity": 4}`); code != http.StatusConflict {
+		t.Errorf("got %d for more than is in stock, want 409", code)
+	}
+	if code, _ := apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/66VCHSJNUP", `{"quantity": 0}`); code != http.StatusBadRequest {
+		t.Errorf("got %d for a zero quantity, want 400", code)
+	}
+	if code, _ := apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/GONE", `{"quantity": 1}`); code != http.StatusNotFound {
+		t.Errorf("got %d for an item not in the cart, want 404", code)
+	}
+
+	code, got = apiRequest(t, h, http.MethodDelete, "/api/v1/cart/items/OLJCESPC7Z", "")
+	if code != http.StatusOK || len(got["items"].([]interface{})) != 1 {
+		t.Fatalf("got %d %v", code, got)
+	}
+	if q := quantities(); len(q) != 1 || q["66VCHSJNUP"] != 1 {
+		t.Errorf("got cart %v, want just the tank top", shop.carts["user-1"])
+	}
+	if code, _ := apiRequest(t, h, http.MethodDelete, "/api/v1/cart/items/OLJCESPC7Z", ""); code != http.StatusNotFound {
+		t.Errorf("got %d removing an item twice, want 404", code)
+	}
+}
+
+func TestAPIUpdateCartItemsRestoresTheCart(t *testing.T) {
+	shop, h := newTestAPI(t)
+	cart := []*pb.CartItem{
+		{ProductId: "OLJCESPC7Z", Quantity: 1},
+		{ProductId: "66VCHSJNUP", Quantity: 1},
+		{ProductId: "OLJCESPC7Z", Quantity: 2},
+	}
+	shop.carts["user-1"] = cart
+	unavailable := status.Error(codes.Unavailable, "cart is down")
+
+	// the tank top fails to go back in after the sunglasses
+	shop.addErrs = []error{nil, unavailable}
+	code, got := apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/OLJCESPC7Z", `{"quantity": 5}`)
+	if code != http.StatusBadGateway {
+		t.Errorf("got %d %v, want 502", code, got)
+	}
+	if len(shop.carts["user-1"]) != len(cart) {
+		t.Fatalf("got cart %v, want it restored to %v", shop.carts["user-1"], cart)
+	}
+	for i, it := range shop.carts["user-1"] {
+		if !proto.Equal(it, cart[i]) {
+			t.Errorf("got item %d %v, want %v", i, it, cart[i])
+		}
+	}
+
+	// the cart service stays down while restoring too
+	shop.addErrs = []error{unavailable, unavailable}
+	code, got = apiRequest(t, h, http.MethodPut, "/api/v1/cart/items/OLJCESPC7Z", `{"quantity": 5}`)
+	if msg, _ := got["error"].(string); code != http.StatusBadGateway || !strings.Contains(msg, "restoring the cart failed") {
+		t.Errorf("got %d %v, want a 502 saying the cart wasn't restored", code, got)
+	}
+}
+
+func TestAPIUpdateCartItemsConcurrently(t *testing.T) {
+	shop, h := newTestAPI(t)
+	shop.carts["user-1"] = []*pb.CartItem{
+		{ProductId: "OLJCESPC7Z", Quantity: 1},
+		{ProductId: "66VCHSJNUP", Quantity: 1},
+	}
+	shop.cartDelay = time.Millisecond
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(quantity int) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/cart/items/OLJCESPC7Z", strings.NewReader(fmt.Sprintf(`{"quantity": %d}`, quantity))))
+			if rec.Code != http.StatusOK {
+				t.Errorf("got %d %s", rec.Code, rec.Body)
+			}
+		}(i)
+	}
+	wg.Wait()
+	items := map[string]int{}
+	for _, it := range shop.carts["user-1"] {
+		items[it.ProductId]++
+	}
+	if len(shop.carts["user-1"]) != 2 || items["OLJCESPC7Z"] != 1 || items["66VCHSJNUP"] != 1 {
+		t.Errorf("got cart %v, want one item of each product", shop.carts["user-1"])
+	}
+}
+
+func TestAPICartListsUnavailableItems(t *testing.T) {
+	shop, h := newTestAPI(t)
+	shop.carts["user-1"] = []*pb.CartItem{{ProductId: "OLJCESPC7Z", Quantity: 1}, {ProductId: "GONE", Quantity: 2}}
+
+	code, got := apiRequest(t, h, http.MethodGet, "/api/v1/cart", "")
+	if code != http.StatusOK {
+		t.Fatalf("got %d: %v", code, got)
+	}
+	if items := got["items"].([]interface{}); len(items) != 1 {
+		t.Errorf("got items %v, want just the sunglasses", items)
+	}
+	unavailable := got["unavailable_items"].([]interface{})
+	if len(unavailable) != 1 || unavailable[0].(map[string]interface{})["product_id"] != "GONE" {
+		t.Errorf("got unavailable items %v, want GONE", unavailable)
+	}
+	if total := got["total"].(map[string]interface{}); total["units"] != 24.0 {
+		t.Errorf("got total %v, want 24.99 without the unavailable item", total)
+	}
+}
+
+func TestAPIRejectsInvalidJSON(t *testing.T) {
+	_, h := newTestAPI(t)
+	code, got := apiRequest(t, h, http.MethodPost, "/api/v1/checkout", `{"email": `)
+	if code != http.StatusBadRequest || !strings.Contains(got["error"].(string), "invalid request body") {
+		t.Errorf("got %d %v for invalid JSON", code, got)
+	}
+}
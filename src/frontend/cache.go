@@ -1,11 +1,7 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"io"
-	"net/http"
 	"sync"
 	"time"
 
@@ -17,12 +13,12 @@ type CacheTracker struct {
 	currentSize     int
 	userThreshold   int
 	markerThreshold int
-	honeycombAPIKey string
+	markerSinks     []MarkerSink
 	log             logrus.FieldLogger
 	lock            sync.Mutex
 }
 
-func NewCacheTracker(userThreshold, markerThreshold int, apiKey string, log logrus.FieldLogger) *CacheTracker {
+func NewCacheTracker(userThreshold, markerThreshold int, sinks []MarkerSink, log logrus.FieldLogger) *CacheTracker {
 	log.WithFields(logrus.Fields{
 		"userThreshold":   userThreshold,
 		"markerThreshold": markerThreshold,
@@ -31,33 +27,63 @@ func NewCacheTracker(userThreshold, markerThreshold int, apiKey string, log logr
 	return &CacheTracker{
 		userThreshold:   userThreshold,
 		markerThreshold: markerThreshold,
-		honeycombAPIKey: apiKey,
+		markerSinks:     sinks,
 		log:             log,
 	}
 }
 
 func (c *CacheTracker) IsOverUserThreshold() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	return c.currentSize > c.userThreshold
 }
 
+// Thresholds returns the cache sizes above which user 20109 shows up and a
+// marker is created.
+func (c *CacheTracker) Thresholds() (user, marker int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.userThreshold, c.markerThreshold
+}
+
+func (c *CacheTracker) SetUserThreshold(n int) {
+	c.lock.Lock()
+	c.userThreshold = n
+	c.lock.Unlock()
+}
+
+func (c *CacheTracker) SetMarkerThreshold(n int) {
+	c.lock.Lock()
+	c.markerThreshold = n
+	c.lock.Unlock()
+}
+
 func (c *CacheTracker) Track(ctx context.Context, fe *frontendServer) {
 	c.log.Infof("starting CacheTracker.Track()")
 	ticker := time.NewTicker(10 * time.Second)
 	go func() {
 		for range ticker.C {
-			resp, err := fe.getCacheSize(ctx)
-			if err != nil {
-				c.log.Error(errors.Wrap(err, "could not fetch cache size"))
-				continue
+			if err := c.Refresh(ctx, fe); err != nil {
+				c.log.Error(err)
 			}
-			c.updateSize(int(resp.CacheSize))
-			c.log.WithFields(logrus.Fields{
-				"currentSize": c.currentSize,
-			}).Debug("fetched cache size")
 		}
 	}()
 }
 
+// Refresh fetches the cache size from the checkout service now, rather than
+// waiting for the next tick.
+func (c *CacheTracker) Refresh(ctx context.Context, fe *frontendServer) error {
+	resp, err := fe.getCacheSize(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch cache size")
+	}
+	c.updateSize(int(resp.CacheSize))
+	c.log.WithFields(logrus.Fields{
+		"currentSize": resp.CacheSize,
+	}).Debug("fetched cache size")
+	return nil
+}
+
 func (c *CacheTracker) updateSize(newSize int) {
 	c.lock.Lock()
 
@@ -69,48 +95,31 @@ func (c *CacheTracker) updateSize(newSize int) {
 		}).Debug("cache marker threshold reached")
 
 		// Send a marker in a new Go routine
-		go c.createMarker()
+		go c.createMarker(newSize, c.markerThreshold)
 	}
 
 	c.currentSize = newSize
 	c.lock.Unlock()
 }
 
-func (c *CacheTracker) createMarker() {
-
-	MockBuildId = randomHex(4) // update build id
-	c.createMarkerHoneycomb(MockBuildId)
+func (c *CacheTracker) createMarker(cacheSize, threshold int) {
 
-}
-
-func (c *CacheTracker) createMarkerHoneycomb(buildId string) {
-	if c.honeycombAPIKey == "" {
-		// Do nothing
-		return
+	buildId := randomHex(4) // update build id
+	MockBuildId.Store(buildId)
+	marker := Marker{
+		BuildID:   buildId,
+		Threshold: threshold,
+		CacheSize: cacheSize,
+		Time:      time.Now(),
 	}
-	c.log.Debug("Creating Honeycomb marker...")
 
-	url := "https://api.honeycomb.io/1/markers/__all__"
-	payload := []byte(fmt.Sprintf(`{"message":"Deploy %s", "url":"https://github.com/honeycombio/microservices-demo/commit/5645075", "type":"deploy"}`, buildId))
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
-	if err != nil {
-		c.log.Error(errors.Wrap(err, "could not create request to generate marker"))
-		return
-	}
-	req.Header.Set("X-Honeycomb-Team", c.honeycombAPIKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-
-	if err != nil {
-		c.log.Error(errors.Wrap(err, "could not create Honeycomb marker"))
-	} else if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		c.log.WithFields(logrus.Fields{
-			"satusCode": resp.StatusCode,
-		}).Error("Invalid status code when creating Honeycomb marker")
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.log.Error(string(bodyBytes))
-	} else {
-		c.log.Debug("Honeycomb marker created.")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, sink := range c.markerSinks {
+		if err := sink.CreateMarker(ctx, marker); err != nil {
+			c.log.Error(errors.Wrapf(err, "could not create marker with %T", sink))
+			continue
+		}
+		c.log.WithField("build_id", marker.BuildID).Debugf("marker created with %T", sink)
 	}
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"google.golang.org/grpc"
+)
+
+type fakeCheckoutCache struct {
+	pb.CheckoutServiceClient
+	lock sync.Mutex
+	size int64
+}
+
+func (f *fakeCheckoutCache) GetCacheSize(_ context.Context, _ *pb.Empty, _ ...grpc.CallOption) (*pb.CacheSizeResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return &pb.CacheSizeResponse{CacheSize: f.size}, nil
+}
+
+func (f *fakeCheckoutCache) ResetCache(_ context.Context, in *pb.ResetCacheRequest, _ ...grpc.CallOption) (*pb.CacheSizeResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.size = min(f.size, in.TargetSize)
+	return &pb.CacheSizeResponse{CacheSize: f.size}, nil
+}
+
+// TestResetCacheRefreshesTracker drives the cache past the user threshold and
+// checks that a reset brings CacheTrack back below it without waiting for a tick.
+func TestResetCacheRefreshesTracker(t *testing.T) {
+	fe, _ := newTestAdminServer(t)
+	checkout := &fakeCheckoutCache{size: 40000}
+	fe.checkoutSvcClient = checkout
+	fe.getCacheClient = checkout
+	router := http.NewServeMux()
+	router.HandleFunc("/admin/cache/reset", fe.requireAdmin(fe.resetCacheHandler))
+	h := withTestLog(router)
+
+	if err := CacheTrack.Refresh(context.Background(), fe); err != nil {
+		t.Fatal(err)
+	}
+	if !CacheTrack.IsOverUserThreshold() {
+		t.Fatal("tracker should be over the user threshold")
+	}
+
+	code, got := adminRequest(t, h, http.MethodPost, "/admin/cache/reset", "secret", `{"target_size": 100}`)
+	if code != http.StatusOK {
+		t.Fatalf("got %d: %v", code, got)
+	}
+	if CacheTrack.IsOverUserThreshold() {
+		t.Error("tracker is still over the user threshold after the reset")
+	}
+	if checkout.size != 100 {
+		t.Errorf("got cache size %d, want 100", checkout.size)
+	}
+
+	// an empty body clears the cache
+	if code, _ := adminRequest(t, h, http.MethodPost, "/admin/cache/reset", "secret", ""); code != http.StatusOK || checkout.size != 0 {
+		t.Errorf("got %d and cache size %d, want 200 and 0", code, checkout.size)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The /admin/catalog routes change the product catalog. The page itself only
+// shows the products, which are public anyway; its forms send ADMIN_TOKEN to
+// the JSON endpoints, which require it like the rest of the admin API.
+
+func (a apiMoney) proto() *pb.Money {
+	return &pb.Money{CurrencyCode: a.CurrencyCode, Units: a.Units, Nanos: a.Nanos}
+}
+
+func (a apiProduct) proto() *pb.Product {
+	return &pb.Product{
+		Id:          a.ID,
+		Name:        a.Name,
+		Description: a.Description,
+		Picture:     a.Picture,
+		Categories:  a.Categories,
+		PriceUsd:    a.Price.proto(),
+	}
+}
+
+func (fe *frontendServer) adminCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	span := trace.SpanFromContext(r.Context())
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	products, err := fe.getProducts(r.Context())
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve products"), http.StatusInternalServerError)
+		return
+	}
+	span.SetAttributes(attribute.Int("app.products.count", len(products)))
+	if err := templates.ExecuteTemplate(w, "admin_catalog", map[string]interface{}{
+		"trace_id":      span.SpanContext().TraceID().String(),
+		"span_id":       span.SpanContext().SpanID().String(),
+		"session_id":    sessionID(r),
+		"request_id":    r.Context().Value(ctxKeyRequestID{}),
+		"show_currency": false,
+		"products":      products,
+		"platform_css":  plat.css,
+		"platform_name": plat.provider,
+	}); err != nil {
+		log.Println(err)
+	}
+}
+
+// decodeAdminProduct reads the product in the request body, with the ID in
+// the path if there is one.
+func decodeAdminProduct(r *http.Request) (*pb.Product, error) {
+	var in apiProduct
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return nil, errors.Wrap(err, "invalid product")
+	}
+	if id, ok := mux.Vars(r)["id"]; ok {
+		if in.ID != "" && in.ID != id {
+			return nil, errors.Errorf("product id %q doesn't match the path", in.ID)
+		}
+		in.ID = id
+	}
+	return in.proto(), nil
+}
+
+// catalogChanged logs a change to the catalog and records it on the span.
+func catalogChanged(r *http.Request, action, id string) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	log.WithField("product_id", id).Infof("product %s", action)
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("app.product_id", id))
+}
+
+func (fe *frontendServer) createCatalogProductHandler(w http.ResponseWriter, r *http.Request) {
+	in, err := decodeAdminProduct(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	p, err := fe.createProduct(r.Context(), in)
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(errors.Cause(err)), err)
+		return
+	}
+	catalogChanged(r, "created", p.GetId())
+	writeJSON(w, http.StatusCreated, newAPIProduct(p, p.GetPriceUsd()))
+}
+
+func (fe *frontendServer) updateCatalogProductHandler(w http.ResponseWriter, r *http.Request) {
+	in, err := decodeAdminProduct(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	p, err := fe.updateProduct(r.Context(), in)
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(errors.Cause(err)), err)
+		return
+	}
+	catalogChanged(r, "updated", p.GetId())
+	writeJSON(w, http.StatusOK, newAPIProduct(p, p.GetPriceUsd()))
+}
+
+func (fe *frontendServer) deleteCatalogProductHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := fe.deleteProduct(r.Context(), id); err != nil {
+		writeJSONError(w, httpStatusFromGRPC(errors.Cause(err)), err)
+		return
+	}
+	catalogChanged(r, "deleted", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (fe *frontendServer) setCatalogPriceHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var price apiMoney
+	if err := json.NewDecoder(r.Body).Decode(&price); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errors.Wrap(err, "invalid price"))
+		return
+	}
+	p, err := fe.setProductPrice(r.Context(), id, price.proto())
+	if err != nil {
+		writeJSONError(w, httpStatusFromGRPC(errors.Cause(err)), err)
+		return
+	}
+	catalogChanged(r, "repriced", id)
+	writeJSON(w, http.StatusOK, newAPIProduct(p, p.GetPriceUsd()))
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// fakeBackends serves a fakeShop over gRPC, so the contract tests go through
+// the frontend's real clients.
+type fakeBackends struct {
+	pb.UnimplementedAdServiceServer
+	pb.UnimplementedAdminServiceServer
+	pb.UnimplementedCartServiceServer
+	pb.UnimplementedCheckoutServiceServer
+	pb.UnimplementedCurrencyServiceServer
+	pb.UnimplementedInventoryServiceServer
+	pb.UnimplementedProductCatalogServiceServer
+	pb.UnimplementedRecommendationServiceServer
+	pb.UnimplementedShippingServiceServer
+
+	shop     *fakeShop
+	settings *fakeAdminClient
+	cache    *fakeCheckoutCache
+}
+
+func (b *fakeBackends) GetAds(ctx context.Context, in *pb.AdRequest) (*pb.AdResponse, error) {
+	return b.shop.GetAds(ctx, in)
+}
+
+func (b *fakeBackends) GetSettings(ctx context.Context, in *pb.Empty) (*pb.AdminSettings, error) {
+	return b.settings.GetSettings(ctx, in)
+}
+
+func (b *fakeBackends) UpdateSettings(ctx context.Context, in *pb.AdminSettings) (*pb.AdminSettings, error) {
+	return b.settings.UpdateSettings(ctx, in)
+}
+
+func (b *fakeBackends) AddItem(ctx context.Context, in *pb.AddItemRequest) (*pb.Empty, error) {
+	return b.shop.AddItem(ctx, in)
+}
+
+func (b *fakeBackends) GetCart(ctx context.Context, in *pb.GetCartRequest) (*pb.Cart, error) {
+	return b.shop.GetCart(ctx, in)
+}
+
+func (b *fakeBackends) EmptyCart(ctx context.Context, in *pb.EmptyCartRequest) (*pb.Empty, error) {
+	return b.shop.EmptyCart(ctx, in)
+}
+
+func (b *fakeBackends) PlaceOrder(ctx context.Context, in *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	return b.shop.PlaceOrder(ctx, in)
+}
+
+func (b *fakeBackends) GetOrder(ctx context.Context, in *pb.GetOrderRequest) (*pb.OrderResult, error) {
+	return b.shop.GetOrder(ctx, in)
+}
+
+func (b *fakeBackends) ListOrdersByUser(ctx context.Context, in *pb.ListOrdersByUserRequest) (*pb.ListOrdersByUserResponse, error) {
+	return b.shop.ListOrdersByUser(ctx, in)
+}
+
+func (b *fakeBackends) GetCacheSize(ctx context.Context, in *pb.Empty) (*pb.CacheSizeResponse, error) {
+	return b.cache.GetCacheSize(ctx, in)
+}
+
+func (b *fakeBackends) ResetCache(ctx context.Context, in *pb.ResetCacheRequest) (*pb.CacheSizeResponse, error) {
+	return b.cache.ResetCache(ctx, in)
+}
+
+func (b *fakeBackends) GetSupportedCurrencies(ctx context.Context, in *pb.Empty) (*pb.GetSupportedCurrenciesResponse, error) {
+	return b.shop.GetSupportedCurrencies(ctx, in)
+}
+
+func (b *fakeBackends) Convert(ctx context.Context, in *pb.CurrencyConversionRequest) (*pb.Money, error) {
+	return b.shop.Convert(ctx, in)
+}
+
+func (b *fakeBackends) GetStock(ctx context.Context, in *pb.GetStockRequest) (*pb.GetStockResponse, error) {
+	return b.shop.GetStock(ctx, in)
+}
+
+func (b *fakeBackends) ListProducts(ctx context.Context, in *pb.Empty) (*pb.ListProductsResponse, error) {
+	return b.shop.ListProducts(ctx, in)
+}
+
+func (b *fakeBackends) GetProduct(ctx context.Context, in *pb.GetProductRequest) (*pb.Product, error) {
+	return b.shop.GetProduct(ctx, in)
+}
+
+func (b *fakeBackends) GetProducts(ctx context.Context, in *pb.GetProductsRequest) (*pb.GetProductsResponse, error) {
+	return b.shop.GetProducts(ctx, in)
+}
+
+func (b *fakeBackends) SearchProducts(ctx context.Context, in *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	return b.shop.SearchProducts(ctx, in)
+}
+
+func (b *fakeBackends) CreateProduct(ctx context.Context, in *pb.CreateProductRequest) (*pb.Product, error) {
+	return b.shop.CreateProduct(ctx, in)
+}
+
+func (b *fakeBackends) UpdateProduct(ctx context.Context, in *pb.UpdateProductRequest) (*pb.Product, error) {
+	return b.shop.UpdateProduct(ctx, in)
+}
+
+func (b *fakeBackends) DeleteProduct(ctx context.Context, in *pb.DeleteProductRequest) (*pb.Empty, error) {
+	return b.shop.DeleteProduct(ctx, in)
+}
+
+func (b *fakeBackends) SetPrice(ctx context.Context, in *pb.SetPriceRequest) (*pb.Product, error) {
+	return b.shop.SetPrice(ctx, in)
+}
+
+func (b *fakeBackends) ListRecommendations(ctx context.Context, in *pb.ListRecommendationsRequest) (*pb.ListRecommendationsResponse, error) {
+	return b.shop.ListRecommendations(ctx, in)
+}
+
+func (b *fakeBackends) TrackShipment(ctx context.Context, in *pb.TrackShipmentRequest) (*pb.TrackShipmentResponse, error) {
+	return b.shop.TrackShipment(ctx, in)
+}
+
+func (b *fakeBackends) GetQuote(ctx context.Context, in *pb.GetQuoteRequest) (*pb.GetQuoteResponse, error) {
+	return b.shop.GetQuote(ctx, in)
+}
+
+// newContractServer starts the frontend against fakeBackends, connecting to
+// them with opts.
+func newContractServer(t *testing.T, opts ...grpc.DialOption) *frontendServer {
+	t.Helper()
+	backends := &fakeBackends{
+		shop:     newFakeShop(),
+		settings: &fakeAdminClient{token: "secret", settings: map[string]string{"cache_user_threshold": "35000"}},
+		cache:    &fakeCheckoutCache{size: 1000},
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterAdServiceServer(srv, backends)
+	pb.RegisterAdminServiceServer(srv, backends)
+	pb.RegisterCartServiceServer(srv, backends)
+	pb.RegisterCheckoutServiceServer(srv, backends)
+	pb.RegisterCurrencyServiceServer(srv, backends)
+	pb.RegisterInventoryServiceServer(srv, backends)
+	pb.RegisterProductCatalogServiceServer(srv, backends)
+	pb.RegisterRecommendationServiceServer(srv, backends)
+	pb.RegisterShippingServiceServer(srv, backends)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(lis.Addr().String(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	r, err := newFaultRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the product page fails at random otherwise
+	if err := r.Modify("product-timeout", func(s *faults.Scenario) { s.Enabled = false }); err != nil {
+		t.Fatal(err)
+	}
+	CacheTrack = NewCacheTracker(35000, 30000, nil, logrus.New())
+	PercentNormal.Store(75)
+	return &frontendServer{
+		adSvcClient:             pb.NewAdServiceClient(conn),
+		cartSvcClient:           pb.NewCartServiceClient(conn),
+		checkoutSvcClient:       pb.NewCheckoutServiceClient(conn),
+		getCacheClient:          pb.NewCheckoutServiceClient(conn),
+		currencySvcClient:       pb.NewCurrencyServiceClient(conn),
+		inventorySvcClient:      pb.NewInventoryServiceClient(conn),
+		productCatalogSvcClient: pb.NewProductCatalogServiceClient(conn),
+		recommendationSvcClient: pb.NewRecommendationServiceClient(conn),
+		shippingSvcClient:       pb.NewShippingServiceClient(conn),
+		faults:                  r,
+		adminToken:              "secret",
+		admin:                   newAdminSettings(r),
+		adminSvcClient: map[string]pb.AdminServiceClient{
+			"checkout":       pb.NewAdminServiceClient(conn),
+			"productcatalog": pb.NewAdminServiceClient(conn),
+			"shipping":       pb.NewAdminServiceClient(conn),
+		},
+	}
+}
+
+func loadOpenAPISpec(t *testing.T) (*openapi3.T, routers.Router) {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("openapi.json is invalid: %v", err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc, router
+}
+
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.FileBodyDecoder)
+}
+
+type contractCase struct {
+	method, path string
+	contentType  string
+	body         string
+	header       map[string]string
+	// invalid requests break the spec on purpose, to check the error response
+	invalid bool
+	status  int
+}
+
+func (c contractCase) String() string {
+	return c.method + " " + c.path
+}
+
+// TestContract sends a request to every operation in openapi.json and
+// validates the requests and responses against it.
+func TestContract(t *testing.T) {
+	doc, router := loadOpenAPISpec(t)
+	ts := httptest.NewServer(newContractServer(t).handler(&logrus.Logger{Out: io.Discard, Formatter: new(logrus.TextFormatter), Level: logrus.PanicLevel}))
+	defer ts.Close()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Jar: jar,
+		// check redirects instead of following them
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	const (
+		form     = "application/x-www-form-urlencoded"
+		jsonType = "application/json"
+	)
+	admin := map[string]string{"Authorization": "Bearer secret"}
+	checkoutForm := "email=someone%40example.com&street_address=1600+Amphitheatre+Parkway&zip_code=94043&city=Mountain+View" +
+		"&state=CA&country=United+States&credit_card_number=4432801561520454&credit_card_expiration_month=1" +
+		"&credit_card_expiration_year=2030&credit_card_cvv=672&idempotency_key=abc&promo_code="
+
+	cases := []contractCase{
+		{method: "GET", path: "/", status: 200},
+		{method: "HEAD", path: "/", status: 200},
+		{method: "GET", path: "/product/OLJCESPC7Z", status: 200},
+		{method: "GET", path: "/product/nope", status: 500},
+		{method: "HEAD", path: "/product/OLJCESPC7Z", status: 200},
+		{method: "GET", path: "/search?q=sun&category=accessories&min_price=10&max_price=24.99&sort=price_asc", status: 200},
+		{method: "GET", path: "/search?page_token=bogus", status: 400},
+		{method: "GET", path: "/search?sort=random", status: 400, invalid: true},
+		{method: "HEAD", path: "/search?q=top", status: 200},
+		{method: "POST", path: "/cart", contentType: form, body: "product_id=OLJCESPC7Z&quantity=2", status: 302},
+		{method: "POST", path: "/cart", contentType: form, body: "product_id=OLJCESPC7Z&quantity=0", invalid: true, status: 400},
+		{method: "POST", path: "/cart", contentType: form, body: "product_id=66VCHSJNUP&quantity=4", status: 409},
+		{method: "GET", path: "/cart", status: 200},
+		{method: "GET", path: "/cart?shipping_option=express", status: 200},
+		{method: "HEAD", path: "/cart", status: 200},
+		{method: "POST", path: "/setCurrency", contentType: form, body: "currency_code=EUR", status: 302},
+		{method: "POST", path: "/cart/checkout", contentType: form, body: checkoutForm + "BOGUS", status: 400},
+		{method: "POST", path: "/cart/checkout", contentType: form, body: checkoutForm, status: 200},
+		{method: "POST", path: "/cart/checkout", contentType: form, body: "shipping_option=express&" + checkoutForm, status: 200},
+		{method: "GET", path: "/orders", status: 200},
+		{method: "HEAD", path: "/orders", status: 200},
+		{method: "GET", path: "/orders/order-1", status: 200},
+		{method: "GET", path: "/orders/nope", status: 404},
+		{method: "HEAD", path: "/orders/order-1", status: 200},
+		{method: "GET", path: "/track/TRACK-1", status: 200},
+		{method: "GET", path: "/track/nope", status: 404},
+		{method: "HEAD", path: "/track/TRACK-1", status: 200},
+		{method: "POST", path: "/cart/empty", status: 302},
+
+		{method: "GET", path: "/api/v1/products?currency=EUR", status: 200},
+		{method: "GET", path: "/api/v1/products?currency=XXX", status: 400},
+		{method: "GET", path: "/api/v1/products/66VCHSJNUP", status: 200},
+		{method: "GET", path: "/api/v1/products/nope", status: 404},
+		{method: "POST", path: "/api/v1/cart/items", contentType: jsonType, body: `{"product_id": "66VCHSJNUP", "quantity": 1}`, status: 200},
+		{method: "POST", path: "/api/v1/cart/items", contentType: jsonType, body: `{"product_id": "66VCHSJNUP", "quantity": 3}`, status: 409},
+		{method: "POST", path: "/api/v1/cart/items", contentType: jsonType, body: `{"product_id": "66VCHSJNUP"}`, invalid: true, status: 400},
+		{method: "PUT", path: "/api/v1/cart/items/66VCHSJNUP", contentType: jsonType, body: `{"quantity": 2}`, status: 200},
+		{method: "PUT", path: "/api/v1/cart/items/66VCHSJNUP", contentType: jsonType, body: `{"quantity": 4}`, status: 409},
+		{method: "PUT", path: "/api/v1/cart/items/66VCHSJNUP", contentType: jsonType, body: `{"quantity": 0}`, invalid: true, status: 400},
+		{method: "PUT", path: "/api/v1/cart/items/OLJCESPC7Z", contentType: jsonType, body: `{"quantity": 1}`, status: 404},
+		{method: "DELETE", path: "/api/v1/cart/items/66VCHSJNUP", status: 200},
+		{method: "DELETE", path: "/api/v1/cart/items/66VCHSJNUP", status: 404},
+		{method: "POST", path: "/api/v1/cart/items", contentType: jsonType, body: `{"product_id": "66VCHSJNUP", "quantity": 1}`, status: 200},
+		{method: "GET", path: "/api/v1/cart", status: 200},
+		{method: "GET", path: "/api/v1/cart?shipping_option=express", status: 200},
+		{method: "GET", path: "/api/v1/currencies", status: 200},
+		{method: "GET", path: "/api/v1/recommendations?product_id=66VCHSJNUP", status: 200},
+		{method: "POST", path: "/api/v1/checkout", contentType: jsonType, header: map[string]string{"Idempotency-Key": "def"}, status: 201,
+			body: `{"email": "someone@example.com", "address": {"street_address": "1600 Amphitheatre Parkway", "city": "Mountain View", "state": "CA", "country": "United States", "zip_code": 94043},
+				"credit_card": {"number": "4432801561520454", "expiration_month": 1, "expiration_year": 2030, "cvv": 672}}`},
+		{method: "POST", path: "/api/v1/checkout", contentType: jsonType, body: `{"email": "someone@example.com", "credit_card": {"number": "declined"}}`, status: 400},
+		{method: "DELETE", path: "/api/v1/cart", status: 204},
+
+		{method: "GET", path: "/admin/settings", header: admin, status: 200},
+		{method: "GET", path: "/admin/settings", invalid: true, status: 401},
+		{method: "PUT", path: "/admin/settings", contentType: jsonType, header: admin, body: `{"percent_normal": "50"}`, status: 200},
+		{method: "PUT", path: "/admin/settings", contentType: jsonType, header: admin, body: `{"percent_normal": "500"}`, status: 400},
+		{method: "GET", path: "/admin/settings/checkout", header: admin, status: 200},
+		{method: "PUT", path: "/admin/settings/shipping", contentType: jsonType, header: admin, body: `{"tracking_lambda_url": "https://example.com"}`, status: 200},
+		{method: "POST", path: "/admin/cache/reset", contentType: jsonType, header: admin, body: `{"target_size": 10}`, status: 200},
+		{method: "GET", path: "/admin/catalog", status: 200},
+		{method: "HEAD", path: "/admin/catalog", status: 200},
+		{method: "POST", path: "/admin/catalog/products", contentType: jsonType, header: admin, status: 201,
+			body: `{"name": "Mug", "categories": ["kitchen"], "price": {"currency_code": "USD", "units": 8, "nanos": 0}}`},
+		{method: "POST", path: "/admin/catalog/products", contentType: jsonType, header: admin, status: 409,
+			body: `{"id": "OLJCESPC7Z", "name": "Mug", "categories": ["kitchen"], "price": {"currency_code": "USD", "units": 8, "nanos": 0}}`},
+		{method: "PUT", path: "/admin/catalog/products/NEWPRODUCT", contentType: jsonType, header: admin, status: 200,
+			body: `{"name": "Mug", "description": "Holds tea.", "categories": ["kitchen"], "price": {"currency_code": "USD", "units": 8, "nanos": 0}}`},
+		{method: "PUT", path: "/admin/catalog/products/NEWPRODUCT/price", contentType: jsonType, header: admin, status: 200,
+			body: `{"currency_code": "USD", "units": 9, "nanos": 500000000}`},
+		{method: "PUT", path: "/admin/catalog/products/NEWPRODUCT/price", contentType: jsonType, header: admin, status: 400,
+			body: `{"currency_code": "EUR", "units": 9, "nanos": 0}`},
+		{method: "DELETE", path: "/admin/catalog/products/NEWPRODUCT", header: admin, status: 204},
+		{method: "DELETE", path: "/admin/catalog/products/NEWPRODUCT", header: admin, status: 404},
+
+		{method: "GET", path: "/static/favicon.ico", status: 200},
+		{method: "GET", path: "/dist/nope.js", status: 404},
+		{method: "GET", path: "/robots.txt", status: 200},
+		{method: "GET", path: "/_healthz", status: 200},
+		{method: "GET", path: "/openapi.json", status: 200},
+		{method: "GET", path: "/logout", status: 302},
+	}
+
+	covered := map[string]bool{}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, ts.URL+c.path, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.contentType != "" {
+			req.Header.Set("Content-Type", c.contentType)
+		}
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		for _, cookie := range jar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			t.Errorf("%s: not in openapi.json: %v", c, err)
+			continue
+		}
+		covered[c.method+" "+route.Path] = true
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc:    authenticateAdmin,
+				IncludeResponseStatus: true,
+			},
+		}
+		err = openapi3filter.ValidateRequest(context.Background(), input)
+		if c.invalid && err == nil {
+			t.Errorf("%s: request should break the spec", c)
+		} else if !c.invalid && err != nil {
+			t.Errorf("%s: invalid request: %v", c, err)
+		}
+		// ValidateRequest consumed the body
+		req.Body = io.NopCloser(strings.NewReader(c.body))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("%s: got status %d, want %d: %s", c, resp.StatusCode, c.status, body)
+			continue
+		}
+		if strings.HasPrefix(c.path, "/static/") {
+			// images have no body decoder, the status and headers are enough
+			input.Options.ExcludeResponseBody = true
+		}
+		err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 resp.StatusCode,
+			Header:                 resp.Header,
+			Body:                   io.NopCloser(bytes.NewReader(body)),
+			Options:                input.Options,
+		})
+		if err != nil {
+			t.Errorf("%s: invalid response: %v", c, err)
+		}
+	}
+
+	var missing []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !covered[method+" "+path] {
+				missing = append(missing, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("no contract test for %v", missing)
+	}
+}
+
+// authenticateAdmin checks the bearer token is present, leaving whether it is
+// right to the handler.
+func authenticateAdmin(_ context.Context, in *openapi3filter.AuthenticationInput) error {
+	if !strings.HasPrefix(in.RequestValidationInput.Request.Header.Get("Authorization"), "Bearer ") {
+		return in.NewError(nil)
+	}
+	return nil
+}
+
+// TestOpenAPICoversRoutes checks that every route registered on the router is
+// described in openapi.json.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	doc, _ := loadOpenAPISpec(t)
+	fe := &frontendServer{}
+	err := fe.router().Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// subrouters and file servers match every method
+			if strings.HasSuffix(path, "/") && path != "/" {
+				methods = []string{http.MethodGet}
+				path += "{file}"
+			} else if strings.HasPrefix(path, "/api/v1") && !strings.Contains(path[len("/api/v1"):], "/") {
+				return nil
+			} else {
+				methods = []string{http.MethodGet}
+			}
+		}
+		item := doc.Paths.Find(path)
+		if item == nil {
+			t.Errorf("%s is not in openapi.json", path)
+			return nil
+		}
+		for _, m := range methods {
+			if item.GetOperation(m) == nil {
+				t.Errorf("%s %s is not in openapi.json", m, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+)
+
+// defaultFaultScenarios are the scenarios the frontend runs when
+// FAULTS_CONFIG is not set.
+func defaultFaultScenarios() []faults.Scenario {
+	return []faults.Scenario{
+		{
+			Name:        "product-timeout",
+			Kind:        faults.KindError,
+			Point:       "frontend.product",
+			Enabled:     true,
+			Probability: 0.01,
+			Message:     "Product service failed to respond. Connection timeout after 3 retries.",
+		},
+	}
+}
+
+// newFaultRegistry loads the scenarios in path, or the defaults if path is empty.
+func newFaultRegistry(path string) (*faults.Registry, error) {
+	scenarios := defaultFaultScenarios()
+	if path != "" {
+		var err error
+		if scenarios, err = faults.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	return faults.NewRegistry(scenarios)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/honeycombio/microservices-demo/src/frontend/flags"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// flagAvoidNoopConversion skips the currency service when converting to
+	// the currency a price is already in.
+	flagAvoidNoopConversion = "avoid-noop-currency-conversion"
+	// flagProductPageErrors lets the fault scenarios at frontend.product fire.
+	flagProductPageErrors = "product-page-errors"
+	// flagProblemUserOverride routes load generator traffic to user 20109 once
+	// the checkout cache is over the user threshold.
+	flagProblemUserOverride = "problem-user-override"
+
+	flagPollInterval = 5 * time.Second
+)
+
+// featureFlags evaluates flags for the frontend. Until initFlags sets a
+// provider, every evaluation returns the default passed by the caller.
+var featureFlags = openfeature.NewClient("frontend")
+
+// defaultFlags are served when FLAGS_CONFIG is not set, or does not define them.
+func defaultFlags() map[string]flags.Flag {
+	onOff := func(on bool) flags.Flag {
+		variant := "off"
+		if on {
+			variant = "on"
+		}
+		return flags.Flag{
+			State:          "ENABLED",
+			Variants:       map[string]interface{}{"on": true, "off": false},
+			DefaultVariant: variant,
+		}
+	}
+	return map[string]flags.Flag{
+		flagAvoidNoopConversion: onOff(false),
+		flagProductPageErrors:   onOff(true),
+		flagProblemUserOverride: onOff(true),
+	}
+}
+
+// initFlags serves the flags in path, reloading it when it changes.
+func initFlags(path string, log logrus.FieldLogger) error {
+	p, err := flags.NewFileProvider(path, defaultFlags(), flagPollInterval)
+	if err != nil {
+		return err
+	}
+	openfeature.AddHooks(flags.TracingHook{})
+	logEvent := func(d openfeature.EventDetails) {
+		log.WithField("flags", d.FlagChanges).Info(d.Message)
+	}
+	openfeature.AddHandler(openfeature.ProviderConfigChange, &logEvent)
+	logError := func(d openfeature.EventDetails) {
+		log.WithField("path", path).Warnf("failed to reload feature flags: %s", d.Message)
+	}
+	openfeature.AddHandler(openfeature.ProviderError, &logError)
+	return openfeature.SetProviderAndWait(p)
+}
+
+// flagEnabled evaluates a boolean flag for the user, falling back to def.
+func flagEnabled(ctx context.Context, key, userID string, def bool) bool {
+	evalCtx := openfeature.NewTargetlessEvaluationContext(nil)
+	if userID != "" {
+		evalCtx = openfeature.NewEvaluationContext(userID, nil)
+	}
+	return featureFlags.Boolean(ctx, key, def, evalCtx)
+}
@@ -0,0 +1,323 @@
+// Package flags is an OpenFeature provider that serves feature flags from a
+// JSON file and reloads them when the file changes, so demo behavior can be
+// flipped without a redeploy.
+//
+// A flag file looks like:
+//
+//	{
+//	  "flags": {
+//	    "product-page-errors": {
+//	      "state": "ENABLED",
+//	      "variants": {"on": true, "off": false},
+//	      "defaultVariant": "on",
+//	      "targeting": [{"users": ["20109"], "variant": "off"}]
+//	    }
+//	  }
+//	}
+//
+// Targeting rules match the targeting key of the evaluation context, which is
+// the user id in this demo.
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ProviderName is reported as the provider's metadata name.
+const ProviderName = "file"
+
+// Flag is the definition of one flag.
+type Flag struct {
+	// State is ENABLED or DISABLED. Disabled flags evaluate to the caller's default.
+	State          string                 `json:"state"`
+	Variants       map[string]interface{} `json:"variants"`
+	DefaultVariant string                 `json:"defaultVariant"`
+	Targeting      []Rule                 `json:"targeting,omitempty"`
+}
+
+// Rule serves Variant to the listed users.
+type Rule struct {
+	Users   []string `json:"users"`
+	Variant string   `json:"variant"`
+}
+
+// Config is the format of a flag file.
+type Config struct {
+	Flags map[string]Flag `json:"flags"`
+}
+
+func (c Config) validate() error {
+	for key, f := range c.Flags {
+		if f.State != "ENABLED" && f.State != "DISABLED" {
+			return fmt.Errorf("flag %q: state must be ENABLED or DISABLED", key)
+		}
+		if _, ok := f.Variants[f.DefaultVariant]; !ok {
+			return fmt.Errorf("flag %q: default variant %q is not defined", key, f.DefaultVariant)
+		}
+		for _, r := range f.Targeting {
+			if _, ok := f.Variants[r.Variant]; !ok {
+				return fmt.Errorf("flag %q: targeted variant %q is not defined", key, r.Variant)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads and validates a flag file.
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, c.validate()
+}
+
+// FileProvider is an openfeature.FeatureProvider backed by a flag file.
+type FileProvider struct {
+	path     string
+	interval time.Duration
+
+	lock    sync.RWMutex
+	flags   map[string]Flag
+	modTime time.Time
+
+	events chan openfeature.Event
+	stop   chan struct{}
+	done   sync.WaitGroup
+}
+
+// NewFileProvider serves the flags in path, checking it for changes every
+// interval. Flags in defaults are served when path is empty, or when the file
+// does not define them.
+func NewFileProvider(path string, defaults map[string]Flag, interval time.Duration) (*FileProvider, error) {
+	p := &FileProvider{
+		path:     path,
+		interval: interval,
+		flags:    defaults,
+		events:   make(chan openfeature.Event, 8),
+	}
+	if path != "" {
+		if _, err := p.reload(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *FileProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: ProviderName}
+}
+
+func (p *FileProvider) Hooks() []openfeature.Hook {
+	return nil
+}
+
+// Init starts watching the flag file. It is called by the OpenFeature SDK.
+func (p *FileProvider) Init(openfeature.EvaluationContext) error {
+	if p.path == "" || p.interval <= 0 {
+		return nil
+	}
+	p.stop = make(chan struct{})
+	p.done.Add(1)
+	go p.watch()
+	return nil
+}
+
+// Shutdown stops watching the flag file.
+func (p *FileProvider) Shutdown() {
+	if p.stop != nil {
+		close(p.stop)
+		p.done.Wait()
+		p.stop = nil
+	}
+}
+
+func (p *FileProvider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+func (p *FileProvider) watch() {
+	defer p.done.Done()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+		changed, err := p.reload()
+		switch {
+		case err != nil:
+			p.emit(openfeature.Event{
+				ProviderName:         ProviderName,
+				EventType:            openfeature.ProviderError,
+				ProviderEventDetails: openfeature.ProviderEventDetails{Message: err.Error()},
+			})
+		case len(changed) > 0:
+			p.emit(openfeature.Event{
+				ProviderName: ProviderName,
+				EventType:    openfeature.ProviderConfigChange,
+				ProviderEventDetails: openfeature.ProviderEventDetails{
+					Message:     "flag file reloaded",
+					FlagChanges: changed,
+				},
+			})
+		}
+	}
+}
+
+// emit drops the event rather than blocking when nobody is listening.
+func (p *FileProvider) emit(e openfeature.Event) {
+	select {
+	case p.events <- e:
+	default:
+	}
+}
+
+// reload reads the flag file if it changed since the last read, and returns
+// the keys of the flags that changed. The current flags are kept if the file
+// is invalid.
+func (p *FileProvider) reload() ([]string, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+	p.lock.RLock()
+	unchanged := info.ModTime().Equal(p.modTime)
+	p.lock.RUnlock()
+	if unchanged {
+		return nil, nil
+	}
+
+	c, err := LoadConfig(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	flags := make(map[string]Flag, len(p.flags)+len(c.Flags))
+	for key, f := range p.flags {
+		flags[key] = f
+	}
+	var changed []string
+	for key, f := range c.Flags {
+		if old, ok := flags[key]; !ok || !reflect.DeepEqual(old, f) {
+			changed = append(changed, key)
+		}
+		flags[key] = f
+	}
+	sort.Strings(changed)
+	p.flags = flags
+	p.modTime = info.ModTime()
+	return changed, nil
+}
+
+// resolve returns the variant of key for the evaluation context.
+func (p *FileProvider) resolve(key string, evalCtx openfeature.FlattenedContext) (interface{}, openfeature.ProviderResolutionDetail) {
+	p.lock.RLock()
+	f, ok := p.flags[key]
+	p.lock.RUnlock()
+	if !ok {
+		return nil, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %q is not defined", key)),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+	if f.State == "DISABLED" {
+		return nil, openfeature.ProviderResolutionDetail{Reason: openfeature.DisabledReason}
+	}
+
+	user, _ := evalCtx[openfeature.TargetingKey].(string)
+	for _, r := range f.Targeting {
+		for _, u := range r.Users {
+			if u == user {
+				return f.Variants[r.Variant], openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: r.Variant,
+				}
+			}
+		}
+	}
+	return f.Variants[f.DefaultVariant], openfeature.ProviderResolutionDetail{
+		Reason:  openfeature.StaticReason,
+		Variant: f.DefaultVariant,
+	}
+}
+
+func typeMismatch(key string, detail openfeature.ProviderResolutionDetail) openfeature.ProviderResolutionDetail {
+	detail.ResolutionError = openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant %q of flag %q has the wrong type", detail.Variant, key))
+	detail.Reason = openfeature.ErrorReason
+	return detail
+}
+
+func (p *FileProvider) BooleanEvaluation(_ context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	v, detail := p.resolve(flag, evalCtx)
+	if v == nil {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, detail)}
+	}
+	return openfeature.BoolResolutionDetail{Value: b, ProviderResolutionDetail: detail}
+}
+
+func (p *FileProvider) StringEvaluation(_ context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	v, detail := p.resolve(flag, evalCtx)
+	if v == nil {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	s, ok := v.(string)
+	if !ok {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, detail)}
+	}
+	return openfeature.StringResolutionDetail{Value: s, ProviderResolutionDetail: detail}
+}
+
+func (p *FileProvider) FloatEvaluation(_ context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	v, detail := p.resolve(flag, evalCtx)
+	if v == nil {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, detail)}
+	}
+	return openfeature.FloatResolutionDetail{Value: f, ProviderResolutionDetail: detail}
+}
+
+func (p *FileProvider) IntEvaluation(_ context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	v, detail := p.resolve(flag, evalCtx)
+	if v == nil {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	// JSON numbers decode as float64
+	f, ok := v.(float64)
+	if !ok || f != float64(int64(f)) {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, detail)}
+	}
+	return openfeature.IntResolutionDetail{Value: int64(f), ProviderResolutionDetail: detail}
+}
+
+func (p *FileProvider) ObjectEvaluation(_ context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	v, detail := p.resolve(flag, evalCtx)
+	if v == nil {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	return openfeature.InterfaceResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+}
@@ -0,0 +1,174 @@
+package flags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const testConfig = `{
+  "flags": {
+    "product-page-errors": {
+      "state": "ENABLED",
+      "variants": {"on": true, "off": false},
+      "defaultVariant": "on",
+      "targeting": [{"users": ["20109"], "variant": "off"}]
+    },
+    "greeting": {
+      "state": "DISABLED",
+      "variants": {"formal": "Good day", "casual": "Hi"},
+      "defaultVariant": "casual"
+    },
+    "retries": {
+      "state": "ENABLED",
+      "variants": {"few": 2, "many": 10},
+      "defaultVariant": "many"
+    }
+  }
+}`
+
+func writeConfig(t *testing.T, path, config string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// set the time explicitly, as writes in quick succession can share an mtime
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestProvider(t *testing.T, defaults map[string]Flag) (*FileProvider, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "flags.json")
+	writeConfig(t, path, testConfig, time.Unix(1700000000, 0))
+	p, err := NewFileProvider(path, defaults, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, path
+}
+
+func user(id string) openfeature.FlattenedContext {
+	return openfeature.FlattenedContext{openfeature.TargetingKey: id}
+}
+
+func TestResolve(t *testing.T) {
+	p, _ := newTestProvider(t, map[string]Flag{
+		"from-defaults": {State: "ENABLED", Variants: map[string]interface{}{"on": true}, DefaultVariant: "on"},
+	})
+	ctx := context.Background()
+
+	got := p.BooleanEvaluation(ctx, "product-page-errors", false, user("42"))
+	if !got.Value || got.Variant != "on" || got.Reason != openfeature.StaticReason {
+		t.Errorf("got %+v for an untargeted user", got)
+	}
+	got = p.BooleanEvaluation(ctx, "product-page-errors", true, user("20109"))
+	if got.Value || got.Variant != "off" || got.Reason != openfeature.TargetingMatchReason {
+		t.Errorf("got %+v for a targeted user", got)
+	}
+	if got := p.BooleanEvaluation(ctx, "from-defaults", false, nil); !got.Value {
+		t.Errorf("got %+v, want the default flag", got)
+	}
+
+	s := p.StringEvaluation(ctx, "greeting", "Hello", nil)
+	if s.Value != "Hello" || s.Reason != openfeature.DisabledReason {
+		t.Errorf("got %+v for a disabled flag", s)
+	}
+	if i := p.IntEvaluation(ctx, "retries", 0, nil); i.Value != 10 {
+		t.Errorf("got %+v, want 10", i)
+	}
+
+	missing := p.BooleanEvaluation(ctx, "nope", true, nil)
+	if !missing.Value || missing.ResolutionError.Error() == "" {
+		t.Errorf("got %+v for a missing flag", missing)
+	}
+	mismatch := p.StringEvaluation(ctx, "retries", "x", nil)
+	if mismatch.Value != "x" || mismatch.Reason != openfeature.ErrorReason {
+		t.Errorf("got %+v for a type mismatch", mismatch)
+	}
+}
+
+func TestLoadConfigValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	for _, bad := range []string{
+		`{"flags": {"f": {"state": "MAYBE", "variants": {"on": true}, "defaultVariant": "on"}}}`,
+		`{"flags": {"f": {"state": "ENABLED", "variants": {"on": true}, "defaultVariant": "off"}}}`,
+		`{"flags": {"f": {"state": "ENABLED", "variants": {"on": true}, "defaultVariant": "on", "targeting": [{"users": ["1"], "variant": "off"}]}}}`,
+		`{"flags": `,
+	} {
+		writeConfig(t, path, bad, time.Now())
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("loaded invalid config %s", bad)
+		}
+	}
+}
+
+func TestHotReload(t *testing.T) {
+	p, path := newTestProvider(t, nil)
+	p.interval = 5 * time.Millisecond
+	if err := p.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown()
+
+	writeConfig(t, path, `{"flags": {"product-page-errors": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "off"}}}`,
+		time.Unix(1700000100, 0))
+	select {
+	case e := <-p.EventChannel():
+		if e.EventType != openfeature.ProviderConfigChange || len(e.FlagChanges) != 1 || e.FlagChanges[0] != "product-page-errors" {
+			t.Errorf("got event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("flag file was not reloaded")
+	}
+	if got := p.BooleanEvaluation(context.Background(), "product-page-errors", true, nil); got.Value {
+		t.Errorf("got %+v after the reload, want off", got)
+	}
+
+	writeConfig(t, path, `{"flags": `, time.Unix(1700000200, 0))
+	select {
+	case e := <-p.EventChannel():
+		if e.EventType != openfeature.ProviderError {
+			t.Errorf("got event %+v, want an error", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("invalid flag file was not reported")
+	}
+	if got := p.BooleanEvaluation(context.Background(), "greeting", true, nil); got.Reason != openfeature.DisabledReason {
+		t.Errorf("got %+v, want the flags from before the invalid file", got)
+	}
+}
+
+func TestTracingHook(t *testing.T) {
+	p, _ := newTestProvider(t, nil)
+	if err := openfeature.SetNamedProviderAndWait("tracing-test", p); err != nil {
+		t.Fatal(err)
+	}
+	client := openfeature.NewClient("tracing-test")
+	client.AddHooks(TracingHook{})
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	client.Boolean(ctx, "product-page-errors", false, openfeature.NewEvaluationContext("20109", nil))
+	span.End()
+
+	attrs := map[string]string{}
+	for _, kv := range sr.Ended()[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["feature_flag.key"] != "product-page-errors" || attrs["feature_flag.variant"] != "off" ||
+		attrs["feature_flag.provider_name"] != ProviderName {
+		t.Errorf("got attributes %v", attrs)
+	}
+	if events := sr.Ended()[0].Events(); len(events) != 1 || events[0].Name != "feature_flag" {
+		t.Errorf("got events %v, want one feature_flag event", events)
+	}
+}
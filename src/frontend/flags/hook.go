@@ -0,0 +1,37 @@
+package flags
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingHook records every flag evaluation on the active span, following
+// the OpenTelemetry semantic conventions for feature flags.
+type TracingHook struct {
+	openfeature.UnimplementedHook
+}
+
+func (TracingHook) After(ctx context.Context, hookContext openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, _ openfeature.HookHints) error {
+	attrs := []attribute.KeyValue{
+		attribute.String("feature_flag.key", hookContext.FlagKey()),
+		attribute.String("feature_flag.provider_name", hookContext.ProviderMetadata().Name),
+		attribute.String("feature_flag.variant", details.Variant),
+	}
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attrs...)
+	span.AddEvent("feature_flag", trace.WithAttributes(
+		append(attrs, attribute.String("feature_flag.reason", string(details.Reason)))...,
+	))
+	return nil
+}
+
+func (TracingHook) Error(ctx context.Context, hookContext openfeature.HookContext, err error, _ openfeature.HookHints) {
+	trace.SpanFromContext(ctx).AddEvent("feature_flag", trace.WithAttributes(
+		attribute.String("feature_flag.key", hookContext.FlagKey()),
+		attribute.String("feature_flag.provider_name", hookContext.ProviderMetadata().Name),
+		attribute.String("error.message", err.Error()),
+	))
+}
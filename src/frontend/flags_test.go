@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFlagsOverrideDefaults(t *testing.T) {
+	if flagEnabled(context.Background(), flagAvoidNoopConversion, "", false) {
+		t.Error("flag is on before a provider is set")
+	}
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	config := `{"flags": {"avoid-noop-currency-conversion": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "on"},
+		"product-page-errors": {"state": "ENABLED", "variants": {"on": true, "off": false}, "defaultVariant": "on", "targeting": [{"users": ["20109"], "variant": "off"}]}}}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := initFlags(path, logrus.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if !flagEnabled(ctx, flagAvoidNoopConversion, "", false) {
+		t.Error("flag file did not turn on avoid-noop-currency-conversion")
+	}
+	if flagEnabled(ctx, flagProductPageErrors, "20109", true) || !flagEnabled(ctx, flagProductPageErrors, "42", false) {
+		t.Error("product-page-errors did not target user 20109")
+	}
+	if !flagEnabled(ctx, flagProblemUserOverride, "", false) {
+		t.Error("problem-user-override lost its default")
+	}
+}
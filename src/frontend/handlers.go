@@ -14,9 +14,11 @@ import (
 
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
 	"github.com/honeycombio/microservices-demo/src/frontend/money"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
@@ -24,6 +26,9 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type OtelHook struct{}
@@ -99,15 +104,21 @@ func (fe *frontendServer) homeHandler(w http.ResponseWriter, r *http.Request) {
 	type productView struct {
 		Item  *pb.Product
 		Price *pb.Money
+		Stock stockLevel
 	}
+	prices, err := fe.localizedPrices(r.Context(), products, currentCurrency(r))
+	if err != nil {
+		renderHTTPError(log, r, w, err, http.StatusInternalServerError)
+		return
+	}
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.GetId()
+	}
+	stock := fe.stockLevels(r.Context(), log, ids)
 	ps := make([]productView, len(products))
 	for i, p := range products {
-		price, err := fe.convertCurrency(r.Context(), p.GetPriceUsd(), currentCurrency(r))
-		if err != nil {
-			renderHTTPError(log, r, w, errors.Wrapf(err, "failed to do currency conversion for product %s", p.GetId()), http.StatusInternalServerError)
-			return
-		}
-		ps[i] = productView{p, price}
+		ps[i] = productView{p, prices[i], stock[p.GetId()]}
 	}
 
 	//get env and render correct platform banner.
@@ -158,14 +169,19 @@ func (fe *frontendServer) productHandler(w http.ResponseWriter, r *http.Request)
 	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
 	id := mux.Vars(r)["id"]
 
-	//randomly panic for demo scenario
-	if rand.Intn(100) == 0 {
-		err := fmt.Errorf("Product service failed to respond. Connection timeout after 3 retries.")
+	//randomly fail for demo scenario, see defaultFaultScenarios
+	var err error
+	if flagEnabled(ctx, flagProductPageErrors, sessionID(r), true) {
+		err = fe.faults.Inject(ctx, "frontend.product", faults.Target{
+			UserID:    sessionID(r),
+			RequestID: fmt.Sprint(ctx.Value(ctxKeyRequestID{})),
+		})
+	}
+	if err != nil {
 		span.SetStatus(codes.Error, "panic incoming")
 		span.RecordError(err)
 		renderHTTPError(log, r, w, errors.New("Product service failed to respond"), http.StatusInternalServerError)
 		return
-
 	}
 	if id == "" {
 		renderHTTPError(log, r, w, errors.New("Product id not specified"), http.StatusBadRequest)
@@ -174,11 +190,24 @@ func (fe *frontendServer) productHandler(w http.ResponseWriter, r *http.Request)
 	log.WithField("id", id).WithField("currency", currentCurrency(r)).
 		Debug("serving product page")
 
-	p, err := fe.getProduct(r.Context(), id)
+	// look up the product and the recommended ones in a single call
+	recommendationIDs, err := fe.getRecommendationIDs(r.Context(), sessionID(r), []string{id})
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "failed to get product recommendations"), http.StatusInternalServerError)
+		return
+	}
+	products, _, err := fe.getProductsByID(r.Context(), append([]string{id}, recommendationIDs...))
 	if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve product"), http.StatusInternalServerError)
 		return
 	}
+	p, ok := products[id]
+	if !ok {
+		renderHTTPError(log, r, w, errors.Errorf("could not retrieve product: no product with ID %s", id), http.StatusInternalServerError)
+		return
+	}
+	recommendations := pickRecommendations(recommendationIDs, products)
+
 	currencies, err := fe.getCurrencies(r.Context())
 	if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve currencies"), http.StatusInternalServerError)
@@ -197,16 +226,11 @@ func (fe *frontendServer) productHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	recommendations, err := fe.getRecommendations(r.Context(), sessionID(r), []string{id})
-	if err != nil {
-		renderHTTPError(log, r, w, errors.Wrap(err, "failed to get product recommendations"), http.StatusInternalServerError)
-		return
-	}
-
 	product := struct {
 		Item  *pb.Product
 		Price *pb.Money
-	}{p, price}
+		Stock stockLevel
+	}{p, price, fe.stockLevels(r.Context(), log, []string{id})[id]}
 
 	if err := templates.ExecuteTemplate(w, "product", map[string]interface{}{
 		"trace_id":        traceID,
@@ -244,6 +268,10 @@ func (fe *frontendServer) addToCartHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := fe.checkStock(r.Context(), log, sessionID(r), p.GetId(), int32(quantity)); err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not add to cart"), stockErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
 	if err := fe.insertCart(r.Context(), sessionID(r), p.GetId(), int32(quantity)); err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "failed to add to cart"), http.StatusInternalServerError)
 		return
@@ -265,12 +293,24 @@ func (fe *frontendServer) emptyCartHandler(w http.ResponseWriter, r *http.Reques
 }
 
 func (fe *frontendServer) viewCartHandler(w http.ResponseWriter, r *http.Request) {
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	log.Debug("view user cart")
+	address := cartQuoteAddress
+	if r.FormValue("street_address") != "" {
+		// the shipping selector keeps the address the checkout form was sent with
+		address = formAddress(r)
+	}
+	fe.renderCart(w, r, http.StatusOK, address, "")
+}
+
+// renderCart renders the cart page with shipping quoted to address, which the
+// checkout form starts with, and notice shown above the form if it is set.
+func (fe *frontendServer) renderCart(w http.ResponseWriter, r *http.Request, code int, address *pb.Address, notice string) {
 	ctx := r.Context()
 	span := trace.SpanFromContext(ctx)
 	traceID := span.SpanContext().TraceID().String()
 	spanID := span.SpanContext().SpanID().String()
 	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
-	log.Debug("view user cart")
 	currencies, err := fe.getCurrencies(r.Context())
 	if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve currencies"), http.StatusInternalServerError)
@@ -282,47 +322,61 @@ func (fe *frontendServer) viewCartHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	recommendations, err := fe.getRecommendations(r.Context(), sessionID(r), cartIDs(cart))
+	// look up the cart's products and the recommended ones in a single call
+	recommendationIDs, err := fe.getRecommendationIDs(r.Context(), sessionID(r), cartIDs(cart))
 	if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "failed to get product recommendations"), http.StatusInternalServerError)
 		return
 	}
+	products, missing, err := fe.getProductsByID(r.Context(), append(cartIDs(cart), recommendationIDs...))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve products"), http.StatusInternalServerError)
+		return
+	}
+	if len(missing) > 0 {
+		span.SetAttributes(attribute.StringSlice("app.products.missing", missing))
+	}
+	recommendations := pickRecommendations(recommendationIDs, products)
 
-	shippingCost, err := fe.getShippingQuote(r.Context(), cart, currentCurrency(r))
+	shippingOptions, selected, err := fe.getShippingQuote(r.Context(), cart, products, address, currentCurrency(r), r.FormValue("shipping_option"))
 	if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "failed to get shipping quote"), http.StatusInternalServerError)
 		return
 	}
+	shipping := shippingOptions[selected]
 
 	type cartItemView struct {
 		Item     *pb.Product
 		Quantity int32
 		Price    *pb.Money
+		// Missing is set for a product that is no longer in the catalog,
+		// in which case only Item.Id is set.
+		Missing bool
+	}
+	cartProducts, err := fe.getCartProducts(r.Context(), cart, products, currentCurrency(r))
+	if err != nil {
+		renderHTTPError(log, r, w, err, http.StatusInternalServerError)
+		return
 	}
 	items := make([]cartItemView, len(cart))
 	totalPrice := pb.Money{CurrencyCode: currentCurrency(r)}
-	for i, item := range cart {
-		p, err := fe.getProduct(r.Context(), item.GetProductId())
-		if err != nil {
-			renderHTTPError(log, r, w, errors.Wrapf(err, "could not retrieve product #%s", item.GetProductId()), http.StatusInternalServerError)
-			return
-		}
-		price, err := fe.convertCurrency(r.Context(), p.GetPriceUsd(), currentCurrency(r))
-		if err != nil {
-			renderHTTPError(log, r, w, errors.Wrapf(err, "could not convert currency for product #%s", item.GetProductId()), http.StatusInternalServerError)
-			return
+	for i, cp := range cartProducts {
+		if cp.product == nil {
+			items[i] = cartItemView{Item: &pb.Product{Id: cp.item.GetProductId()}, Quantity: cp.item.GetQuantity(), Missing: true}
+			continue
 		}
-
-		multPrice := money.MultiplySlow(*price, uint32(item.GetQuantity()))
+		multPrice := money.MultiplySlow(*cp.price, uint32(cp.item.GetQuantity()))
 		items[i] = cartItemView{
-			Item:     p,
-			Quantity: item.GetQuantity(),
+			Item:     cp.product,
+			Quantity: cp.item.GetQuantity(),
 			Price:    &multPrice}
 		totalPrice = money.Must(money.Sum(totalPrice, multPrice))
 	}
-	totalPrice = money.Must(money.Sum(totalPrice, *shippingCost))
+	subtotal := totalPrice
+	totalPrice = money.Must(money.Sum(totalPrice, *shipping.Cost))
 
 	year := time.Now().Year()
+	w.WriteHeader(code)
 	if err := templates.ExecuteTemplate(w, "cart", map[string]interface{}{
 		"trace_id":         traceID,
 		"span_id":          spanID,
@@ -332,11 +386,16 @@ func (fe *frontendServer) viewCartHandler(w http.ResponseWriter, r *http.Request
 		"currencies":       currencies,
 		"recommendations":  recommendations,
 		"cart_size":        cartSize(cart),
-		"shipping_cost":    shippingCost,
+		"shipping_options": shippingOptions,
+		"shipping":         shipping,
+		"subtotal":         subtotal,
 		"show_currency":    true,
 		"total_cost":       totalPrice,
 		"items":            items,
+		"address":          address,
+		"notice":           notice,
 		"expiration_years": []int{year, year + 1, year + 2, year + 3, year + 4},
+		"idempotency_key":  uuid.New().String(),
 		"platform_css":     plat.css,
 		"platform_name":    plat.provider,
 	}); err != nil {
@@ -353,67 +412,53 @@ func (fe *frontendServer) placeOrderHandler(w http.ResponseWriter, r *http.Reque
 	log.Debug("placing order")
 
 	var (
-		email         = r.FormValue("email")
-		streetAddress = r.FormValue("street_address")
-		zipCode, _    = strconv.ParseInt(r.FormValue("zip_code"), 10, 32)
-		city          = r.FormValue("city")
-		state         = r.FormValue("state")
-		country       = r.FormValue("country")
-		ccNumber      = r.FormValue("credit_card_number")
-		ccMonth, _    = strconv.ParseInt(r.FormValue("credit_card_expiration_month"), 10, 32)
-		ccYear, _     = strconv.ParseInt(r.FormValue("credit_card_expiration_year"), 10, 32)
-		ccCVV, _      = strconv.ParseInt(r.FormValue("credit_card_cvv"), 10, 32)
-		s             = sessionID(r)
+		email      = r.FormValue("email")
+		address    = formAddress(r)
+		ccNumber   = r.FormValue("credit_card_number")
+		ccMonth, _ = strconv.ParseInt(r.FormValue("credit_card_expiration_month"), 10, 32)
+		ccYear, _  = strconv.ParseInt(r.FormValue("credit_card_expiration_year"), 10, 32)
+		ccCVV, _   = strconv.ParseInt(r.FormValue("credit_card_cvv"), 10, 32)
+		promoCode  = r.FormValue("promo_code")
+		shipping   = r.FormValue("shipping_option")
+		s          = sessionID(r)
 	)
 
-	reqIDRaw := ctx.Value(ctxKeyRequestID{}) // reqIDRaw at this point is of type 'interface{}'
-	reqID := reqIDRaw.(string)
-
-	// add the UserID and requestId into OpenTelemetry Baggage to propagate across services
-	userIdMember, _ := baggage.NewMember("app.user_id", s)
-	requestIdMember, _ := baggage.NewMember("app.request_id", reqID)
-	buildIdMember, _ := baggage.NewMember("app.build_id", MockBuildId)
-	bags := baggage.FromContext(ctx)
-	bags, _ = bags.SetMember(userIdMember)
-	bags, _ = bags.SetMember(requestIdMember)
-	bags, _ = bags.SetMember(buildIdMember)
-	ctx = baggage.ContextWithBaggage(ctx, bags)
-
-	order, err := fe.checkoutSvcClient.
-		PlaceOrder(ctx, &pb.PlaceOrderRequest{
-			Email: email,
-			CreditCard: &pb.CreditCardInfo{
-				CreditCardNumber:          ccNumber,
-				CreditCardExpirationMonth: int32(ccMonth),
-				CreditCardExpirationYear:  int32(ccYear),
-				CreditCardCvv:             int32(ccCVV)},
-			UserId:       sessionID(r),
-			UserCurrency: currentCurrency(r),
-			Address: &pb.Address{
-				StreetAddress: streetAddress,
-				City:          city,
-				State:         state,
-				ZipCode:       int32(zipCode),
-				Country:       country},
-		})
-	if err != nil {
+	order, err := fe.placeOrder(orderContext(r), &pb.PlaceOrderRequest{
+		Email: email,
+		CreditCard: &pb.CreditCardInfo{
+			CreditCardNumber:          ccNumber,
+			CreditCardExpirationMonth: int32(ccMonth),
+			CreditCardExpirationYear:  int32(ccYear),
+			CreditCardCvv:             int32(ccCVV)},
+		UserId:           s,
+		UserCurrency:     currentCurrency(r),
+		Address:          address,
+		PromoCode:        promoCode,
+		ShippingOptionId: shipping,
+	})
+	if status.Code(err) == grpccodes.InvalidArgument {
+		// most likely a promo code, or a shipping option picked before the
+		// address was given that isn't offered to it: show the cart quoted
+		// to the address so the customer can pick again
+		log.WithError(err).Warn("order rejected")
+		fe.renderCart(w, r, http.StatusBadRequest, address, status.Convert(err).Message())
+		return
+	} else if status.Code(err) == grpccodes.FailedPrecondition {
+		// the cart has products that are no longer in the catalog
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not place the order"), http.StatusConflict)
+		return
+	} else if err != nil {
 		renderHTTPError(log, r, w, errors.Wrap(err, "failed to complete the order"), http.StatusInternalServerError)
 		return
 	}
-	log.WithField("order", order.GetOrder().GetOrderId()).Info("order placed")
+	log.WithField("order", order.GetOrderId()).Info("order placed")
 
-	order.GetOrder().GetItems()
 	recommendations, _ := fe.getRecommendations(r.Context(), sessionID(r), nil)
 
-	totalPaid := *order.GetOrder().GetShippingCost()
-	for _, v := range order.GetOrder().GetItems() {
-		multPrice := money.MultiplySlow(*v.GetCost(), uint32(v.GetItem().GetQuantity()))
-		totalPaid = money.Must(money.Sum(totalPaid, multPrice))
-	}
+	totalPaid := orderTotal(order)
 
 	// add total paid to span
-	totalPaidNum, err := strconv.ParseFloat(fmt.Sprintf("%d.%02d", totalPaid.GetUnits(), totalPaid.GetNanos()/10000000), 64)
-	span.SetAttributes(attribute.Key("app.cart_total").Float64(totalPaidNum))
+	span.SetAttributes(cartTotalAttribute(totalPaid))
 
 	currencies, err := fe.getCurrencies(r.Context())
 	if err != nil {
@@ -429,8 +474,9 @@ func (fe *frontendServer) placeOrderHandler(w http.ResponseWriter, r *http.Reque
 		"user_currency":   currentCurrency(r),
 		"show_currency":   false,
 		"currencies":      currencies,
-		"order":           order.GetOrder(),
-		"total_paid":      &totalPaid,
+		"order":           order,
+		"subtotal":        orderSubtotal(order),
+		"total_paid":      totalPaid,
 		"recommendations": recommendations,
 		"platform_css":    plat.css,
 		"platform_name":   plat.provider,
@@ -439,6 +485,171 @@ func (fe *frontendServer) placeOrderHandler(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+func (fe *frontendServer) listOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	log.Debug("view order history")
+
+	orders, err := fe.listOrders(r.Context(), sessionID(r))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve orders"), http.StatusInternalServerError)
+		return
+	}
+	cart, err := fe.getCart(r.Context(), sessionID(r))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve cart"), http.StatusInternalServerError)
+		return
+	}
+	span.SetAttributes(attribute.Int("app.orders.count", len(orders)))
+
+	type orderView struct {
+		Order     *pb.OrderResult
+		PlacedAt  string
+		ItemCount int
+		Total     *pb.Money
+	}
+	views := make([]orderView, len(orders))
+	for i, o := range orders {
+		items := 0
+		for _, it := range o.GetItems() {
+			items += int(it.GetItem().GetQuantity())
+		}
+		views[i] = orderView{o, renderOrderTime(o), items, orderTotal(o)}
+	}
+
+	if err := templates.ExecuteTemplate(w, "orders", map[string]interface{}{
+		"trace_id":      traceID,
+		"span_id":       spanID,
+		"session_id":    sessionID(r),
+		"request_id":    r.Context().Value(ctxKeyRequestID{}),
+		"show_currency": false,
+		"cart_size":     cartSize(cart),
+		"orders":        views,
+		"platform_css":  plat.css,
+		"platform_name": plat.provider,
+	}); err != nil {
+		log.Println(err)
+	}
+}
+
+func (fe *frontendServer) viewOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	id := mux.Vars(r)["id"]
+	log.WithField("order", id).Debug("view order")
+	span.SetAttributes(attribute.String("app.order_id", id))
+
+	order, err := fe.getOrder(r.Context(), sessionID(r), id)
+	if status.Code(err) == grpccodes.NotFound {
+		renderHTTPError(log, r, w, errors.Wrapf(err, "order %s not found", id), http.StatusNotFound)
+		return
+	} else if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve order"), http.StatusInternalServerError)
+		return
+	}
+	cart, err := fe.getCart(r.Context(), sessionID(r))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve cart"), http.StatusInternalServerError)
+		return
+	}
+	totalPaid := orderTotal(order)
+
+	if err := templates.ExecuteTemplate(w, "order", map[string]interface{}{
+		"trace_id":      traceID,
+		"span_id":       spanID,
+		"session_id":    sessionID(r),
+		"request_id":    r.Context().Value(ctxKeyRequestID{}),
+		"show_currency": false,
+		"cart_size":     cartSize(cart),
+		"order":         order,
+		"placed_at":     renderOrderTime(order),
+		"subtotal":      orderSubtotal(order),
+		"total_paid":    totalPaid,
+		"platform_css":  plat.css,
+		"platform_name": plat.provider,
+	}); err != nil {
+		log.Println(err)
+	}
+}
+
+// shipmentStatusLabels are how shipment statuses are shown to the customer.
+var shipmentStatusLabels = map[pb.ShipmentStatus]string{
+	pb.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED:    "Label created",
+	pb.ShipmentStatus_SHIPMENT_STATUS_PICKED_UP:        "Picked up",
+	pb.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT:       "In transit",
+	pb.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY: "Out for delivery",
+	pb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED:        "Delivered",
+	pb.ShipmentStatus_SHIPMENT_STATUS_EXCEPTION:        "Delayed",
+	pb.ShipmentStatus_SHIPMENT_STATUS_CANCELLED:        "Cancelled",
+}
+
+func (fe *frontendServer) trackShipmentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+	id := mux.Vars(r)["id"]
+	log.WithField("tracking_id", id).Debug("track shipment")
+	span.SetAttributes(attribute.String("app.tracking_id", id))
+
+	shipment, err := fe.trackShipment(r.Context(), id)
+	if status.Code(err) == grpccodes.NotFound {
+		renderHTTPError(log, r, w, errors.Wrapf(err, "shipment %s not found", id), http.StatusNotFound)
+		return
+	} else if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not track shipment"), http.StatusInternalServerError)
+		return
+	}
+	cart, err := fe.getCart(r.Context(), sessionID(r))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve cart"), http.StatusInternalServerError)
+		return
+	}
+	span.SetAttributes(attribute.String("app.shipment.status", shipment.GetStatus().String()))
+
+	type eventView struct {
+		Status      string
+		Time        string
+		Location    string
+		Description string
+	}
+	// newest first
+	events := make([]eventView, len(shipment.GetEvents()))
+	for i, e := range shipment.GetEvents() {
+		events[len(events)-1-i] = eventView{
+			Status:      shipmentStatusLabels[e.GetStatus()],
+			Time:        time.Unix(e.GetTime(), 0).UTC().Format("January 2, 2006 15:04 MST"),
+			Location:    e.GetLocation(),
+			Description: e.GetDescription(),
+		}
+	}
+
+	if err := templates.ExecuteTemplate(w, "track", map[string]interface{}{
+		"trace_id":      traceID,
+		"span_id":       spanID,
+		"session_id":    sessionID(r),
+		"request_id":    r.Context().Value(ctxKeyRequestID{}),
+		"show_currency": false,
+		"cart_size":     cartSize(cart),
+		"shipment":      shipment,
+		"status":        shipmentStatusLabels[shipment.GetStatus()],
+		"delivered":     shipment.GetStatus() == pb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED,
+		"cancelled":     shipment.GetStatus() == pb.ShipmentStatus_SHIPMENT_STATUS_CANCELLED,
+		"events":        events,
+		"platform_css":  plat.css,
+		"platform_name": plat.provider,
+	}); err != nil {
+		log.Println(err)
+	}
+}
+
 func (fe *frontendServer) logoutHandler(w http.ResponseWriter, r *http.Request) {
 	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
 	log.Debug("logging out")
@@ -515,6 +726,17 @@ func renderHTTPError(log logrus.FieldLogger, r *http.Request, w http.ResponseWri
 	}
 }
 
+// formAddress is the shipping address sent with the checkout form.
+func formAddress(r *http.Request) *pb.Address {
+	zipCode, _ := strconv.ParseInt(r.FormValue("zip_code"), 10, 32)
+	return &pb.Address{
+		StreetAddress: r.FormValue("street_address"),
+		City:          r.FormValue("city"),
+		State:         r.FormValue("state"),
+		ZipCode:       int32(zipCode),
+		Country:       r.FormValue("country")}
+}
+
 func currentCurrency(r *http.Request) string {
 	c, _ := r.Cookie(cookieCurrency)
 	if c != nil {
@@ -531,6 +753,45 @@ func sessionID(r *http.Request) string {
 	return ""
 }
 
+// orderContext returns the request context with what checkout needs to
+// propagate: the user, request and build ids as baggage, and the idempotency key.
+func orderContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	reqID, _ := ctx.Value(ctxKeyRequestID{}).(string)
+
+	// add the UserID and requestId into OpenTelemetry Baggage to propagate across services
+	userIdMember, _ := baggage.NewMember("app.user_id", sessionID(r))
+	requestIdMember, _ := baggage.NewMember("app.request_id", reqID)
+	buildIdMember, _ := baggage.NewMember("app.build_id", currentBuildId())
+	bags := baggage.FromContext(ctx)
+	bags, _ = bags.SetMember(userIdMember)
+	bags, _ = bags.SetMember(requestIdMember)
+	bags, _ = bags.SetMember(buildIdMember)
+	ctx = baggage.ContextWithBaggage(ctx, bags)
+
+	// pass the idempotency key along so a double-submitted form is only charged once
+	if key := idempotencyKey(r); key != "" {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("app.idempotency_key", key))
+		ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyHeader, key)
+	}
+	return ctx
+}
+
+// cartTotalAttribute records the amount paid for an order as app.cart_total.
+func cartTotalAttribute(total *pb.Money) attribute.KeyValue {
+	totalNum, _ := strconv.ParseFloat(fmt.Sprintf("%d.%02d", total.GetUnits(), total.GetNanos()/10000000), 64)
+	return attribute.Key("app.cart_total").Float64(totalNum)
+}
+
+// idempotencyKey returns the key identifying a checkout attempt, taken from
+// the Idempotency-Key header for API clients or the form for the cart page.
+func idempotencyKey(r *http.Request) string {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		return key
+	}
+	return r.FormValue("idempotency_key")
+}
+
 func cartIDs(c []*pb.CartItem) []string {
 	out := make([]string, len(c))
 	for i, v := range c {
@@ -548,6 +809,51 @@ func cartSize(c []*pb.CartItem) int {
 	return cartSize
 }
 
+// orderSubtotal is the cost of the items of an order. Orders placed before
+// checkout calculated tax don't carry it, so it is summed from the items.
+func orderSubtotal(order *pb.OrderResult) *pb.Money {
+	if order.GetSubtotal() != nil {
+		return order.GetSubtotal()
+	}
+	subtotal := &pb.Money{CurrencyCode: order.GetShippingCost().GetCurrencyCode()}
+	for _, v := range order.GetItems() {
+		multPrice := money.MultiplySlow(money.Value(v.GetCost()), uint32(v.GetItem().GetQuantity()))
+		subtotal = addMoney(subtotal, &multPrice)
+	}
+	return subtotal
+}
+
+// orderTotal is the amount paid for an order, its items less discounts, plus
+// shipping and tax.
+func orderTotal(order *pb.OrderResult) *pb.Money {
+	total := addMoney(orderSubtotal(order), order.GetShippingCost())
+	for _, d := range order.GetDiscounts() {
+		discount := money.Negate(money.Value(d.GetAmount()))
+		total = addMoney(total, &discount)
+	}
+	return addMoney(total, order.GetTax())
+}
+
+// addMoney returns a plus b. A missing amount, or a zero one without a
+// currency, counts as zero in the currency of the other.
+func addMoney(a, b *pb.Money) *pb.Money {
+	switch {
+	case b == nil || b.GetCurrencyCode() == "" && money.IsZero(money.Value(b)):
+		return a
+	case a == nil || a.GetCurrencyCode() == "" && money.IsZero(money.Value(a)):
+		return b
+	}
+	sum := money.Must(money.Sum(money.Value(a), money.Value(b)))
+	return &sum
+}
+
+func renderOrderTime(order *pb.OrderResult) string {
+	if order.GetPlacedAt() == 0 {
+		return ""
+	}
+	return time.Unix(order.GetPlacedAt(), 0).UTC().Format("January 2, 2006 15:04 MST")
+}
+
 func renderMoney(money pb.Money) string {
 	return fmt.Sprintf("%s %d.%02d", money.GetCurrencyCode(), money.GetUnits(), money.GetNanos()/10000000)
 }
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+)
+
+// newBigShop returns a fake shop with n products, all of them in the cart of
+// "user-1", behind the home and cart pages.
+func newBigShop(n int, delay time.Duration, limit int) (*fakeShop, http.Handler) {
+	shop := newFakeShop()
+	shop.delay = delay
+	shop.products = nil
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("PRODUCT%02d", i)
+		shop.products = append(shop.products, &pb.Product{Id: id, Name: "Product " + id, PriceUsd: &pb.Money{CurrencyCode: "USD", Units: int64(i + 1)}})
+		shop.carts["user-1"] = append(shop.carts["user-1"], &pb.CartItem{ProductId: id, Quantity: 1})
+	}
+	fe := shop.server()
+	fe.fanoutLimit = limit
+	r := mux.NewRouter()
+	r.HandleFunc("/", fe.homeHandler)
+	r.HandleFunc("/cart", fe.viewCartHandler)
+	return shop, withTestSession("user-1", r)
+}
+
+func TestPagesListProductsInOrder(t *testing.T) {
+	_, h := newBigShop(12, time.Millisecond, 4)
+	for _, path := range []string{"/", "/cart"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", path, rec.Code, rec.Body.String())
+		}
+		body := rec.Body.String()
+		last := -1
+		for i := 0; i < 12; i++ {
+			at := strings.Index(body, fmt.Sprintf("Product PRODUCT%02d", i))
+			if at < last {
+				t.Errorf("GET %s: product %d is out of order", path, i)
+			}
+			last = at
+		}
+	}
+}
+
+func TestCartPageShowsMissingProducts(t *testing.T) {
+	shop, h := newBigShop(2, 0, 0)
+	shop.carts["user-1"] = append(shop.carts["user-1"], &pb.CartItem{ProductId: "MISSING", Quantity: 3})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cart", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "No longer available") || !strings.Contains(body, "#MISSING") {
+		t.Error("the missing product isn't listed")
+	}
+	// the two products cost $1 and $2
+	if !strings.Contains(body, "Subtotal: <strong>USD 3.00</strong>") {
+		t.Error("the missing product is counted in the subtotal")
+	}
+}
+
+// TestPagesMakeOneCatalogCall renders each shop page through the real gRPC
+// clients and checks that its trace has a single catalog span, however many
+// products the page shows.
+func TestPagesMakeOneCatalogCall(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	fe := newContractServer(t, grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp))))
+	handler := fe.handler(&logrus.Logger{Out: io.Discard, Formatter: new(logrus.TextFormatter), Level: logrus.PanicLevel})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tp.Tracer("test").Start(r.Context(), r.Method+" "+r.URL.Path)
+		defer span.End()
+		w.Header().Set("X-Trace-Id", span.SpanContext().TraceID().String())
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer ts.Close()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	for _, id := range []string{"OLJCESPC7Z", "66VCHSJNUP"} {
+		resp, err := client.PostForm(ts.URL+"/cart", url.Values{"product_id": {id}, "quantity": {"1"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	for _, path := range []string{"/", "/product/OLJCESPC7Z", "/cart"} {
+		resp, err := client.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s returned %d", path, resp.StatusCode)
+		}
+		var calls []string
+		for _, s := range sr.Ended() {
+			if s.SpanContext().TraceID().String() == resp.Header.Get("X-Trace-Id") &&
+				strings.HasPrefix(s.Name(), "msdemo.ProductCatalogService/") {
+				calls = append(calls, s.Name())
+			}
+		}
+		if len(calls) != 1 {
+			t.Errorf("GET %s made %d catalog calls %v, want 1", path, len(calls), calls)
+		}
+	}
+}
+
+// BenchmarkPages renders the home and cart pages of a shop with 20 products,
+// all in the cart, against backends that take 2ms a call, looking products
+// up one at a time and with the default limit.
+func BenchmarkPages(b *testing.B) {
+	for page, path := range map[string]string{"home": "/", "cart": "/cart"} {
+		for _, limit := range []int{1, 0} {
+			name := fmt.Sprintf("%s/limit=%d", page, limit)
+			if limit == 0 {
+				name = page + "/limit=default"
+			}
+			b.Run(name, func(b *testing.B) {
+				_, h := newBigShop(20, 2*time.Millisecond, limit)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					rec := httptest.NewRecorder()
+					h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+					if rec.Code != http.StatusOK {
+						b.Fatalf("GET %s returned %d", path, rec.Code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestOrderTotal(t *testing.T) {
+	usd := func(units int64) *pb.Money { return &pb.Money{CurrencyCode: "USD", Units: units} }
+	for name, tc := range map[string]struct {
+		order           *pb.OrderResult
+		subtotal, total int64
+	}{
+		"complete": {&pb.OrderResult{
+			Subtotal: usd(20), ShippingCost: usd(5), Tax: usd(2),
+			Discounts: []*pb.AppliedDiscount{{Amount: usd(1)}},
+		}, 20, 26},
+		"without subtotal or tax": {&pb.OrderResult{
+			ShippingCost: usd(5),
+			Items:        []*pb.OrderItem{{Item: &pb.CartItem{Quantity: 2}, Cost: usd(10)}},
+		}, 20, 25},
+		"partly filled": {&pb.OrderResult{
+			Items:     []*pb.OrderItem{{Item: &pb.CartItem{Quantity: 2}, Cost: usd(10)}, {Item: &pb.CartItem{Quantity: 1}}},
+			Discounts: []*pb.AppliedDiscount{{Code: "SAVE1"}},
+		}, 20, 20},
+		"empty": {&pb.OrderResult{}, 0, 0},
+	} {
+		if got := orderSubtotal(tc.order); got.GetUnits() != tc.subtotal {
+			t.Errorf("%s: got subtotal %v, want %d", name, got, tc.subtotal)
+		}
+		if got := orderTotal(tc.order); got.GetUnits() != tc.total {
+			t.Errorf("%s: got total %v, want %d", name, got, tc.total)
+		}
+	}
+}
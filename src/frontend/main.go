@@ -9,10 +9,14 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
 	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	middleware "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
@@ -39,6 +43,10 @@ const (
 	cookiePrefix    = "shop_"
 	cookieSessionID = cookiePrefix + "session-id"
 	cookieCurrency  = cookiePrefix + "currency"
+
+	// idempotencyKeyHeader is the gRPC metadata key checkout reads the
+	// idempotency key of an order from.
+	idempotencyKeyHeader = "idempotency-key"
 )
 
 var (
@@ -63,6 +71,8 @@ type frontendServer struct {
 
 	cartSvcAddr   string
 	cartSvcClient pb.CartServiceClient
+	// cartLocks serialize the changes to each cart, see lockCart.
+	cartLocks [64]sync.Mutex
 
 	currencySvcAddr   string
 	currencySvcClient pb.CurrencyServiceClient
@@ -70,18 +80,40 @@ type frontendServer struct {
 	productCatalogSvcAddr   string
 	productCatalogSvcClient pb.ProductCatalogServiceClient
 
+	inventorySvcAddr   string
+	inventorySvcClient pb.InventoryServiceClient
+
 	recommendationSvcAddr   string
 	recommendationSvcClient pb.RecommendationServiceClient
 
 	shippingSvcAddr   string
 	shippingSvcClient pb.ShippingServiceClient
+
+	faults *faults.Registry
+
+	// fanoutLimit caps how many products a page looks up at once.
+	fanoutLimit int
+
+	// adminToken authenticates requests to the /admin endpoints, which are
+	// disabled when it is empty, and is passed on to the backends' AdminService.
+	adminToken     string
+	admin          *admin.Settings
+	adminSvcClient map[string]pb.AdminServiceClient
 }
 
 var CacheTrack *CacheTracker
-var PercentNormal = 75
+var PercentNormal atomic.Int32 // see main for the default
 var CacheUserThreshold = 35000
 var CacheMarkerThreshold = 30000
-var MockBuildId = ""
+
+// MockBuildId holds the build id reported as app.build_id. It changes
+// whenever CacheTracker creates a deploy marker.
+var MockBuildId atomic.Value
+
+func currentBuildId() string {
+	id, _ := MockBuildId.Load().(string)
+	return id
+}
 
 func main() {
 
@@ -107,9 +139,10 @@ func main() {
 	tp := initOtelTracing(ctx, log)
 	defer func() { _ = tp.Shutdown(ctx) }()
 
+	PercentNormal.Store(75)
 	p, err := strconv.Atoi(os.Getenv("PERCENT_NORMAL"))
 	if err == nil {
-		PercentNormal = p
+		PercentNormal.Store(int32(p))
 	}
 	cut, err := strconv.Atoi(os.Getenv("CACHE_USER_THRESHOLD"))
 	if err == nil {
@@ -119,8 +152,11 @@ func main() {
 	if err == nil {
 		CacheMarkerThreshold = cmt
 	}
-	apiKey := os.Getenv("HONEYCOMB_API_KEY")
-	CacheTrack = NewCacheTracker(CacheUserThreshold, CacheMarkerThreshold, apiKey, log)
+	markerSinks, err := markerSinksFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure marker sinks: %+v", err)
+	}
+	CacheTrack = NewCacheTracker(CacheUserThreshold, CacheMarkerThreshold, markerSinks, log)
 
 	srvPort := port
 	if os.Getenv("PORT") != "" {
@@ -129,9 +165,22 @@ func main() {
 
 	addr := os.Getenv("LISTEN_ADDR")
 
-	MockBuildId = randomHex(4)
+	MockBuildId.Store(randomHex(4))
 
 	svc := new(frontendServer)
+	if n, err := strconv.Atoi(os.Getenv("FANOUT_LIMIT")); err == nil {
+		svc.fanoutLimit = n
+	}
+	svc.faults, err = newFaultRegistry(os.Getenv("FAULTS_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load fault scenarios: %+v", err)
+	}
+	if err := initFlags(os.Getenv("FLAGS_CONFIG"), log); err != nil {
+		log.Fatalf("failed to load feature flags: %+v", err)
+	}
+	svc.adminToken = os.Getenv("ADMIN_TOKEN")
+	svc.admin = newAdminSettings(svc.faults)
+	svc.adminSvcClient = make(map[string]pb.AdminServiceClient)
 	mustMapEnv(&svc.adSvcAddr, "AD_SERVICE_ADDR")
 	c := mustCreateClientConn(svc.adSvcAddr)
 	svc.adSvcClient = pb.NewAdServiceClient(c)
@@ -145,6 +194,7 @@ func main() {
 	mustMapEnv(&svc.checkoutSvcAddr, "CHECKOUT_SERVICE_ADDR")
 	c = mustCreateClientConn(svc.checkoutSvcAddr)
 	svc.checkoutSvcClient = pb.NewCheckoutServiceClient(c)
+	svc.adminSvcClient["checkout"] = pb.NewAdminServiceClient(c)
 	defer c.Close()
 
 	mustMapEnv(&svc.currencySvcAddr, "CURRENCY_SERVICE_ADDR")
@@ -155,6 +205,16 @@ func main() {
 	mustMapEnv(&svc.productCatalogSvcAddr, "PRODUCT_CATALOG_SERVICE_ADDR")
 	c = mustCreateClientConn(svc.productCatalogSvcAddr)
 	svc.productCatalogSvcClient = pb.NewProductCatalogServiceClient(c)
+	svc.adminSvcClient["productcatalog"] = pb.NewAdminServiceClient(c)
+	defer c.Close()
+
+	// the inventory is served by the product catalog service unless it moves
+	svc.inventorySvcAddr = os.Getenv("INVENTORY_SERVICE_ADDR")
+	if svc.inventorySvcAddr == "" {
+		svc.inventorySvcAddr = svc.productCatalogSvcAddr
+	}
+	c = mustCreateClientConn(svc.inventorySvcAddr)
+	svc.inventorySvcClient = pb.NewInventoryServiceClient(c)
 	defer c.Close()
 
 	mustMapEnv(&svc.recommendationSvcAddr, "RECOMMENDATION_SERVICE_ADDR")
@@ -165,6 +225,7 @@ func main() {
 	mustMapEnv(&svc.shippingSvcAddr, "SHIPPING_SERVICE_ADDR")
 	c = mustCreateClientConn(svc.shippingSvcAddr)
 	svc.shippingSvcClient = pb.NewShippingServiceClient(c)
+	svc.adminSvcClient["shipping"] = pb.NewAdminServiceClient(c)
 	defer c.Close()
 
 	// getCache connection is not instrumented
@@ -176,32 +237,59 @@ func main() {
 	}
 	svc.getCacheClient = pb.NewCheckoutServiceClient(conn)
 
+	handler := svc.handler(log)
+
+	CacheTrack.Track(ctx, svc)
+
+	log.Infof("starting server on " + addr + ":" + srvPort)
+	log.Fatal(http.ListenAndServe(addr+":"+srvPort, handler))
+}
+
+// handler returns the frontend's routes, wrapped in the logging and session
+// middleware.
+func (fe *frontendServer) handler(log *logrus.Logger) http.Handler {
+	var handler http.Handler = fe.router()
+	handler = &logHandler{log: log, next: handler} // add logging
+	handler = ensureSessionID(handler)             // add session ID
+	return handler
+}
+
+// router registers every route the frontend serves. Keep openapi.json in step.
+func (fe *frontendServer) router() *mux.Router {
 	r := mux.NewRouter()
 
-	r.HandleFunc("/", instrumentHandler(svc.homeHandler)).Methods(http.MethodGet, http.MethodHead)
-	r.HandleFunc("/product/{id}", instrumentHandler(svc.productHandler)).Methods(http.MethodGet, http.MethodHead)
-	r.HandleFunc("/cart", instrumentHandler(svc.viewCartHandler)).Methods(http.MethodGet, http.MethodHead)
-	r.HandleFunc("/cart", instrumentHandler(svc.addToCartHandler)).Methods(http.MethodPost)
-	r.HandleFunc("/cart/empty", instrumentHandler(svc.emptyCartHandler)).Methods(http.MethodPost)
-	r.HandleFunc("/setCurrency", instrumentHandler(svc.setCurrencyHandler)).Methods(http.MethodPost)
-	r.HandleFunc("/logout", instrumentHandler(svc.logoutHandler)).Methods(http.MethodGet)
-	r.HandleFunc("/cart/checkout", instrumentHandler(svc.placeOrderHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/", instrumentHandler(fe.homeHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/product/{id}", instrumentHandler(fe.productHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/search", instrumentHandler(fe.searchHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/cart", instrumentHandler(fe.viewCartHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/cart", instrumentHandler(fe.addToCartHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/cart/empty", instrumentHandler(fe.emptyCartHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/setCurrency", instrumentHandler(fe.setCurrencyHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/logout", instrumentHandler(fe.logoutHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/cart/checkout", instrumentHandler(fe.placeOrderHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/orders", instrumentHandler(fe.listOrdersHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/orders/{id}", instrumentHandler(fe.viewOrderHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/track/{id}", instrumentHandler(fe.trackShipmentHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/admin/settings", instrumentHandler(fe.requireAdmin(fe.getAdminSettingsHandler))).Methods(http.MethodGet)
+	r.HandleFunc("/admin/settings", instrumentHandler(fe.requireAdmin(fe.updateAdminSettingsHandler))).Methods(http.MethodPut)
+	r.HandleFunc("/admin/settings/{service}", instrumentHandler(fe.requireAdmin(fe.getServiceSettingsHandler))).Methods(http.MethodGet)
+	r.HandleFunc("/admin/settings/{service}", instrumentHandler(fe.requireAdmin(fe.updateServiceSettingsHandler))).Methods(http.MethodPut)
+	r.HandleFunc("/admin/cache/reset", instrumentHandler(fe.requireAdmin(fe.resetCacheHandler))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/catalog", instrumentHandler(fe.adminCatalogHandler)).Methods(http.MethodGet, http.MethodHead)
+	r.HandleFunc("/admin/catalog/products", instrumentHandler(fe.requireAdmin(fe.createCatalogProductHandler))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/catalog/products/{id}", instrumentHandler(fe.requireAdmin(fe.updateCatalogProductHandler))).Methods(http.MethodPut)
+	r.HandleFunc("/admin/catalog/products/{id}", instrumentHandler(fe.requireAdmin(fe.deleteCatalogProductHandler))).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/catalog/products/{id}/price", instrumentHandler(fe.requireAdmin(fe.setCatalogPriceHandler))).Methods(http.MethodPut)
+	fe.apiRoutes(r)
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./static/"))))
 	r.PathPrefix("/dist/").Handler(http.StripPrefix("/dist/", http.FileServer(http.Dir("./dist/"))))
 	r.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) { _, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /") })
 	r.HandleFunc("/_healthz", func(w http.ResponseWriter, _ *http.Request) { _, _ = fmt.Fprint(w, "ok") })
+	r.HandleFunc("/openapi.json", openAPIHandler).Methods(http.MethodGet)
 
 	// Add OpenTelemetry instrumentation to incoming HTTP requests controlled by the gorilla/mux Router.
 	r.Use(middleware.Middleware("frontend"))
-
-	var handler http.Handler = r
-	handler = &logHandler{log: log, next: handler} // add logging
-	handler = ensureSessionID(handler)             // add session ID
-
-	CacheTrack.Track(ctx, svc)
-
-	log.Infof("starting server on " + addr + ":" + srvPort)
-	log.Fatal(http.ListenAndServe(addr+":"+srvPort, handler))
+	return r
 }
 
 func initOtelLogging(ctx context.Context) *sdklog.LoggerProvider {
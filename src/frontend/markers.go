@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+const defaultMarkerURL = "https://github.com/honeycombio/microservices-demo/commit/5645075"
+
+// Marker is the deploy marker created when the checkout cache crosses the
+// marker threshold, standing in for the deploy that "caused" the leak.
+type Marker struct {
+	BuildID   string    `json:"build_id"`
+	Threshold int       `json:"threshold"`
+	CacheSize int       `json:"cache_size"`
+	Time      time.Time `json:"time"`
+}
+
+// Message describes the marker, with the cache size that set it off.
+func (m Marker) Message() string {
+	return fmt.Sprintf("Deploy %s (checkout cache at %d, threshold %d)", m.BuildID, m.CacheSize, m.Threshold)
+}
+
+// MarkerSink records deploy markers somewhere presenters can see them.
+type MarkerSink interface {
+	CreateMarker(ctx context.Context, m Marker) error
+}
+
+// markerSinksFromEnv creates the sinks named in MARKER_SINKS, a comma
+// separated list of honeycomb, webhook, otlp and file. Without MARKER_SINKS,
+// markers go to Honeycomb when HONEYCOMB_API_KEY is set.
+func markerSinksFromEnv() ([]MarkerSink, error) {
+	names := os.Getenv("MARKER_SINKS")
+	if names == "" && os.Getenv("HONEYCOMB_API_KEY") != "" {
+		names = "honeycomb"
+	}
+	markerURL := os.Getenv("MARKER_URL")
+	if markerURL == "" {
+		markerURL = defaultMarkerURL
+	}
+
+	var sinks []MarkerSink
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "honeycomb":
+			apiKey := os.Getenv("HONEYCOMB_API_KEY")
+			if apiKey == "" {
+				return nil, errors.New("the honeycomb marker sink needs HONEYCOMB_API_KEY")
+			}
+			apiURL := os.Getenv("HONEYCOMB_API_URL")
+			if apiURL == "" {
+				apiURL = "https://api.honeycomb.io"
+			}
+			dataset := os.Getenv("HONEYCOMB_MARKER_DATASET")
+			if dataset == "" {
+				dataset = "__all__"
+			}
+			sinks = append(sinks, &honeycombMarkerSink{
+				url:       strings.TrimSuffix(apiURL, "/") + "/1/markers/" + dataset,
+				apiKey:    apiKey,
+				markerURL: markerURL,
+				client:    http.DefaultClient,
+			})
+		case "webhook":
+			webhookURL := os.Getenv("MARKER_WEBHOOK_URL")
+			if webhookURL == "" {
+				return nil, errors.New("the webhook marker sink needs MARKER_WEBHOOK_URL")
+			}
+			sinks = append(sinks, &webhookMarkerSink{url: webhookURL, client: http.DefaultClient})
+		case "otlp":
+			sinks = append(sinks, &otlpLogMarkerSink{logger: global.Logger("markers")})
+		case "file":
+			sink, err := newFileMarkerSink(os.Getenv("MARKER_FILE"))
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, errors.Errorf("unknown marker sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// honeycombMarkerSink creates markers with the Honeycomb Markers API.
+type honeycombMarkerSink struct {
+	url       string
+	apiKey    string
+	markerURL string
+	client    *http.Client
+}
+
+func (s *honeycombMarkerSink) CreateMarker(ctx context.Context, m Marker) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"message":    m.Message(),
+		"url":        s.markerURL,
+		"type":       "deploy",
+		"start_time": m.Time.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "could not create request to generate marker")
+	}
+	req.Header.Set("X-Honeycomb-Team", s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	return postMarker(s.client, req)
+}
+
+// webhookMarkerSink posts every Marker as JSON to a URL.
+type webhookMarkerSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookMarkerSink) CreateMarker(ctx context.Context, m Marker) error {
+	payload, err := json.Marshal(struct {
+		Marker
+		Message string `json:"message"`
+	}{m, m.Message()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "could not create request to generate marker")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return postMarker(s.client, req)
+}
+
+func postMarker(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not create marker")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("invalid status code %d when creating marker: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// otlpLogMarkerSink emits markers as log events through the OpenTelemetry
+// logs pipeline, so they reach whatever collector the frontend reports to.
+type otlpLogMarkerSink struct {
+	logger otellog.Logger
+}
+
+func (s *otlpLogMarkerSink) CreateMarker(ctx context.Context, m Marker) error {
+	var record otellog.Record
+	record.SetTimestamp(m.Time)
+	record.SetSeverity(otellog.SeverityInfo)
+	record.SetBody(otellog.StringValue(m.Message()))
+	record.AddAttributes(
+		otellog.String("event.name", "deploy_marker"),
+		otellog.String("app.build_id", m.BuildID),
+		otellog.Int("app.cache_marker_threshold", m.Threshold),
+		otellog.Int("app.cache_size", m.CacheSize),
+	)
+	s.logger.Emit(ctx, record)
+	return nil
+}
+
+// fileMarkerSink appends markers as JSON lines to a file, or to stdout.
+type fileMarkerSink struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// newFileMarkerSink writes to path, or to stdout when path is empty or "-".
+func newFileMarkerSink(path string) (*fileMarkerSink, error) {
+	if path == "" || path == "-" {
+		return &fileMarkerSink{w: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open marker file")
+	}
+	return &fileMarkerSink{w: f}, nil
+}
+
+func (s *fileMarkerSink) CreateMarker(_ context.Context, m Marker) error {
+	line, err := json.Marshal(struct {
+		Marker
+		Message string `json:"message"`
+	}{m, m.Message()})
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
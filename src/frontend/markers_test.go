@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type recordingMarkerSink struct {
+	markers chan Marker
+}
+
+func (s *recordingMarkerSink) CreateMarker(_ context.Context, m Marker) error {
+	s.markers <- m
+	return nil
+}
+
+func testMarker() Marker {
+	return Marker{BuildID: "cafe", Threshold: 1000, CacheSize: 1200, Time: time.Unix(1700000000, 0)}
+}
+
+func TestHoneycombMarkerSink(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/markers/__all__" || r.Header.Get("X-Honeycomb-Team") != "key" {
+			t.Errorf("got request to %s with team %q", r.URL.Path, r.Header.Get("X-Honeycomb-Team"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	t.Setenv("MARKER_SINKS", "")
+	t.Setenv("HONEYCOMB_API_KEY", "key")
+	t.Setenv("HONEYCOMB_API_URL", srv.URL)
+	sinks, err := markerSinksFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 1 {
+		t.Fatalf("got %d sinks, want only honeycomb", len(sinks))
+	}
+	if err := sinks[0].CreateMarker(context.Background(), testMarker()); err != nil {
+		t.Fatal(err)
+	}
+	if got["message"] != "Deploy cafe (checkout cache at 1200, threshold 1000)" || got["type"] != "deploy" || got["url"] != defaultMarkerURL {
+		t.Errorf("got marker %v", got)
+	}
+}
+
+func TestWebhookMarkerSink(t *testing.T) {
+	var got map[string]interface{}
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := &webhookMarkerSink{url: srv.URL, client: srv.Client()}
+	if err := sink.CreateMarker(context.Background(), testMarker()); err != nil {
+		t.Fatal(err)
+	}
+	if got["build_id"] != "cafe" || got["threshold"] != 1000.0 || got["cache_size"] != 1200.0 {
+		t.Errorf("got marker %v", got)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.CreateMarker(context.Background(), testMarker()); err == nil {
+		t.Error("expected an error for a failed webhook")
+	}
+}
+
+func TestFileMarkerSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &fileMarkerSink{w: &buf}
+	if err := sink.CreateMarker(context.Background(), testMarker()); err != nil {
+		t.Fatal(err)
+	}
+	var got Marker
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("wrote invalid JSON %q", buf.String())
+	}
+	if got.BuildID != "cafe" || got.CacheSize != 1200 {
+		t.Errorf("got marker %+v", got)
+	}
+}
+
+func TestMarkerSinksFromEnv(t *testing.T) {
+	t.Setenv("HONEYCOMB_API_KEY", "")
+	t.Setenv("MARKER_SINKS", "file, otlp")
+	sinks, err := markerSinksFromEnv()
+	if err != nil || len(sinks) != 2 {
+		t.Errorf("got %d sinks and %v, want 2", len(sinks), err)
+	}
+	for _, bad := range []string{"carrier-pigeon", "webhook", "honeycomb"} {
+		t.Setenv("MARKER_SINKS", bad)
+		if _, err := markerSinksFromEnv(); err == nil {
+			t.Errorf("MARKER_SINKS=%s should fail without its settings", bad)
+		}
+	}
+}
+
+// TestCacheTrackerCreatesMarker checks that a marker is created once, when the
+// cache size crosses the marker threshold.
+func TestCacheTrackerCreatesMarker(t *testing.T) {
+	sink := &recordingMarkerSink{markers: make(chan Marker, 2)}
+	c := NewCacheTracker(35000, 1000, []MarkerSink{sink}, logrus.New())
+
+	c.updateSize(500)
+	c.updateSize(1200)
+	c.updateSize(1500)
+
+	select {
+	case m := <-sink.markers:
+		if m.Threshold != 1000 || m.CacheSize != 1200 || m.BuildID == "" {
+			t.Errorf("got marker %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no marker was created")
+	}
+	select {
+	case m := <-sink.markers:
+		t.Errorf("got a second marker %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -90,7 +90,7 @@ func instrumentHandler(fn httpHandler) httpHandler {
 			buildIdKey   = attribute.Key("app.build_id")
 		)
 		span := trace.SpanFromContext(r.Context())
-		span.SetAttributes(userIDKey.String(userId), requestIDKey.String(reqID), buildIdKey.String(MockBuildId))
+		span.SetAttributes(userIDKey.String(userId), requestIDKey.String(reqID), buildIdKey.String(currentBuildId()))
 
 		fn(w, r)
 	}
@@ -106,9 +106,11 @@ func ensureSessionID(next http.Handler) http.HandlerFunc {
 		// DEMO: If the checkoutservice Cache size is greater than the userThreshold (default 35000)
 		// AND the request is from the load generator (useragent contains python)
 		// AND rnd > PercentNormal
+		// AND the problem-user-override flag is on
 		// Then we will use a session id of 20109 to emphasize a problematic user
 		// sessionID will be referenced as userid in OpenTelemetry data
-		if CacheTrack.IsOverUserThreshold() && strings.Contains(userAgent, "python") && rnd > PercentNormal {
+		if CacheTrack.IsOverUserThreshold() && strings.Contains(userAgent, "python") && rnd > int(PercentNormal.Load()) &&
+			flagEnabled(r.Context(), flagProblemUserOverride, "", true) {
 			// Use the static sessionID "20109"
 			sessionID = "20109"
 			http.SetCookie(w, &http.Cookie{
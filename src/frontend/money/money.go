@@ -55,6 +55,15 @@ func AreEquals(l, r pb.Money) bool {
 		l.GetUnits() == r.GetUnits() && l.GetNanos() == r.GetNanos()
 }
 
+// Value returns the amount m points to as a new value, for passing amounts
+// held as *pb.Money to the functions here without copying the message.
+func Value(m *pb.Money) pb.Money {
+	return pb.Money{
+		Units:        m.GetUnits(),
+		Nanos:        m.GetNanos(),
+		CurrencyCode: m.GetCurrencyCode()}
+}
+
 // Negate returns the same amount with the sign negated.
 func Negate(m pb.Money) pb.Money {
 	return pb.Money{
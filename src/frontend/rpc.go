@@ -2,16 +2,17 @@ package main
 
 import (
 	"context"
+	"hash/fnv"
+
 	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/frontend/money"
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	"github.com/honeycombio/microservices-demo/src/internal/fanout"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
 	apiTrace "go.opentelemetry.io/otel/trace"
 )
 
-const (
-	avoidNoopCurrencyConversionRPC = false
-)
-
 func (fe *frontendServer) getCurrencies(ctx context.Context) ([]string, error) {
 	curSvc, err := fe.currencySvcClient.
 		GetSupportedCurrencies(ctx, &pb.Empty{})
@@ -33,23 +34,132 @@ func (fe *frontendServer) getProducts(ctx context.Context) ([]*pb.Product, error
 	return resp.GetProducts(), err
 }
 
+func (fe *frontendServer) searchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	return fe.productCatalogSvcClient.SearchProducts(ctx, req)
+}
+
 func (fe *frontendServer) getProduct(ctx context.Context, id string) (*pb.Product, error) {
 	resp, err := fe.productCatalogSvcClient.
 		GetProduct(ctx, &pb.GetProductRequest{Id: id})
 	return resp, err
 }
 
+// getProductsByID looks up products in a single call. It returns the products
+// found keyed by ID, and the IDs the catalog doesn't have.
+func (fe *frontendServer) getProductsByID(ctx context.Context, ids []string) (map[string]*pb.Product, []string, error) {
+	resp, err := fe.productCatalogSvcClient.GetProducts(ctx, &pb.GetProductsRequest{Ids: ids})
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make(map[string]*pb.Product, len(resp.GetProducts()))
+	for _, p := range resp.GetProducts() {
+		out[p.GetId()] = p
+	}
+	return out, resp.GetMissingIds(), nil
+}
+
+// cartProduct is a product in the cart and its price in the user's currency.
+// product and price are nil for a product that is no longer in the catalog.
+type cartProduct struct {
+	item    *pb.CartItem
+	product *pb.Product
+	price   *pb.Money
+}
+
+// getCartProducts pairs the items in cart with their products and converts
+// every price to currency at once. The results are in the order of cart.
+func (fe *frontendServer) getCartProducts(ctx context.Context, cart []*pb.CartItem, products map[string]*pb.Product, currency string) ([]cartProduct, error) {
+	return fanout.Map(ctx, fe.fanoutLimit, cart, func(ctx context.Context, item *pb.CartItem) (cartProduct, error) {
+		p, ok := products[item.GetProductId()]
+		if !ok {
+			return cartProduct{item: item}, nil
+		}
+		price, err := fe.convertCurrency(ctx, p.GetPriceUsd(), currency)
+		if err != nil {
+			return cartProduct{}, errors.Wrapf(err, "could not convert currency for product #%s", item.GetProductId())
+		}
+		return cartProduct{item: item, product: p, price: price}, nil
+	})
+}
+
 func (fe *frontendServer) getCart(ctx context.Context, userID string) ([]*pb.CartItem, error) {
 	resp, err := fe.cartSvcClient.GetCart(ctx, &pb.GetCartRequest{UserId: userID})
 	return resp.GetItems(), err
 }
 
 func (fe *frontendServer) emptyCart(ctx context.Context, userID string) error {
+	defer fe.lockCart(userID)()
+	return fe.clearCart(ctx, userID)
+}
+
+func (fe *frontendServer) insertCart(ctx context.Context, userID, productID string, quantity int32) error {
+	defer fe.lockCart(userID)()
+	return fe.addCartItem(ctx, userID, productID, quantity)
+}
+
+// lockCart holds the lock of the user's cart until the returned function is
+// called. The cart service has no way to change a quantity, so setCartQuantity
+// rewrites the whole cart, and every change this frontend makes to a cart takes
+// the lock so that none lands in the middle of it. Users share one of
+// cartLocks by the hash of their ID.
+func (fe *frontendServer) lockCart(userID string) (unlock func()) {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	mu := &fe.cartLocks[h.Sum32()%uint32(len(fe.cartLocks))]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// setCartQuantity changes how many of productID are in the user's cart, which
+// holds cart, removing it at zero. The caller holds lockCart(userID) from
+// reading cart until this returns. The cart service can only add items or
+// empty the cart, so this empties it and adds the items back. If that fails
+// part way, cart is put back as it was, even if ctx has been canceled.
+func (fe *frontendServer) setCartQuantity(ctx context.Context, userID string, cart []*pb.CartItem, productID string, quantity int32) error {
+	items := make([]*pb.CartItem, 0, len(cart))
+	set := false
+	for _, it := range cart {
+		switch {
+		case it.GetProductId() != productID:
+			items = append(items, it)
+		case !set && quantity > 0:
+			items = append(items, &pb.CartItem{ProductId: productID, Quantity: quantity})
+			set = true
+		}
+	}
+	if err := fe.clearCart(ctx, userID); err != nil {
+		return err
+	}
+	for _, it := range items {
+		if err := fe.addCartItem(ctx, userID, it.GetProductId(), it.GetQuantity()); err != nil {
+			if rerr := fe.restoreCart(context.WithoutCancel(ctx), userID, cart); rerr != nil {
+				return errors.Wrapf(err, "%v, and restoring the cart failed", rerr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreCart replaces the user's cart with cart.
+func (fe *frontendServer) restoreCart(ctx context.Context, userID string, cart []*pb.CartItem) error {
+	if err := fe.clearCart(ctx, userID); err != nil {
+		return err
+	}
+	for _, it := range cart {
+		if err := fe.addCartItem(ctx, userID, it.GetProductId(), it.GetQuantity()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fe *frontendServer) clearCart(ctx context.Context, userID string) error {
 	_, err := fe.cartSvcClient.EmptyCart(ctx, &pb.EmptyCartRequest{UserId: userID})
 	return err
 }
 
-func (fe *frontendServer) insertCart(ctx context.Context, userID, productID string, quantity int32) error {
+func (fe *frontendServer) addCartItem(ctx context.Context, userID, productID string, quantity int32) error {
 	_, err := fe.cartSvcClient.AddItem(ctx, &pb.AddItemRequest{
 		UserId: userID,
 		Item: &pb.CartItem{
@@ -60,7 +170,7 @@ func (fe *frontendServer) insertCart(ctx context.Context, userID, productID stri
 }
 
 func (fe *frontendServer) convertCurrency(ctx context.Context, money *pb.Money, currency string) (*pb.Money, error) {
-	if avoidNoopCurrencyConversionRPC && money.GetCurrencyCode() == currency {
+	if money.GetCurrencyCode() == currency && flagEnabled(ctx, flagAvoidNoopConversion, "", false) {
 		return money, nil
 	}
 	return fe.currencySvcClient.
@@ -69,39 +179,124 @@ func (fe *frontendServer) convertCurrency(ctx context.Context, money *pb.Money,
 			ToCode: currency})
 }
 
-func (fe *frontendServer) getShippingQuote(ctx context.Context, items []*pb.CartItem, currency string) (*pb.Money, error) {
+// localizedPrices converts the prices of products to currency, all at once.
+func (fe *frontendServer) localizedPrices(ctx context.Context, products []*pb.Product, currency string) ([]*pb.Money, error) {
+	return fanout.Map(ctx, fe.fanoutLimit, products, func(ctx context.Context, p *pb.Product) (*pb.Money, error) {
+		price, err := fe.convertCurrency(ctx, p.GetPriceUsd(), currency)
+		return price, errors.Wrapf(err, "failed to do currency conversion for product %s", p.GetId())
+	})
+}
+
+// cartQuoteAddress is where the cart is quoted shipping to before the user
+// gives an address: the one the checkout form starts with.
+var cartQuoteAddress = &pb.Address{
+	StreetAddress: "1600 Amphitheatre Parkway",
+	City:          "Mountain View",
+	State:         "CA",
+	Country:       "United States",
+	ZipCode:       94043,
+}
+
+// shippingOption is a way the cart can be shipped, priced in the user's
+// currency.
+type shippingOption struct {
+	ID                string
+	Name              string
+	Carrier           string
+	Cost              *pb.Money
+	BusinessDays      int32
+	EstimatedDelivery string
+}
+
+// getShippingQuote quotes the ways of shipping the items to address, whose
+// products are looked up in products for the subtotal that can make shipping
+// free. It returns the options and the index of the one with ID optionID, or
+// of the first if there is none.
+func (fe *frontendServer) getShippingQuote(ctx context.Context, items []*pb.CartItem, products map[string]*pb.Product, address *pb.Address, currency, optionID string) ([]shippingOption, int, error) {
+	subtotal := pb.Money{CurrencyCode: "USD"}
+	for _, it := range items {
+		if p, ok := products[it.GetProductId()]; ok && p.GetPriceUsd() != nil {
+			subtotal = money.Must(money.Sum(subtotal, money.MultiplySlow(*p.GetPriceUsd(), uint32(it.GetQuantity()))))
+		}
+	}
 	quote, err := fe.shippingSvcClient.GetQuote(ctx,
 		&pb.GetQuoteRequest{
-			Address: nil,
-			Items:   items})
+			Address:     address,
+			Items:       items,
+			SubtotalUsd: &subtotal})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	quoted := quote.GetOptions()
+	if len(quoted) == 0 {
+		// a shipping service that only quotes a single price
+		quoted = []*pb.ShippingOption{{Name: "Standard", CostUsd: quote.GetCostUsd()}}
+	}
+	options, err := fanout.Map(ctx, fe.fanoutLimit, quoted, func(ctx context.Context, o *pb.ShippingOption) (shippingOption, error) {
+		cost, err := fe.convertCurrency(ctx, o.GetCostUsd(), currency)
+		if err != nil {
+			return shippingOption{}, errors.Wrapf(err, "failed to convert currency for shipping option %q", o.GetId())
+		}
+		return shippingOption{
+			ID:                o.GetId(),
+			Name:              o.GetName(),
+			Carrier:           o.GetCarrier(),
+			Cost:              cost,
+			BusinessDays:      o.GetBusinessDays(),
+			EstimatedDelivery: o.GetEstimatedDeliveryDate(),
+		}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, o := range options {
+		if o.ID == optionID {
+			return options, i, nil
+		}
 	}
-	localized, err := fe.convertCurrency(ctx, quote.GetCostUsd(), currency)
-	return localized, errors.Wrap(err, "failed to convert currency for shipping cost")
+	return options, 0, nil
 }
 
-func (fe *frontendServer) getRecommendations(ctx context.Context, userID string, productIDs []string) ([]*pb.Product, error) {
+func (fe *frontendServer) trackShipment(ctx context.Context, trackingID string) (*pb.TrackShipmentResponse, error) {
+	return fe.shippingSvcClient.TrackShipment(ctx, &pb.TrackShipmentRequest{TrackingId: trackingID})
+}
+
+func (fe *frontendServer) getRecommendationIDs(ctx context.Context, userID string, productIDs []string) ([]string, error) {
 	resp, err := fe.recommendationSvcClient.ListRecommendations(ctx,
 		&pb.ListRecommendationsRequest{UserId: userID, ProductIds: productIDs})
+	if err != nil {
+		return nil, err
+	}
 	span := apiTrace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("recommendations.num_products", len(resp.GetProductIds())))
+	return resp.GetProductIds(), nil
+}
+
+func (fe *frontendServer) getRecommendations(ctx context.Context, userID string, productIDs []string) ([]*pb.Product, error) {
+	ids, err := fe.getRecommendationIDs(ctx, userID, productIDs)
 	if err != nil {
 		return nil, err
 	}
-	lenProducts := len(resp.GetProductIds())
-	span.SetAttributes(attribute.Int("recommendations.num_products", lenProducts))
-	out := make([]*pb.Product, lenProducts)
-	for i, v := range resp.GetProductIds() {
-		p, err := fe.getProduct(ctx, v)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get recommended product info (#%s)", v)
-		}
-		out[i] = p
+	products, _, err := fe.getProductsByID(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get recommended product info")
 	}
-	if len(out) > 4 {
-		out = out[:4] // take only first four to fit the UI
+	return pickRecommendations(ids, products), nil
+}
+
+// pickRecommendations returns the first four of ids that are in products,
+// skipping any that are no longer in the catalog.
+func pickRecommendations(ids []string, products map[string]*pb.Product) []*pb.Product {
+	var out []*pb.Product
+	for _, id := range ids {
+		if p, ok := products[id]; ok {
+			out = append(out, p)
+		}
+		if len(out) == 4 {
+			break // take only first four to fit the UI
+		}
 	}
-	return out, err
+	return out
 }
 
 func (fe *frontendServer) getAd(ctx context.Context, ctxKeys []string) ([]*pb.Ad, error) {
@@ -111,7 +306,59 @@ func (fe *frontendServer) getAd(ctx context.Context, ctxKeys []string) ([]*pb.Ad
 	return resp.GetAds(), errors.Wrap(err, "failed to get ads")
 }
 
+func (fe *frontendServer) placeOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.OrderResult, error) {
+	resp, err := fe.checkoutSvcClient.PlaceOrder(ctx, req)
+	return resp.GetOrder(), err
+}
+
+func (fe *frontendServer) getOrder(ctx context.Context, userID, orderID string) (*pb.OrderResult, error) {
+	return fe.checkoutSvcClient.GetOrder(ctx, &pb.GetOrderRequest{UserId: userID, OrderId: orderID})
+}
+
+func (fe *frontendServer) listOrders(ctx context.Context, userID string) ([]*pb.OrderResult, error) {
+	resp, err := fe.checkoutSvcClient.ListOrdersByUser(ctx, &pb.ListOrdersByUserRequest{UserId: userID})
+	return resp.GetOrders(), err
+}
+
 func (fe *frontendServer) getCacheSize(ctx context.Context) (*pb.CacheSizeResponse, error) {
 	resp, err := fe.getCacheClient.GetCacheSize(ctx, &pb.Empty{})
 	return resp, errors.Wrap(err, "failed to get CacheTracker size")
 }
+
+func (fe *frontendServer) resetCache(ctx context.Context, targetSize int64) (*pb.CacheSizeResponse, error) {
+	resp, err := fe.checkoutSvcClient.ResetCache(ctx, &pb.ResetCacheRequest{TargetSize: targetSize})
+	return resp, errors.Wrap(err, "failed to reset cache")
+}
+
+func (fe *frontendServer) createProduct(ctx context.Context, p *pb.Product) (*pb.Product, error) {
+	resp, err := fe.productCatalogSvcClient.CreateProduct(admin.WithToken(ctx, fe.adminToken), &pb.CreateProductRequest{Product: p})
+	return resp, errors.Wrap(err, "failed to create product")
+}
+
+func (fe *frontendServer) updateProduct(ctx context.Context, p *pb.Product) (*pb.Product, error) {
+	resp, err := fe.productCatalogSvcClient.UpdateProduct(admin.WithToken(ctx, fe.adminToken), &pb.UpdateProductRequest{Product: p})
+	return resp, errors.Wrap(err, "failed to update product")
+}
+
+func (fe *frontendServer) deleteProduct(ctx context.Context, id string) error {
+	_, err := fe.productCatalogSvcClient.DeleteProduct(admin.WithToken(ctx, fe.adminToken), &pb.DeleteProductRequest{Id: id})
+	return errors.Wrap(err, "failed to delete product")
+}
+
+func (fe *frontendServer) setProductPrice(ctx context.Context, id string, price *pb.Money) (*pb.Product, error) {
+	resp, err := fe.productCatalogSvcClient.SetPrice(admin.WithToken(ctx, fe.adminToken), &pb.SetPriceRequest{Id: id, PriceUsd: price})
+	return resp, errors.Wrap(err, "failed to set price")
+}
+
+// getStock returns how many of each product can be bought, keyed by ID.
+func (fe *frontendServer) getStock(ctx context.Context, ids []string) (map[string]int32, error) {
+	resp, err := fe.inventorySvcClient.GetStock(ctx, &pb.GetStockRequest{ProductIds: ids})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stock levels")
+	}
+	out := make(map[string]int32, len(resp.GetLevels()))
+	for _, l := range resp.GetLevels() {
+		out[l.GetProductId()] = l.GetAvailable()
+	}
+	return out, nil
+}
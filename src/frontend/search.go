@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// searchPageSize fills four rows of the product grid.
+const searchPageSize = 12
+
+// searchSorts are the values of the sort parameter of /search.
+var searchSorts = []struct {
+	param string
+	label string
+	order pb.SearchSortOrder
+}{
+	{"relevance", "Relevance", pb.SearchSortOrder_SEARCH_SORT_ORDER_RELEVANCE},
+	{"price_asc", "Price: low to high", pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_ASCENDING},
+	{"price_desc", "Price: high to low", pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_DESCENDING},
+	{"name", "Name", pb.SearchSortOrder_SEARCH_SORT_ORDER_NAME},
+}
+
+// searchParams is a search as it is written in the query string of /search.
+// Prices are in USD, which the catalog filters on.
+type searchParams struct {
+	Query      string
+	Categories []string
+	MinPrice   string
+	MaxPrice   string
+	Sort       string
+	PageToken  string
+}
+
+func parseSearchParams(v url.Values) (searchParams, *pb.SearchProductsRequest, error) {
+	p := searchParams{
+		Query:      strings.TrimSpace(v.Get("q")),
+		Categories: v["category"],
+		MinPrice:   v.Get("min_price"),
+		MaxPrice:   v.Get("max_price"),
+		Sort:       v.Get("sort"),
+		PageToken:  v.Get("page_token"),
+	}
+	req := &pb.SearchProductsRequest{Query: p.Query, Categories: p.Categories, PageSize: searchPageSize, PageToken: p.PageToken}
+	var err error
+	if req.MinPrice, err = parseUSD(p.MinPrice); err != nil {
+		return p, nil, errors.Wrap(err, "invalid min_price")
+	}
+	if req.MaxPrice, err = parseUSD(p.MaxPrice); err != nil {
+		return p, nil, errors.Wrap(err, "invalid max_price")
+	}
+	if p.Sort != "" {
+		found := false
+		for _, s := range searchSorts {
+			if s.param == p.Sort {
+				req.Sort, found = s.order, true
+			}
+		}
+		if !found {
+			return p, nil, errors.Errorf("unknown sort %q", p.Sort)
+		}
+	}
+	return p, req, nil
+}
+
+// parseUSD parses a price such as "25" or "24.99", or returns nil for "".
+func parseUSD(s string) (*pb.Money, error) {
+	if s == "" {
+		return nil, nil
+	}
+	units, cents, ok := strings.Cut(s, ".")
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || u < 0 {
+		return nil, errors.Errorf("%q is not a price", s)
+	}
+	m := &pb.Money{CurrencyCode: "USD", Units: u}
+	if ok {
+		if len(cents) == 0 || len(cents) > 2 {
+			return nil, errors.Errorf("%q is not a price", s)
+		}
+		c, err := strconv.Atoi(cents + strings.Repeat("0", 2-len(cents)))
+		if err != nil || c < 0 {
+			return nil, errors.Errorf("%q is not a price", s)
+		}
+		m.Nanos = int32(c) * 1e7
+	}
+	return m, nil
+}
+
+// formatUSD is the inverse of parseUSD.
+func formatUSD(m *pb.Money) string {
+	if m.GetNanos() == 0 {
+		return strconv.FormatInt(m.GetUnits(), 10)
+	}
+	return fmt.Sprintf("%d.%02d", m.GetUnits(), m.GetNanos()/1e7)
+}
+
+// URL returns the /search URL of p, back on the first page.
+func (p searchParams) URL() string {
+	v := url.Values{}
+	if p.Query != "" {
+		v.Set("q", p.Query)
+	}
+	for _, c := range p.Categories {
+		v.Add("category", c)
+	}
+	if p.MinPrice != "" {
+		v.Set("min_price", p.MinPrice)
+	}
+	if p.MaxPrice != "" {
+		v.Set("max_price", p.MaxPrice)
+	}
+	if p.Sort != "" {
+		v.Set("sort", p.Sort)
+	}
+	if p.PageToken != "" {
+		v.Set("page_token", p.PageToken)
+	}
+	return "/search?" + v.Encode()
+}
+
+func (p searchParams) hasCategory(c string) bool {
+	for _, have := range p.Categories {
+		if strings.EqualFold(have, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCategory adds c to the categories of p, or removes it if it is there.
+func (p searchParams) withCategory(c string) searchParams {
+	var out []string
+	for _, have := range p.Categories {
+		if !strings.EqualFold(have, c) {
+			out = append(out, have)
+		}
+	}
+	if !p.hasCategory(c) {
+		out = append(out, c)
+	}
+	p.Categories, p.PageToken = out, ""
+	return p
+}
+
+type searchFacetView struct {
+	Label    string
+	Count    int32
+	Selected bool
+	URL      string
+}
+
+// searchFacets describes the facets of res as links that narrow the search
+// to them, or widen it again if they are already selected.
+func searchFacets(p searchParams, res *pb.SearchProductsResponse) (categories, prices []searchFacetView) {
+	for _, f := range res.GetCategoryFacets() {
+		categories = append(categories, searchFacetView{
+			Label:    f.GetCategory(),
+			Count:    f.GetCount(),
+			Selected: p.hasCategory(f.GetCategory()),
+			URL:      p.withCategory(f.GetCategory()).URL(),
+		})
+	}
+	for _, f := range res.GetPriceFacets() {
+		narrowed := p
+		narrowed.PageToken = ""
+		narrowed.MinPrice = formatUSD(f.GetMin())
+		narrowed.MaxPrice = ""
+		label := renderMoney(*f.GetMin()) + " and up"
+		if f.GetMax() != nil {
+			// the facet's max is exclusive, the filter's inclusive
+			max := f.GetMax().GetUnits()*100 + int64(f.GetMax().GetNanos()/1e7) - 1
+			narrowed.MaxPrice = formatUSD(&pb.Money{Units: max / 100, Nanos: int32(max%100) * 1e7})
+			label = renderMoney(*f.GetMin()) + " to " + renderMoney(*f.GetMax())
+		}
+		view := searchFacetView{Label: label, Count: f.GetCount(), URL: narrowed.URL()}
+		if narrowed.MinPrice == p.MinPrice && narrowed.MaxPrice == p.MaxPrice {
+			widened := narrowed
+			widened.MinPrice, widened.MaxPrice = "", ""
+			view.Selected, view.URL = true, widened.URL()
+		}
+		prices = append(prices, view)
+	}
+	return categories, prices
+}
+
+func (fe *frontendServer) searchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	log := r.Context().Value(ctxKeyLog{}).(logrus.FieldLogger)
+
+	params, req, err := parseSearchParams(r.URL.Query())
+	if err != nil {
+		renderHTTPError(log, r, w, err, http.StatusBadRequest)
+		return
+	}
+	log.WithField("query", params.Query).Debug("search")
+	span.SetAttributes(
+		attribute.String("app.search.query", params.Query),
+		attribute.StringSlice("app.search.categories", params.Categories),
+	)
+
+	res, err := fe.searchProducts(ctx, req)
+	if status.Code(err) == grpccodes.InvalidArgument {
+		renderHTTPError(log, r, w, errors.Wrap(err, "invalid search"), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not search products"), http.StatusInternalServerError)
+		return
+	}
+	span.SetAttributes(attribute.Int("app.search.total", int(res.GetTotalSize())))
+
+	prices, err := fe.localizedPrices(ctx, res.GetResults(), currentCurrency(r))
+	if err != nil {
+		renderHTTPError(log, r, w, err, http.StatusInternalServerError)
+		return
+	}
+	type productView struct {
+		Item  *pb.Product
+		Price *pb.Money
+	}
+	products := make([]productView, len(res.GetResults()))
+	for i, p := range res.GetResults() {
+		products[i] = productView{p, prices[i]}
+	}
+	currencies, err := fe.getCurrencies(ctx)
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve currencies"), http.StatusInternalServerError)
+		return
+	}
+	cart, err := fe.getCart(ctx, sessionID(r))
+	if err != nil {
+		renderHTTPError(log, r, w, errors.Wrap(err, "could not retrieve cart"), http.StatusInternalServerError)
+		return
+	}
+
+	type sortView struct {
+		Value    string
+		Label    string
+		Selected bool
+	}
+	sorts := make([]sortView, len(searchSorts))
+	for i, s := range searchSorts {
+		sorts[i] = sortView{s.param, s.label, s.order == req.GetSort()}
+	}
+	categoryFacets, priceFacets := searchFacets(params, res)
+	var nextPage, firstPage string
+	if res.GetNextPageToken() != "" {
+		next := params
+		next.PageToken = res.GetNextPageToken()
+		nextPage = next.URL()
+	}
+	if params.PageToken != "" {
+		first := params
+		first.PageToken = ""
+		firstPage = first.URL()
+	}
+
+	if err := templates.ExecuteTemplate(w, "search", map[string]interface{}{
+		"trace_id":        span.SpanContext().TraceID().String(),
+		"span_id":         span.SpanContext().SpanID().String(),
+		"session_id":      sessionID(r),
+		"request_id":      r.Context().Value(ctxKeyRequestID{}),
+		"user_currency":   currentCurrency(r),
+		"show_currency":   true,
+		"currencies":      currencies,
+		"cart_size":       cartSize(cart),
+		"search":          params,
+		"search_query":    params.Query,
+		"products":        products,
+		"total":           res.GetTotalSize(),
+		"category_facets": categoryFacets,
+		"price_facets":    priceFacets,
+		"sorts":           sorts,
+		"next_page":       nextPage,
+		"first_page":      firstPage,
+		"platform_css":    plat.css,
+		"platform_name":   plat.provider,
+	}); err != nil {
+		log.Error(err)
+	}
+}
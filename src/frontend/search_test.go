@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+)
+
+func TestParseSearchParams(t *testing.T) {
+	v, _ := url.ParseQuery("q=+sun+&category=accessories&category=kitchen&min_price=10&max_price=24.5&sort=price_desc")
+	p, req, err := parseSearchParams(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Query != "sun" || len(req.Categories) != 2 || req.PageSize != searchPageSize {
+		t.Errorf("got request %v", req)
+	}
+	if req.MinPrice.GetUnits() != 10 || req.MaxPrice.GetUnits() != 24 || req.MaxPrice.GetNanos() != 500000000 {
+		t.Errorf("got prices %v and %v", req.MinPrice, req.MaxPrice)
+	}
+	if req.Sort != pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_DESCENDING {
+		t.Errorf("got sort %v", req.Sort)
+	}
+	if got := p.URL(); got != "/search?category=accessories&category=kitchen&max_price=24.5&min_price=10&q=sun&sort=price_desc" {
+		t.Errorf("got URL %s", got)
+	}
+
+	for _, bad := range []string{"min_price=-1", "max_price=1.234", "min_price=ten", "max_price=3.", "sort=random"} {
+		v, _ := url.ParseQuery(bad)
+		if _, _, err := parseSearchParams(v); err == nil {
+			t.Errorf("%s: no error", bad)
+		}
+	}
+}
+
+func TestSearchFacets(t *testing.T) {
+	p := searchParams{Query: "mug", Categories: []string{"kitchen"}, PageToken: "page-2"}
+	res := &pb.SearchProductsResponse{
+		CategoryFacets: []*pb.CategoryFacet{{Category: "kitchen", Count: 2}, {Category: "home", Count: 1}},
+		PriceFacets: []*pb.PriceFacet{
+			{Min: &pb.Money{CurrencyCode: "USD"}, Max: &pb.Money{CurrencyCode: "USD", Units: 25}, Count: 2},
+			{Min: &pb.Money{CurrencyCode: "USD", Units: 100}, Count: 1},
+		},
+	}
+	categories, prices := searchFacets(p, res)
+	if !categories[0].Selected || categories[0].URL != "/search?q=mug" {
+		t.Errorf("a selected category should link to the search without it, got %+v", categories[0])
+	}
+	if categories[1].Selected || categories[1].URL != "/search?category=kitchen&category=home&q=mug" {
+		t.Errorf("got %+v", categories[1])
+	}
+	if prices[0].URL != "/search?category=kitchen&max_price=24.99&min_price=0&q=mug" {
+		t.Errorf("got %+v", prices[0])
+	}
+	if prices[1].URL != "/search?category=kitchen&min_price=100&q=mug" || prices[1].Label != "USD 100.00 and up" {
+		t.Errorf("got %+v", prices[1])
+	}
+
+	p.MinPrice = "100"
+	if _, prices := searchFacets(p, res); !prices[1].Selected || prices[1].URL != "/search?category=kitchen&q=mug" {
+		t.Errorf("a selected price should link to the search without it, got %+v", prices[1])
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	shop := newFakeShop()
+	r := mux.NewRouter()
+	r.HandleFunc("/", shop.server().homeHandler)
+	r.HandleFunc("/search", shop.server().searchHandler)
+	h := withTestSession("user-1", r)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`aria-label="Search products" value="a"`,
+		`href="/search?page_token=page-2&amp;q=a"`,
+		`href="/search?category=accessories&amp;q=a"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("the page doesn't contain %s", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?page_token=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got %d for a bad page token, want 400", rec.Code)
+	}
+
+	// other pages have an empty search box
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), `aria-label="Search products" value=""`) {
+		t.Error("the home page search box isn't empty")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestCartShippingOptions(t *testing.T) {
+	shop := newFakeShop()
+	fe := shop.server()
+	r := mux.NewRouter()
+	r.HandleFunc("/cart", fe.viewCartHandler).Methods(http.MethodGet)
+	r.HandleFunc("/cart/checkout", fe.placeOrderHandler).Methods(http.MethodPost)
+	fe.apiRoutes(r)
+	h := withTestSession("user-1", r)
+	if code, _ := apiRequest(t, h, http.MethodPost, "/api/v1/cart/items", `{"product_id": "OLJCESPC7Z", "quantity": 2}`); code != http.StatusOK {
+		t.Fatalf("got %d adding to the cart", code)
+	}
+
+	// 39.98 of sunglasses
+	for path, want := range map[string]struct {
+		option string
+		total  float64
+	}{
+		"/api/v1/cart":                          {"standard", 44},
+		"/api/v1/cart?shipping_option=express":  {"express", 51},
+		"/api/v1/cart?shipping_option=teleport": {"standard", 44},
+	} {
+		_, got := apiRequest(t, h, http.MethodGet, path, "")
+		options, _ := got["shipping_options"].([]interface{})
+		if len(options) != 2 || got["shipping_option_id"] != want.option || got["total"].(map[string]interface{})["units"] != want.total {
+			t.Errorf("GET %s: got %v, want two options and a total of %v.98 with %s shipping", path, got, want.total, want.option)
+		}
+	}
+
+	body := getPage(t, h, "/cart?shipping_option=express")
+	if !strings.Contains(body, `<option value="express" selected="selected">`) ||
+		!strings.Contains(body, `name="shipping_option" value="express"`) ||
+		!strings.Contains(body, "2026-10-20") {
+		t.Error("the cart page doesn't show express shipping as picked")
+	}
+
+	code, got := apiRequest(t, h, http.MethodPost, "/api/v1/checkout", `{"email": "a@example.com", "credit_card": {"number": "1"}, "shipping_option_id": "teleport"}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("got %d %v for an unknown shipping option, want 400", code, got)
+	}
+	code, got = apiRequest(t, h, http.MethodPost, "/api/v1/checkout", `{"email": "a@example.com", "credit_card": {"number": "1"}, "shipping_option_id": "express"}`)
+	if code != http.StatusCreated || got["shipping_cost"].(map[string]interface{})["units"] != 12.0 {
+		t.Errorf("got %d %v, want an order shipped express", code, got)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/cart/checkout", strings.NewReader(url.Values{"email": {"a@example.com"}, "shipping_option": {"express"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || shop.placed[len(shop.placed)-1].GetShippingOptionId() != "express" {
+		t.Errorf("got %d, want the checkout form's shipping option to be ordered", rec.Code)
+	}
+
+	// express was picked on the cart page, but isn't offered abroad
+	if code, _ := apiRequest(t, h, http.MethodPost, "/api/v1/cart/items", `{"product_id": "OLJCESPC7Z", "quantity": 1}`); code != http.StatusOK {
+		t.Fatalf("got %d adding to the cart", code)
+	}
+	placed := len(shop.placed)
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/cart/checkout", strings.NewReader(url.Values{"email": {"a@example.com"}, "street_address": {"1 Main St"}, "city": {"Toronto"}, "country": {"Canada"}, "shipping_option": {"express"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || len(shop.placed) != placed {
+		t.Fatalf("got %d, want the order to Canada rejected", rec.Code)
+	}
+	body = rec.Body.String()
+	if !strings.Contains(body, `isn&#39;t available for this order`) || strings.Contains(body, `<option value="express"`) ||
+		!strings.Contains(body, `name="country" value="Canada"`) {
+		t.Error("the rejected checkout doesn't show the cart quoted to Canada with the reason")
+	}
+	if shop.quoted.GetAddress().GetCountry() != "Canada" {
+		t.Errorf("shipping was quoted to %v, want the checkout address", shop.quoted.GetAddress())
+	}
+}
+
+func TestTrackShipmentPage(t *testing.T) {
+	shop := newFakeShop()
+	fe := shop.server()
+	r := mux.NewRouter()
+	r.HandleFunc("/track/{id}", fe.trackShipmentHandler)
+	h := withTestSession("user-1", r)
+
+	body := getPage(t, h, "/track/TRACK-1")
+	for _, want := range []string{"In transit", "2026-10-23", "Parcel Post", "Picked up by Parcel Post"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("the tracking page doesn't show %q", want)
+		}
+	}
+	if strings.Index(body, "Departed the origin facility") > strings.Index(body, "Shipping label created") {
+		t.Error("the tracking page doesn't show the newest event first")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/track/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got %d for an unknown tracking ID, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// lowStockThreshold is the stock at or below which pages say how many are left.
+const lowStockThreshold = 5
+
+// stockLevel is what the pages show about a product's stock. The shop keeps
+// working without the inventory service, showing nothing about stock.
+type stockLevel struct {
+	Known     bool
+	Available int32
+}
+
+func (s stockLevel) OutOfStock() bool { return s.Known && s.Available <= 0 }
+
+func (s stockLevel) Low() bool {
+	return s.Known && s.Available > 0 && s.Available <= lowStockThreshold
+}
+
+// stockLevels looks up the stock of the products, logging rather than
+// failing when the inventory can't be reached.
+func (fe *frontendServer) stockLevels(ctx context.Context, log logrus.FieldLogger, ids []string) map[string]stockLevel {
+	out := make(map[string]stockLevel, len(ids))
+	levels, err := fe.getStock(ctx, ids)
+	if err != nil {
+		log.WithError(err).Warn("showing products without stock levels")
+		return out
+	}
+	for id, n := range levels {
+		out[id] = stockLevel{Known: true, Available: n}
+	}
+	return out
+}
+
+// outOfStockError is returned when adding more of a product to the cart than
+// is in stock.
+type outOfStockError struct {
+	productID string
+	available int32
+}
+
+func (e *outOfStockError) Error() string {
+	if e.available <= 0 {
+		return fmt.Sprintf("product %s is out of stock", e.productID)
+	}
+	return fmt.Sprintf("only %d of product %s left", e.available, e.productID)
+}
+
+// checkStock checks there is enough of a product in stock to add quantity
+// more to the user's cart. Orders still reserve their stock at checkout, this
+// only catches carts that could never be bought.
+func (fe *frontendServer) checkStock(ctx context.Context, log logrus.FieldLogger, userID, productID string, quantity int32) error {
+	level := fe.stockLevels(ctx, log, []string{productID})[productID]
+	if !level.Known {
+		return nil
+	}
+	cart, err := fe.getCart(ctx, userID)
+	if err != nil {
+		return err
+	}
+	inCart := int64(0)
+	for _, it := range cart {
+		if it.GetProductId() == productID {
+			inCart += int64(it.GetQuantity())
+		}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("app.stock.available", int(level.Available)))
+	if inCart+int64(quantity) > int64(level.Available) {
+		return &outOfStockError{productID: productID, available: level.Available}
+	}
+	return nil
+}
+
+// stockErrorStatus is the HTTP status for an error from checkStock: a
+// conflict when there isn't enough stock, otherwise the given status.
+func stockErrorStatus(err error, otherwise int) int {
+	var short *outOfStockError
+	if errors.As(err, &short) {
+		return http.StatusConflict
+	}
+	return otherwise
+}
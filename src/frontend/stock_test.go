@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pb "github.com/honeycombio/microservices-demo/src/frontend/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// downInventory fails every call, like an inventory service that isn't there.
+type downInventory struct {
+	pb.InventoryServiceClient
+}
+
+func (downInventory) GetStock(context.Context, *pb.GetStockRequest, ...grpc.CallOption) (*pb.GetStockResponse, error) {
+	return nil, status.Error(codes.Unavailable, "connection refused")
+}
+
+// newStockShop serves the home and product pages and adding to the cart for
+// "user-1".
+func newStockShop(t *testing.T) (*fakeShop, *frontendServer, http.Handler) {
+	t.Helper()
+	shop := newFakeShop()
+	fe := shop.server()
+	r, err := newFaultRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Modify("product-timeout", func(s *faults.Scenario) { s.Enabled = false }); err != nil {
+		t.Fatal(err)
+	}
+	fe.faults = r
+	router := mux.NewRouter()
+	router.HandleFunc("/", fe.homeHandler)
+	router.HandleFunc("/product/{id}", fe.productHandler)
+	router.HandleFunc("/cart", fe.addToCartHandler).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/cart/items", fe.apiAddToCartHandler).Methods(http.MethodPost)
+	return shop, fe, withTestSession("user-1", router)
+}
+
+func getPage(t *testing.T, h http.Handler, path string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s returned %d: %s", path, rec.Code, rec.Body.String())
+	}
+	return rec.Body.String()
+}
+
+func addToCart(h http.Handler, id, quantity string) int {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/cart", strings.NewReader(url.Values{"product_id": {id}, "quantity": {quantity}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestPagesShowStock(t *testing.T) {
+	shop, _, h := newStockShop(t)
+	if body := getPage(t, h, "/"); !strings.Contains(body, "Only 3 left") || strings.Contains(body, "Only 100 left") {
+		t.Error("the home page doesn't show the low stock of the tank top only")
+	}
+	body := getPage(t, h, "/product/66VCHSJNUP")
+	if !strings.Contains(body, "Only 3 left") || strings.Contains(body, "disabled") {
+		t.Error("the product page doesn't show the low stock")
+	}
+	if body := getPage(t, h, "/product/OLJCESPC7Z"); strings.Contains(body, "left") || strings.Contains(body, "Out of stock") {
+		t.Error("the product page shows the stock of a product with plenty")
+	}
+
+	shop.stock["66VCHSJNUP"] = 0
+	if body := getPage(t, h, "/"); !strings.Contains(body, "Out of stock") {
+		t.Error("the home page doesn't show the product is out of stock")
+	}
+	body = getPage(t, h, "/product/66VCHSJNUP")
+	if !strings.Contains(body, "Out of stock") || !strings.Contains(body, "disabled") {
+		t.Error("the product page can add an out of stock product to the cart")
+	}
+}
+
+func TestAddToCartChecksStock(t *testing.T) {
+	shop, _, h := newStockShop(t)
+	if code := addToCart(h, "66VCHSJNUP", "2"); code != http.StatusFound {
+		t.Fatalf("adding 2 of 3 returned %d", code)
+	}
+	// with 2 in the cart, 2 more is too many
+	if code := addToCart(h, "66VCHSJNUP", "2"); code != http.StatusConflict {
+		t.Errorf("adding 2 more returned %d, want 409", code)
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items", strings.NewReader(`{"product_id": "66VCHSJNUP", "quantity": 2}`))
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "only 3 of product 66VCHSJNUP left") {
+		t.Errorf("the API returned %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	if code := addToCart(h, "66VCHSJNUP", "1"); code != http.StatusFound {
+		t.Errorf("adding the last one returned %d", code)
+	}
+	if got := shop.carts["user-1"]; len(got) != 2 || got[0].Quantity+got[1].Quantity != 3 {
+		t.Errorf("cart is %v, want 3 tank tops", got)
+	}
+}
+
+func TestShopWorksWithoutInventory(t *testing.T) {
+	_, fe, h := newStockShop(t)
+	fe.inventorySvcClient = downInventory{}
+	for _, path := range []string{"/", "/product/66VCHSJNUP"} {
+		if body := getPage(t, h, path); strings.Contains(body, "left") || strings.Contains(body, "Out of stock") {
+			t.Errorf("GET %s shows stock levels it doesn't know", path)
+		}
+	}
+	if code := addToCart(h, "66VCHSJNUP", "10"); code != http.StatusFound {
+		t.Errorf("adding to the cart returned %d, want it to work without stock levels", code)
+	}
+}
@@ -0,0 +1,188 @@
+// Package admin holds the demo settings each service lets presenters change
+// at runtime, and the token that guards them.
+//
+// A service describes its settings as a map of Setting. The frontend serves
+// them over HTTP on /admin/settings, and each Go backend serves its own over
+// the gRPC AdminService, which the frontend calls with the same admin token.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Setting is one demo setting that can be read and changed at runtime.
+type Setting struct {
+	Get func() string
+	// Parse checks v without changing anything, and returns a function that
+	// changes the setting to it.
+	Parse func(v string) (apply func(), err error)
+}
+
+// Settings is a service's demo settings. It is safe for concurrent use.
+type Settings struct {
+	lock     sync.Mutex
+	settings map[string]Setting
+}
+
+// NewSettings returns the named settings.
+func NewSettings(settings map[string]Setting) *Settings {
+	return &Settings{settings: settings}
+}
+
+// Current returns the value of every setting.
+func (s *Settings) Current() map[string]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.current()
+}
+
+func (s *Settings) current() map[string]string {
+	out := make(map[string]string, len(s.settings))
+	for name, setting := range s.settings {
+		out[name] = setting.Get()
+	}
+	return out
+}
+
+// Update changes the given settings and returns the value of every setting
+// afterwards. Every value is checked before any is applied, so an unknown
+// name or invalid value leaves all the settings as they were. Each change is
+// logged and added to the span in ctx as a "setting changed" event.
+func (s *Settings) Update(ctx context.Context, log logrus.FieldLogger, values map[string]string) (map[string]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if _, ok := s.settings[name]; !ok {
+			return nil, fmt.Errorf("unknown setting %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	apply := make([]func(), len(names))
+	for i, name := range names {
+		var err error
+		if apply[i], err = s.settings[name].Parse(values[name]); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+
+	span := trace.SpanFromContext(ctx)
+	for i, name := range names {
+		old := s.settings[name].Get()
+		apply[i]()
+		value := s.settings[name].Get()
+		log.WithFields(logrus.Fields{
+			"setting":   name,
+			"old_value": old,
+			"new_value": value,
+		}).Info("admin setting changed")
+		span.AddEvent("setting changed", trace.WithAttributes(
+			attribute.String("app.setting.name", name),
+			attribute.String("app.setting.old_value", old),
+			attribute.String("app.setting.new_value", value),
+		))
+	}
+	return s.current(), nil
+}
+
+// Bool is a Setting for a flag.
+func Bool(get func() bool, set func(bool)) Setting {
+	return Setting{
+		Get: func() string { return strconv.FormatBool(get()) },
+		Parse: func(v string) (func(), error) {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, err
+			}
+			return func() { set(b) }, nil
+		},
+	}
+}
+
+// FaultSettings exposes whether each fault scenario in r is enabled and how
+// often it activates, as fault.<name>.enabled and fault.<name>.probability.
+func FaultSettings(r *faults.Registry) map[string]Setting {
+	settings := make(map[string]Setting)
+	for _, s := range r.Scenarios() {
+		name := s.Name
+		settings["fault."+name+".enabled"] = Bool(
+			func() bool {
+				s, _ := r.Scenario(name)
+				return s.Enabled
+			},
+			func(enabled bool) { modifyScenario(r, name, func(s *faults.Scenario) { s.Enabled = enabled }) },
+		)
+		settings["fault."+name+".probability"] = Setting{
+			Get: func() string {
+				s, _ := r.Scenario(name)
+				return strconv.FormatFloat(s.Probability, 'g', -1, 64)
+			},
+			Parse: func(v string) (func(), error) {
+				p, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					return nil, err
+				}
+				return ScenarioChange(r, name, func(s *faults.Scenario) { s.Probability = p })
+			},
+		}
+	}
+	return settings
+}
+
+// ScenarioChange checks that modify leaves the named scenario valid, and
+// returns a function that makes the change.
+func ScenarioChange(r *faults.Registry, name string, modify func(*faults.Scenario)) (func(), error) {
+	s, ok := r.Scenario(name)
+	if !ok {
+		return nil, fmt.Errorf("no scenario named %q", name)
+	}
+	modify(&s)
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return func() { modifyScenario(r, name, modify) }, nil
+}
+
+// modifyScenario makes a change that has already been checked. It can only
+// fail if the scenario was removed since, which leaves nothing to change.
+func modifyScenario(r *faults.Registry, name string, modify func(*faults.Scenario)) {
+	_ = r.Modify(name, modify)
+}
+
+// WithToken adds the admin token to the metadata of outgoing gRPC calls.
+func WithToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// Authorize checks that an incoming gRPC call carries token as a bearer token.
+// With no token set the admin API is disabled and every call is refused.
+func Authorize(ctx context.Context, token string) error {
+	if token == "" {
+		return status.Error(codes.PermissionDenied, "the admin API is disabled, set ADMIN_TOKEN to enable it")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		got, ok := strings.CutPrefix(v, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid admin token")
+}
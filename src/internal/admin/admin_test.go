@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/honeycombio/microservices-demo/src/internal/faults"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func quietLogger() logrus.FieldLogger {
+	l := logrus.New()
+	l.Out = io.Discard
+	return l
+}
+
+func intSetting(v *int) Setting {
+	return Setting{
+		Get: func() string { return strconv.Itoa(*v) },
+		Parse: func(s string) (func(), error) {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, err
+			}
+			if n < 0 {
+				return nil, errors.New("can't be negative")
+			}
+			return func() { *v = n }, nil
+		},
+	}
+}
+
+func TestUpdateIsAllOrNothing(t *testing.T) {
+	a, b := 1, 2
+	s := NewSettings(map[string]Setting{"a": intSetting(&a), "b": intSetting(&b)})
+	ctx := context.Background()
+
+	got, err := s.Update(ctx, quietLogger(), map[string]string{"a": "10", "b": "20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["a"] != "10" || got["b"] != "20" {
+		t.Errorf("got %v, want both settings changed", got)
+	}
+
+	for _, bad := range []map[string]string{
+		{"a": "30", "b": "-1"},
+		{"a": "30", "b": "lots"},
+		{"a": "30", "c": "1"},
+	} {
+		if _, err := s.Update(ctx, quietLogger(), bad); err == nil {
+			t.Errorf("%v: accepted", bad)
+		}
+		if a != 10 || b != 20 {
+			t.Errorf("%v: rejected update changed the settings to %d and %d", bad, a, b)
+		}
+	}
+}
+
+func TestFaultSettings(t *testing.T) {
+	r, err := faults.NewRegistry([]faults.Scenario{{Name: "boom", Kind: faults.KindError, Point: "p", Enabled: true, Probability: 1, Message: "boom"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSettings(FaultSettings(r))
+	ctx := context.Background()
+
+	if _, err := s.Update(ctx, quietLogger(), map[string]string{"fault.boom.enabled": "false", "fault.boom.probability": "2"}); err == nil {
+		t.Error("a probability of 2 was accepted")
+	}
+	if sc, _ := r.Scenario("boom"); !sc.Enabled {
+		t.Error("a rejected update disabled the scenario")
+	}
+	if _, err := s.Update(ctx, quietLogger(), map[string]string{"fault.boom.enabled": "false", "fault.boom.probability": "0.5"}); err != nil {
+		t.Fatal(err)
+	}
+	if sc, _ := r.Scenario("boom"); sc.Enabled || sc.Probability != 0.5 {
+		t.Errorf("got %+v, want boom disabled with probability 0.5", sc)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	incoming := func(token string) context.Context {
+		out, _ := metadata.FromOutgoingContext(WithToken(context.Background(), token))
+		return metadata.NewIncomingContext(context.Background(), out)
+	}
+	if err := Authorize(incoming("secret"), "secret"); err != nil {
+		t.Errorf("the right token was refused: %v", err)
+	}
+	if err := Authorize(incoming("wrong"), "secret"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got %v for the wrong token, want Unauthenticated", err)
+	}
+	if err := Authorize(context.Background(), "secret"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got %v without a token, want Unauthenticated", err)
+	}
+	if err := Authorize(incoming(""), ""); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v with the admin API disabled, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Package fanout calls a function for every element of a slice in parallel.
+//
+// Handlers that look up a product, price or quote per cart item use Map to
+// make those calls at the same time instead of one after the other, so the
+// latency of a page grows with the slowest call rather than with the size of
+// the cart.
+package fanout
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultLimit is the number of calls Map runs at once when given a limit
+// below 1. It keeps a large cart from opening a stream per item to a backend.
+const DefaultLimit = 8
+
+// Map calls fn for every element of in, running at most limit calls at once,
+// and returns the results in the order of in. The first call to fail cancels
+// the context of the others, and calls that haven't started yet are skipped;
+// Map then returns that first error and no results.
+func Map[T, R any](ctx context.Context, limit int, in []T, fn func(ctx context.Context, v T) (R, error)) ([]R, error) {
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		out      = make([]R, len(in))
+		sem      = make(chan struct{}, limit)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i, v := range in {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// a call failed, or the caller gave up
+			fail(ctx.Err())
+			break
+		}
+		wg.Add(1)
+		go func(i int, v T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r, err := fn(ctx, v)
+			if err != nil {
+				fail(err)
+				return
+			}
+			out[i] = r
+		}(i, v)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
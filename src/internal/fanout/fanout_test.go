@@ -0,0 +1,121 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapKeepsOrder(t *testing.T) {
+	in := []int{5, 1, 4, 2, 3}
+	out, err := Map(context.Background(), 2, in, func(_ context.Context, v int) (string, error) {
+		// finish in a different order than started
+		time.Sleep(time.Duration(v) * time.Millisecond)
+		return fmt.Sprint(v * 10), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(out) != "[50 10 40 20 30]" {
+		t.Errorf("got %v, want [50 10 40 20 30]", out)
+	}
+}
+
+func TestMapLimitsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	in := make([]int, 20)
+	_, err := Map(context.Background(), 3, in, func(_ context.Context, _ int) (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := peak.Load(); got != 3 {
+		t.Errorf("got %d calls at once, want 3", got)
+	}
+}
+
+func TestMapCancelsOnFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	var started, cancelled atomic.Int32
+	in := make([]int, 50)
+	for i := range in {
+		in[i] = i
+	}
+	out, err := Map(context.Background(), 4, in, func(ctx context.Context, v int) (int, error) {
+		started.Add(1)
+		if v == 1 {
+			return 0, boom
+		}
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return v, nil
+		}
+	})
+	if !errors.Is(err, boom) || out != nil {
+		t.Fatalf("got %v, %v, want the first error and no results", out, err)
+	}
+	if got := started.Load(); got > 8 {
+		t.Errorf("started %d calls after the failure, want the rest skipped", got)
+	}
+	if cancelled.Load() == 0 {
+		t.Error("running calls were not cancelled")
+	}
+}
+
+func TestMapHonorsCallerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls atomic.Int32
+	_, err := Map(ctx, 1, []int{1, 2, 3}, func(context.Context, int) (int, error) {
+		calls.Add(1)
+		return 0, nil
+	})
+	if !errors.Is(err, context.Canceled) || calls.Load() != 0 {
+		t.Errorf("got %v after %d calls, want context.Canceled before any", err, calls.Load())
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	out, err := Map(context.Background(), 0, nil, func(context.Context, int) (int, error) {
+		t.Fatal("called for no input")
+		return 0, nil
+	})
+	if err != nil || len(out) != 0 {
+		t.Errorf("got %v, %v", out, err)
+	}
+}
+
+// BenchmarkMap shows the latency of ten 2ms backend calls made one at a time
+// and through Map.
+func BenchmarkMap(b *testing.B) {
+	call := func(context.Context, int) (int, error) {
+		time.Sleep(2 * time.Millisecond)
+		return 0, nil
+	}
+	in := make([]int, 10)
+	for _, limit := range []int{1, 4, DefaultLimit, 10} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Map(context.Background(), limit, in, call); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
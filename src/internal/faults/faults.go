@@ -0,0 +1,427 @@
+// Package faults provides named, configurable failure scenarios for demos.
+//
+// Handlers call Registry.Inject at a named injection point, and every enabled
+// scenario configured for that point decides whether it activates for the
+// request. Scenarios are described in JSON, so new demo stories can be added
+// to a config file without editing handler code. Each activation is recorded
+// on the active span, which makes a demo reproducible from its traces.
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Kind identifies how a scenario misbehaves.
+type Kind string
+
+const (
+	// KindLatency delays the request.
+	KindLatency Kind = "latency"
+	// KindError fails the request with the scenario's message.
+	KindError Kind = "error"
+	// KindLeak retains memory in a registered LeakSink on every request.
+	KindLeak Kind = "leak"
+	// KindNPlusOne issues a burst of mock database calls that grows with load.
+	KindNPlusOne Kind = "n_plus_one"
+)
+
+// Duration is a time.Duration that reads and writes JSON as a string such as "250ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Targeting activates a scenario with a different probability for specific users.
+type Targeting struct {
+	Users       []string `json:"users"`
+	Probability float64  `json:"probability"`
+}
+
+// Scenario is the configuration of one named fault.
+type Scenario struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	// Point is the injection point the scenario applies to, e.g. "checkout.PlaceOrder".
+	Point   string `json:"point"`
+	Enabled bool   `json:"enabled"`
+	// Probability that the scenario activates when its point is reached, from
+	// 0 to 1. It defaults to 1 when omitted from the config.
+	Probability float64 `json:"probability"`
+	// Target, when set, replaces Probability for the listed users.
+	Target *Targeting `json:"target,omitempty"`
+
+	// latency
+	MinLatency Duration `json:"min_latency,omitempty"`
+	MaxLatency Duration `json:"max_latency,omitempty"`
+
+	// error
+	Message string `json:"message,omitempty"`
+
+	// leak
+	Sink string `json:"sink,omitempty"`
+	// While the sink is below Threshold, each request leaks between Base and
+	// Base+Jitter entries. Past Threshold the growth rises steadily, reaching
+	// MaxExtra more entries per request at MaxSize.
+	Base      int `json:"base,omitempty"`
+	Jitter    int `json:"jitter,omitempty"`
+	Threshold int `json:"threshold,omitempty"`
+	MaxSize   int `json:"max_size,omitempty"`
+	MaxExtra  int `json:"max_extra,omitempty"`
+
+	// n_plus_one
+	SpanName  string   `json:"span_name,omitempty"`
+	Statement string   `json:"statement,omitempty"`
+	Database  string   `json:"database,omitempty"`
+	CallTime  Duration `json:"call_time,omitempty"`
+	// A request makes ceil((load / LoadDivisor) ^ LoadExponent / LoadScale)
+	// calls in total, where load is Target.Load. The handler's own query is the
+	// first of them, so the scenario issues the rest.
+	LoadDivisor  float64 `json:"load_divisor,omitempty"`
+	LoadExponent float64 `json:"load_exponent,omitempty"`
+	LoadScale    float64 `json:"load_scale,omitempty"`
+}
+
+func (s *Scenario) UnmarshalJSON(b []byte) error {
+	type scenario Scenario
+	v := scenario{Enabled: true, Probability: 1}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*s = Scenario(v)
+	return nil
+}
+
+// Validate reports whether the scenario can be run.
+func (s Scenario) Validate() error {
+	if s.Name == "" || s.Point == "" {
+		return fmt.Errorf("scenario needs a name and a point")
+	}
+	if s.Probability < 0 || s.Probability > 1 {
+		return fmt.Errorf("scenario %q: probability must be between 0 and 1", s.Name)
+	}
+	switch s.Kind {
+	case KindLatency:
+		if s.MinLatency < 0 {
+			return fmt.Errorf("scenario %q: min_latency can't be negative", s.Name)
+		}
+		if s.MaxLatency < s.MinLatency {
+			return fmt.Errorf("scenario %q: max_latency is less than min_latency", s.Name)
+		}
+	case KindError:
+		if s.Message == "" {
+			return fmt.Errorf("scenario %q: error scenarios need a message", s.Name)
+		}
+	case KindLeak:
+		if s.Sink == "" || s.MaxSize <= s.Threshold {
+			return fmt.Errorf("scenario %q: leak scenarios need a sink and a max_size above threshold", s.Name)
+		}
+		if s.Base < 0 || s.Jitter < 0 || s.MaxExtra < 0 {
+			return fmt.Errorf("scenario %q: base, jitter and max_extra can't be negative", s.Name)
+		}
+	case KindNPlusOne:
+		if s.LoadDivisor <= 0 || s.LoadScale <= 0 {
+			return fmt.Errorf("scenario %q: load_divisor and load_scale must be positive", s.Name)
+		}
+	default:
+		return fmt.Errorf("scenario %q: unknown kind %q", s.Name, s.Kind)
+	}
+	return nil
+}
+
+// Config is the format of a scenario config file.
+type Config struct {
+	Scenarios []Scenario `json:"scenarios"`
+}
+
+// LoadConfig reads a scenario config file. The scenarios are validated when
+// they are given to a Registry.
+func LoadConfig(path string) ([]Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c.Scenarios, nil
+}
+
+// Target describes the request a fault is being injected into.
+type Target struct {
+	UserID    string
+	RequestID string
+	// Load scales load dependent faults, such as the current cache size.
+	Load int
+	// Attrs carries request details that leak sinks may retain.
+	Attrs map[string]string
+}
+
+// LeakSink retains the memory leaked by leak scenarios.
+type LeakSink interface {
+	// Size reports how many entries have leaked so far.
+	Size() int
+	// Leak retains n more entries on behalf of the target request.
+	Leak(ctx context.Context, t Target, n int)
+}
+
+// Error is returned by Inject when an error scenario activates.
+type Error struct {
+	Scenario string
+	Message  string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Registry holds the scenarios of one service.
+type Registry struct {
+	lock      sync.RWMutex
+	scenarios []Scenario
+	sinks     map[string]LeakSink
+	// roll returns a number in [0, 1) used to decide activation.
+	roll func() float64
+	// sleep waits for d or until ctx is done.
+	sleep func(ctx context.Context, d time.Duration)
+}
+
+// NewRegistry returns a Registry running the given scenarios.
+func NewRegistry(scenarios []Scenario) (*Registry, error) {
+	r := &Registry{
+		sinks: make(map[string]LeakSink),
+		roll:  rand.Float64,
+		sleep: sleepContext,
+	}
+	return r, r.Configure(scenarios)
+}
+
+// Configure replaces all scenarios. Nothing changes if any scenario is invalid.
+func (r *Registry) Configure(scenarios []Scenario) error {
+	seen := make(map[string]bool)
+	for _, s := range scenarios {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate scenario %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	r.lock.Lock()
+	r.scenarios = append([]Scenario(nil), scenarios...)
+	r.lock.Unlock()
+	return nil
+}
+
+// Scenarios returns the configured scenarios sorted by name.
+func (r *Registry) Scenarios() []Scenario {
+	r.lock.RLock()
+	out := append([]Scenario(nil), r.scenarios...)
+	r.lock.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Scenario returns the named scenario.
+func (r *Registry) Scenario(name string) (Scenario, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, s := range r.scenarios {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// Update replaces the scenario with the same name, or adds it.
+func (r *Registry) Update(s Scenario) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i := range r.scenarios {
+		if r.scenarios[i].Name == s.Name {
+			r.scenarios[i] = s
+			return nil
+		}
+	}
+	r.scenarios = append(r.scenarios, s)
+	return nil
+}
+
+// Modify applies change to the named scenario, keeping the result only if it is valid.
+func (r *Registry) Modify(name string, change func(*Scenario)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i := range r.scenarios {
+		if r.scenarios[i].Name != name {
+			continue
+		}
+		s := r.scenarios[i]
+		change(&s)
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		r.scenarios[i] = s
+		return nil
+	}
+	return fmt.Errorf("no scenario named %q", name)
+}
+
+// RegisterLeakSink makes sink available to leak scenarios under name.
+func (r *Registry) RegisterLeakSink(name string, sink LeakSink) {
+	r.lock.Lock()
+	r.sinks[name] = sink
+	r.lock.Unlock()
+}
+
+// Inject runs every enabled scenario configured for point that activates for
+// t. It returns an *Error if an error scenario activated.
+func (r *Registry) Inject(ctx context.Context, point string, t Target) error {
+	type activeScenario struct {
+		Scenario
+		sink LeakSink
+	}
+	r.lock.RLock()
+	var active []activeScenario
+	for _, s := range r.scenarios {
+		if s.Enabled && s.Point == point {
+			active = append(active, activeScenario{s, r.sinks[s.Sink]})
+		}
+	}
+	r.lock.RUnlock()
+
+	for _, s := range active {
+		roll := r.roll()
+		if roll >= s.probabilityFor(t.UserID) {
+			continue
+		}
+		if err := r.activate(ctx, s.Scenario, t, roll, s.sink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Scenario) probabilityFor(userID string) float64 {
+	if s.Target != nil {
+		for _, u := range s.Target.Users {
+			if u == userID {
+				return s.Target.Probability
+			}
+		}
+	}
+	return s.Probability
+}
+
+func (r *Registry) activate(ctx context.Context, s Scenario, t Target, roll float64, sink LeakSink) error {
+	attrs := []attribute.KeyValue{
+		attribute.String("app.fault.name", s.Name),
+		attribute.String("app.fault.kind", string(s.Kind)),
+		attribute.String("app.fault.point", s.Point),
+		attribute.Float64("app.fault.roll", roll),
+	}
+
+	var err error
+	switch s.Kind {
+	case KindLatency:
+		d := s.MinLatency
+		if s.MaxLatency > s.MinLatency {
+			d += Duration(r.roll() * float64(s.MaxLatency-s.MinLatency))
+		}
+		attrs = append(attrs, attribute.Int64("app.fault.latency_ms", time.Duration(d).Milliseconds()))
+		r.sleep(ctx, time.Duration(d))
+	case KindError:
+		err = &Error{Scenario: s.Name, Message: s.Message}
+	case KindLeak:
+		if sink == nil {
+			return fmt.Errorf("scenario %q: no leak sink named %q", s.Name, s.Sink)
+		}
+		n := s.leakGrowth(sink.Size())
+		attrs = append(attrs, attribute.Int("app.fault.leaked", n))
+		sink.Leak(ctx, t, n)
+	case KindNPlusOne:
+		calls := s.calls(t.Load)
+		attrs = append(attrs, attribute.Int("app.fault.calls", calls))
+		r.mockDatabaseCalls(ctx, s, calls)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attrs...)
+	span.AddEvent("fault activated", trace.WithAttributes(attrs...))
+	return err
+}
+
+// leakGrowth returns how many entries to leak given the sink's current size.
+func (s Scenario) leakGrowth(size int) int {
+	if size < s.Threshold {
+		return s.Base + rand.Intn(s.Jitter+1)
+	}
+	spread := s.MaxSize - s.Threshold
+	pos := size - s.Threshold
+	rndOffset := float64(pos) / float64(spread) * 10
+	// proportional increase starting from the top of the normal range, plus
+	// a random offset that also grows proportionally
+	return (pos*s.MaxExtra)/spread + s.Base + s.Jitter + rand.Intn(int(rndOffset)+1)
+}
+
+// calls returns how many mock calls to make on top of the handler's own query.
+func (s Scenario) calls(load int) int {
+	n := math.Ceil(math.Pow(float64(load)/s.LoadDivisor, s.LoadExponent) / s.LoadScale)
+	return int(math.Max(0, n-1))
+}
+
+func (r *Registry) mockDatabaseCalls(ctx context.Context, s Scenario, calls int) {
+	tracer := otel.GetTracerProvider().Tracer("")
+	for i := 0; i < calls; i++ {
+		_, span := tracer.Start(ctx, s.SpanName)
+		span.SetAttributes(
+			attribute.String("db.statement", s.Statement),
+			attribute.String("db.name", s.Database),
+			attribute.String("app.fault.name", s.Name),
+		)
+		// sum a few rolls so call times cluster around the middle of the range
+		wait := 0.0
+		for b := 0; b < 4; b++ {
+			wait += r.roll() * float64(s.CallTime) / 4
+		}
+		r.sleep(ctx, time.Duration(wait))
+		span.End()
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,223 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type countingSink struct {
+	size int
+}
+
+func (s *countingSink) Size() int { return s.size }
+
+func (s *countingSink) Leak(_ context.Context, _ Target, n int) { s.size += n }
+
+// newTestRegistry returns a registry whose rolls come from rolls, in order,
+// and which records sleeps instead of waiting.
+func newTestRegistry(t *testing.T, scenarios []Scenario, rolls ...float64) (*Registry, *time.Duration) {
+	t.Helper()
+	r, err := NewRegistry(scenarios)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.roll = func() float64 {
+		if len(rolls) == 0 {
+			return 0
+		}
+		v := rolls[0]
+		rolls = rolls[1:]
+		return v
+	}
+	slept := new(time.Duration)
+	r.sleep = func(_ context.Context, d time.Duration) { *slept += d }
+	return r, slept
+}
+
+func recordingContext() (context.Context, *tracetest.SpanRecorder, func()) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	return ctx, sr, func() { span.End() }
+}
+
+func TestErrorScenario(t *testing.T) {
+	r, _ := newTestRegistry(t, []Scenario{
+		{Name: "flaky", Kind: KindError, Point: "p", Enabled: true, Probability: 0.1, Message: "boom"},
+	}, 0.5, 0.05)
+
+	if err := r.Inject(context.Background(), "p", Target{}); err != nil {
+		t.Errorf("roll above probability returned %v", err)
+	}
+	err := r.Inject(context.Background(), "p", Target{})
+	var fe *Error
+	if !errors.As(err, &fe) || fe.Scenario != "flaky" || fe.Message != "boom" {
+		t.Errorf("got %v, want the flaky scenario's error", err)
+	}
+	if err := r.Inject(context.Background(), "other", Target{}); err != nil {
+		t.Errorf("scenario ran at the wrong point: %v", err)
+	}
+}
+
+func TestTargetedUser(t *testing.T) {
+	r, _ := newTestRegistry(t, []Scenario{{
+		Name: "targeted", Kind: KindError, Point: "p", Enabled: true, Probability: 0.25, Message: "boom",
+		Target: &Targeting{Users: []string{"20109"}, Probability: 0.5},
+	}}, 0.4, 0.4)
+
+	if err := r.Inject(context.Background(), "p", Target{UserID: "20109"}); err == nil {
+		t.Error("targeted user should activate at roll 0.4")
+	}
+	if err := r.Inject(context.Background(), "p", Target{UserID: "1"}); err != nil {
+		t.Error("other users should not activate at roll 0.4")
+	}
+}
+
+func TestLatencyScenario(t *testing.T) {
+	r, slept := newTestRegistry(t, []Scenario{{
+		Name: "slow", Kind: KindLatency, Point: "p", Enabled: true, Probability: 1,
+		MinLatency: Duration(100 * time.Millisecond), MaxLatency: Duration(300 * time.Millisecond),
+	}}, 0, 0.5)
+
+	ctx, sr, end := recordingContext()
+	if err := r.Inject(ctx, "p", Target{}); err != nil {
+		t.Fatal(err)
+	}
+	end()
+	if *slept != 200*time.Millisecond {
+		t.Errorf("slept %v, want 200ms", *slept)
+	}
+	attrs := map[string]string{}
+	for _, kv := range sr.Ended()[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["app.fault.name"] != "slow" || attrs["app.fault.latency_ms"] != "200" {
+		t.Errorf("activation not recorded on the span: %v", attrs)
+	}
+}
+
+func TestLeakScenario(t *testing.T) {
+	s := Scenario{
+		Name: "leak", Kind: KindLeak, Point: "p", Enabled: true, Probability: 1,
+		Sink: "cache", Base: 8, Jitter: 1, Threshold: 100, MaxSize: 200, MaxExtra: 11,
+	}
+	r, _ := newTestRegistry(t, []Scenario{s})
+	if err := r.Inject(context.Background(), "p", Target{}); err == nil {
+		t.Error("expected an error for a missing sink")
+	}
+
+	sink := &countingSink{}
+	r.RegisterLeakSink("cache", sink)
+	if err := r.Inject(context.Background(), "p", Target{}); err != nil {
+		t.Fatal(err)
+	}
+	if sink.size < 8 || sink.size > 9 {
+		t.Errorf("leaked %d entries below threshold, want 8 or 9", sink.size)
+	}
+	if n := s.leakGrowth(200); n < 20 {
+		t.Errorf("leaked %d entries at max size, want at least 20", n)
+	}
+}
+
+func TestNPlusOneScenario(t *testing.T) {
+	r, _ := newTestRegistry(t, []Scenario{{
+		Name: "discounts", Kind: KindNPlusOne, Point: "p", Enabled: true, Probability: 1,
+		SpanName: "SELECT checkout.discounts", LoadDivisor: 6000, LoadExponent: 4, LoadScale: 3000,
+	}})
+
+	ctx, sr, end := recordingContext()
+	_ = r.Inject(ctx, "p", Target{Load: 1000})
+	end()
+	if got := len(sr.Ended()); got != 1 {
+		t.Errorf("got %d spans at low load, want only the request span", got)
+	}
+
+	// (54000 / 6000) ^ 4 / 3000 = 2.187, so 3 calls in total
+	s, _ := r.Scenario("discounts")
+	if got := s.calls(54000); got != 2 {
+		t.Errorf("got %d extra calls at high load, want 2", got)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	r, _ := newTestRegistry(t, nil)
+	bad := []Scenario{
+		{Name: "a", Kind: KindError, Point: "p", Probability: 1, Message: "x"},
+		{Name: "a", Kind: KindError, Point: "p", Probability: 1, Message: "y"},
+	}
+	if err := r.Configure(bad); err == nil {
+		t.Error("duplicate names should be rejected")
+	}
+	if err := r.Update(Scenario{Name: "b", Kind: "unknown", Point: "p"}); err == nil {
+		t.Error("unknown kinds should be rejected")
+	}
+	if err := r.Update(Scenario{Name: "b", Kind: KindError, Point: "p", Probability: 1, Message: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Scenarios(); len(got) != 1 || got[0].Name != "b" {
+		t.Errorf("got %v, want scenario b", got)
+	}
+
+	if err := r.Modify("b", func(s *Scenario) { s.Probability = 2 }); err == nil {
+		t.Error("invalid changes should be rejected")
+	}
+	if err := r.Modify("b", func(s *Scenario) { s.Enabled = false }); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := r.Scenario("b"); s.Enabled || s.Probability != 1 {
+		t.Errorf("got %+v, want b disabled with its probability unchanged", s)
+	}
+	if err := r.Modify("missing", func(*Scenario) {}); err == nil {
+		t.Error("modifying a missing scenario should fail")
+	}
+}
+
+func TestValidateRejectsNegatives(t *testing.T) {
+	leak := Scenario{Name: "leak", Kind: KindLeak, Point: "p", Probability: 1, Sink: "cache", Threshold: 100, MaxSize: 200}
+	for name, modify := range map[string]func(*Scenario){
+		"base":      func(s *Scenario) { s.Base = -1 },
+		"jitter":    func(s *Scenario) { s.Jitter = -1 },
+		"max_extra": func(s *Scenario) { s.MaxExtra = -1 },
+		"min_latency": func(s *Scenario) {
+			*s = Scenario{Name: "slow", Kind: KindLatency, Point: "p", Probability: 1, MinLatency: Duration(-time.Second)}
+		},
+	} {
+		s := leak
+		modify(&s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("a negative %s was accepted", name)
+		}
+	}
+	if err := leak.Validate(); err != nil {
+		t.Errorf("a valid leak scenario was rejected: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.json")
+	config := `{"scenarios": [
+		{"name": "slow", "kind": "latency", "point": "p", "min_latency": "50ms", "max_latency": "1s"},
+		{"name": "off", "kind": "error", "point": "p", "enabled": false, "probability": 0.5, "message": "x"}
+	]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	scenarios, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, off := scenarios[0], scenarios[1]
+	if !slow.Enabled || slow.Probability != 1 || time.Duration(slow.MaxLatency) != time.Second {
+		t.Errorf("defaults not applied: %+v", slow)
+	}
+	if off.Enabled || off.Probability != 0.5 {
+		t.Errorf("explicit values not kept: %+v", off)
+	}
+}
@@ -4,6 +4,7 @@ from __future__ import annotations
 
 import json
 import logging
+import os
 import time
 from datetime import datetime
 from pathlib import Path
@@ -11,10 +12,17 @@ from typing import Any, Dict, List
 
 import pendulum
 from airflow.decorators import dag, task
+from opentelemetry import trace
+from opentelemetry.exporter.otlp.proto.http.trace_exporter import OTLPSpanExporter
+from opentelemetry.sdk.resources import Resource
+from opentelemetry.sdk.trace import TracerProvider
+from opentelemetry.sdk.trace.export import BatchSpanProcessor
+from opentelemetry.trace.propagation.tracecontext import TraceContextTextMapPropagator
 
 LOGGER = logging.getLogger(__name__)
 
-ORDERS_PATH = Path("/opt/airflow/data/orders.json")
+# Either an orders.json document or the NDJSON feed exported by checkoutservice.
+ORDERS_PATH = Path(os.environ.get("ORDERS_PATH", "/opt/airflow/data/orders.json"))
 INVOICE_DIR = Path("/opt/airflow/data/invoices")
 
 REQUIRED_ORDER_KEYS = {
@@ -39,6 +47,53 @@ def _validate_order(order: Dict[str, Any], index: int) -> None:
         raise ValueError(f"Order {order.get('order_id', index)} has invalid items payload.")
 
 
+def _load_orders(path: Path) -> List[Dict[str, Any]]:
+    with path.open("r", encoding="utf-8") as handle:
+        text = handle.read()
+
+    try:
+        payload = json.loads(text)
+    except json.JSONDecodeError:
+        # NDJSON: one order per line
+        return [json.loads(line) for line in text.splitlines() if line.strip()]
+
+    if isinstance(payload, dict) and "order_id" in payload:
+        # an NDJSON feed holding a single order parses as one object
+        return [payload]
+
+    orders = payload.get("orders", [])
+    if not isinstance(orders, list):
+        raise ValueError("orders.json must contain an array under the 'orders' key.")
+    return orders
+
+
+def _tracer_provider() -> TracerProvider:
+    """Returns the SDK tracer provider, setting one up to export to the collector
+    Airflow reports to if the task process doesn't have one yet."""
+    provider = trace.get_tracer_provider()
+    if isinstance(provider, TracerProvider):
+        return provider
+    provider = TracerProvider(resource=Resource.create({"service.name": "invoice"}))
+    endpoint = os.environ.get(
+        "OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-gateway-opentelemetry-collector:4318"
+    )
+    provider.add_span_processor(
+        BatchSpanProcessor(OTLPSpanExporter(endpoint=endpoint.rstrip("/") + "/v1/traces"))
+    )
+    trace.set_tracer_provider(provider)
+    return provider
+
+
+def _checkout_links(order: Dict[str, Any]) -> List[trace.Link]:
+    """Links to the checkout span that exported the order, from the W3C
+    traceparent in its trace_context, if it has one."""
+    context = TraceContextTextMapPropagator().extract(order.get("trace_context") or {})
+    span_context = trace.get_current_span(context).get_span_context()
+    if not span_context.is_valid:
+        return []
+    return [trace.Link(span_context, {"app.link.source": "checkout"})]
+
+
 def _build_invoice(order: Dict[str, Any]) -> Dict[str, Any]:
     subtotal_recalc = sum(item["unit_price"] * item["quantity"] for item in order["items"])
     tax_from_rate = round(subtotal_recalc * (order["tax_percent"] / 100), 2)
@@ -68,21 +123,17 @@ def process_orders_dag():
     @task()
     def read_orders() -> List[Dict[str, Any]]:
         LOGGER.info("read_orders: loading orders from %s", ORDERS_PATH)
-        with ORDERS_PATH.open("r", encoding="utf-8") as handle:
-            payload = json.load(handle)
-
-        orders = payload.get("orders", [])
-        if not isinstance(orders, list):
-            raise ValueError("orders.json must contain an array under the 'orders' key.")
+        orders = _load_orders(ORDERS_PATH)
 
         LOGGER.info("read_orders: payload contains %d orders", len(orders))
         for idx, order in enumerate(orders):
             _validate_order(order, idx)
             LOGGER.debug(
-                "read_orders: order %s (customer=%s, total=%.2f) passed validation",
+                "read_orders: order %s (customer=%s, total=%.2f, traceparent=%s) passed validation",
                 order["order_id"],
                 order["customer_email"],
                 order["total_charged"],
+                order.get("trace_context", {}).get("traceparent"),
             )
 
         LOGGER.info("read_orders: validation complete for %d orders", len(orders))
@@ -98,13 +149,21 @@ def process_orders_dag():
         )
 
         invoice_metadata: List[Dict[str, Any]] = []
+        provider = _tracer_provider()
+        tracer = provider.get_tracer(__name__)
 
         for order in orders:
-            invoice_payload = _build_invoice(order)
             invoice_path = INVOICE_DIR / f"invoice_{order['order_id']}.json"
-
-            with invoice_path.open("w", encoding="utf-8") as handle:
-                json.dump(invoice_payload, handle, indent=2)
+            # a link rather than a parent: the invoice is made long after the
+            # checkout trace ended, in a batch of many orders
+            with tracer.start_as_current_span(
+                "generate invoice",
+                links=_checkout_links(order),
+                attributes={"app.order_id": order["order_id"], "app.invoice.path": str(invoice_path)},
+            ):
+                invoice_payload = _build_invoice(order)
+                with invoice_path.open("w", encoding="utf-8") as handle:
+                    json.dump(invoice_payload, handle, indent=2)
 
             LOGGER.info(
                 "generate_invoices: wrote invoice for order %s (customer=%s, total=%.2f) to %s",
@@ -124,6 +183,7 @@ def process_orders_dag():
                     "customer_email": order["customer_email"],
                     "invoice_path": str(invoice_path),
                     "total_charged": order["total_charged"],
+                    "trace_context": order.get("trace_context", {}),
                 }
             )
 
@@ -131,6 +191,8 @@ def process_orders_dag():
             "generate_invoices: finished creating %d invoice artifacts",
             len(invoice_metadata),
         )
+        # the task process may exit before the batch processor's next export
+        provider.force_flush()
         return invoice_metadata
 
     @task()
@@ -19,6 +19,8 @@ products = [
 
 currencies = ['EUR', 'USD', 'JPY', 'CAD']
 
+searches = ['mug', 'vintage', 'kitchen', 'plush', 'sunglases', 'watch', 'hair']
+
 people = [
     {
         'email': 'someone@example.com',
@@ -104,6 +106,13 @@ class WebsiteUser(HttpUser):
 
         self.client.get("/product/" + product)
 
+    @task(3)
+    def search(self):
+        params = {'q': random.choice(searches)}
+        if random.random() < 0.3:
+            params['sort'] = random.choice(['price_asc', 'price_desc', 'name'])
+        self.client.get("/search", params=params)
+
     @task(3)
     def view_cart(self):
         self.client.get("/cart")
@@ -0,0 +1,37 @@
+const { v4: uuidv4 } = require('uuid');
+const pino = require('pino');
+
+const logger = pino({
+  name: 'paymentservice-refund',
+  messageKey: 'message',
+  changeLevelName: 'severity',
+  useLevelLabels: true
+});
+
+class RefundError extends Error {
+  constructor (message) {
+    super(message);
+    this.code = 3; // gRPC INVALID_ARGUMENT
+  }
+}
+
+/**
+ * (Pretend) refunds a previous charge.
+ *
+ * @param {*} request
+ * @return refund_id - a random uuid v4.
+ */
+module.exports = function refund (request) {
+  const { transaction_id: transactionId, amount } = request;
+
+  if (!transactionId) {
+    throw new RefundError('transaction_id is required to issue a refund');
+  }
+  if (!amount || !amount.currency_code) {
+    throw new RefundError('amount with a currency_code is required to issue a refund');
+  }
+
+  logger.info(`Refund processed: transaction ${transactionId} | Amount: ${amount.currency_code}${amount.units}.${amount.nanos}`);
+
+  return { refund_id: uuidv4() };
+};
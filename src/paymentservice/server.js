@@ -5,6 +5,7 @@ const protoLoader = require('@grpc/proto-loader');
 const opentelemetry = require('@opentelemetry/api');
 
 const charge = require('./charge');
+const refund = require('./refund');
 
 const logger = pino({
     name: 'paymentservice-server',
@@ -69,6 +70,24 @@ class MicroservicesDemoServer {
         }
     }
 
+    /**
+     * Handler for PaymentService.Refund.
+     * @param {*} call  { RefundRequest }
+     * @param {*} callback  fn(err, RefundResponse)
+     */
+    static async RefundServiceHandler(call, callback) {
+        await sleepRandom(50);
+        await mockDatabaseCall(30, "INSERT payment.refunds", "INSERT INTO refunds (transaction_id, amount) VALUES (?, ?)");
+        try {
+            logger.info(`PaymentService#Refund invoked with request ${JSON.stringify(call.request)}`);
+            const response = refund(call.request);
+            callback(null, response);
+        } catch (err) {
+            console.warn(err);
+            callback(err);
+        }
+    }
+
     static CheckHandler(call, callback) {
         callback(null, {status: 'SERVING'});
     }
@@ -108,7 +127,8 @@ class MicroservicesDemoServer {
         this.server.addService(
             msdemoPackage.PaymentService.service,
             {
-                charge: MicroservicesDemoServer.ChargeServiceHandler.bind(this)
+                charge: MicroservicesDemoServer.ChargeServiceHandler.bind(this),
+                refund: MicroservicesDemoServer.RefundServiceHandler.bind(this)
             }
         );
 
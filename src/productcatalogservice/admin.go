@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminService serves the AdminService, which lets presenters change demo
+// settings without restarting the service. Calls must carry token.
+type adminService struct {
+	token    string
+	settings *admin.Settings
+}
+
+func newAdminService(token string) *adminService {
+	return &adminService{token: token, settings: admin.NewSettings(map[string]admin.Setting{
+		"reload_catalog": admin.Bool(reloadCatalog.Load, reloadCatalog.Store),
+	})}
+}
+
+func (a *adminService) GetSettings(ctx context.Context, _ *pb.Empty) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	return &pb.AdminSettings{Settings: a.settings.Current()}, nil
+}
+
+func (a *adminService) UpdateSettings(ctx context.Context, in *pb.AdminSettings) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	current, err := a.settings.Update(ctx, log, in.Settings)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.AdminSettings{Settings: current}, nil
+}
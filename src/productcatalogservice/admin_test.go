@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// adminContext returns the context of an AdminService call carrying token.
+func adminContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAdminReloadCatalog(t *testing.T) {
+	defer reloadCatalog.Store(false)
+	admin := newAdminService("secret")
+	ctx := adminContext("secret")
+
+	got, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"reload_catalog": "true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloadCatalog.Load() || got.Settings["reload_catalog"] != "true" {
+		t.Errorf("reload_catalog not enabled, got settings %v", got.Settings)
+	}
+
+	_, err = admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"reload_catalog": "sometimes"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", err)
+	}
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	admin := newAdminService("secret")
+	ctx := adminContext("wrong")
+	if _, err := admin.GetSettings(ctx, &pb.Empty{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetSettings: got %v, want Unauthenticated", err)
+	}
+	_, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"reload_catalog": "true"}})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("UpdateSettings: got %v, want Unauthenticated", err)
+	}
+	if reloadCatalog.Load() {
+		t.Error("an unauthorized update was applied")
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/search"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// catalogSnapshot is one version of the catalog. It is never changed after it
+// is built, so requests can keep using it while a newer one is swapped in.
+type catalogSnapshot struct {
+	products []*pb.Product
+	byID     map[string]*pb.Product
+	index    *search.Index
+
+	// version is a hash of the products, whichever store they came from.
+	version  string
+	source   string
+	loadedAt time.Time
+}
+
+// catalogVersion is the ETag of a list of products.
+func catalogVersion(products []*pb.Product) string {
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(&pb.ListProductsResponse{Products: products})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// newCatalogSnapshot validates and indexes products loaded from source.
+func newCatalogSnapshot(products []*pb.Product, source string) (*catalogSnapshot, error) {
+	if err := validateProducts(products); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", source, err)
+	}
+	s := &catalogSnapshot{
+		products: products,
+		byID:     make(map[string]*pb.Product, len(products)),
+		index:    search.NewIndex(products),
+		version:  catalogVersion(products),
+		source:   source,
+		loadedAt: time.Now(),
+	}
+	for _, p := range products {
+		s.byID[p.Id] = p
+	}
+	return s, nil
+}
+
+// validateProducts rejects catalogs the shop can't sell from: empty ones, and
+// ones with products that lack an ID, a name or a USD price.
+func validateProducts(products []*pb.Product) error {
+	if len(products) == 0 {
+		return errors.New("no products")
+	}
+	seen := make(map[string]bool, len(products))
+	for i, p := range products {
+		switch {
+		case p.Id == "":
+			return fmt.Errorf("product %d has no id", i)
+		case seen[p.Id]:
+			return fmt.Errorf("product id %s is used twice", p.Id)
+		case p.Name == "":
+			return fmt.Errorf("product %s has no name", p.Id)
+		case p.PriceUsd == nil:
+			return fmt.Errorf("product %s has no price", p.Id)
+		case p.PriceUsd.CurrencyCode != "USD":
+			return fmt.Errorf("product %s is priced in %q, not USD", p.Id, p.PriceUsd.CurrencyCode)
+		case !validMoney(p.PriceUsd) || negativeMoney(p.PriceUsd):
+			return fmt.Errorf("product %s has an invalid price", p.Id)
+		}
+		seen[p.Id] = true
+	}
+	return nil
+}
+
+// catalogWatcher serves the catalog in a store, checking the store for changes
+// every interval. Changed products are built into a new snapshot, which
+// replaces the current one only if it is valid.
+type catalogWatcher struct {
+	store    CatalogStore
+	interval time.Duration
+	current  atomic.Pointer[catalogSnapshot]
+
+	// lock serializes reloads, and guards the fields below
+	lock     sync.Mutex
+	revision string
+	lastErr  error
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func newCatalogWatcher(store CatalogStore, interval time.Duration) *catalogWatcher {
+	return &catalogWatcher{store: store, interval: interval}
+}
+
+// snapshot returns the current catalog, or nil if none has loaded.
+func (w *catalogWatcher) snapshot() *catalogSnapshot {
+	return w.current.Load()
+}
+
+// start checks the store every interval until shutdown is called.
+func (w *catalogWatcher) start() {
+	if w.interval <= 0 {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done.Add(1)
+	go w.watch()
+}
+
+func (w *catalogWatcher) shutdown() {
+	if w.stop != nil {
+		close(w.stop)
+		w.done.Wait()
+		w.stop = nil
+	}
+}
+
+func (w *catalogWatcher) watch() {
+	defer w.done.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		_ = w.reload(context.Background(), false)
+	}
+}
+
+// reload loads the products if the store changed since the last load, or
+// always if force is set, and swaps in their snapshot if they changed. The
+// current snapshot is kept if they are invalid.
+func (w *catalogWatcher) reload(ctx context.Context, force bool) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.reloadLocked(ctx, force)
+}
+
+func (w *catalogWatcher) reloadLocked(ctx context.Context, force bool) error {
+	since := w.revision
+	if force {
+		since = ""
+	}
+	products, revision, err := w.store.Load(ctx, since)
+	if errors.Is(err, errCatalogNotModified) {
+		return nil
+	}
+	if revision != "" {
+		// an invalid catalog isn't loaded again until the store changes
+		w.revision = revision
+	}
+	if err != nil {
+		return w.reject(err)
+	}
+	s, err := newCatalogSnapshot(products, w.store.Source())
+	if err != nil {
+		return w.reject(err)
+	}
+	w.lastErr = nil
+	old := w.current.Load()
+	if old != nil && old.version == s.version {
+		return nil
+	}
+	w.current.Store(s)
+	fields := logrus.Fields{"version": s.version, "products": len(s.products)}
+	if old != nil {
+		fields["previous_version"] = old.version
+	}
+	log.WithFields(fields).Info("loaded product catalog")
+	return nil
+}
+
+// update saves the products fn returns to the store, which must be writable,
+// and swaps in their snapshot. fn is given a copy of the current list, which
+// it may change, but it must replace rather than change the products in it.
+// Errors returned by fn are passed on as they are.
+func (w *catalogWatcher) update(ctx context.Context, fn func([]*pb.Product) ([]*pb.Product, error)) (*catalogSnapshot, error) {
+	store, ok := w.store.(WritableCatalogStore)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "the catalog at %s is read-only", w.store.Source())
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// build on changes made to the store since the last poll, and don't
+	// overwrite an invalid catalog someone is still fixing
+	if err := w.reloadLocked(ctx, false); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "the catalog in the store is invalid: %v", err)
+	}
+	old := w.current.Load()
+	if old == nil {
+		return nil, status.Error(codes.Unavailable, "the product catalog is not loaded")
+	}
+	products, err := fn(append([]*pb.Product(nil), old.products...))
+	if err != nil {
+		return nil, err
+	}
+	s, err := newCatalogSnapshot(products, store.Source())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	revision, err := store.Save(ctx, products)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save the catalog: %v", err)
+	}
+	w.revision, w.lastErr = revision, nil
+	w.current.Store(s)
+	log.WithFields(logrus.Fields{"version": s.version, "previous_version": old.version, "products": len(s.products)}).Info("saved product catalog")
+	return s, nil
+}
+
+func (w *catalogWatcher) reject(err error) error {
+	w.lastErr = err
+	fields := logrus.Fields{"error": err}
+	if s := w.current.Load(); s != nil {
+		fields["version"] = s.version
+	}
+	log.WithFields(fields).Warn("rejected product catalog, keeping the current one")
+	return err
+}
+
+// info describes the current snapshot and the latest failed reload.
+func (w *catalogWatcher) info() *pb.CatalogInfo {
+	out := &pb.CatalogInfo{Source: w.store.Source()}
+	if s := w.current.Load(); s != nil {
+		out.Version = s.version
+		out.ProductCount = int32(len(s.products))
+		out.LoadedAt = s.loadedAt.Unix()
+	}
+	w.lock.Lock()
+	if w.lastErr != nil {
+		out.LastError = w.lastErr.Error()
+	}
+	w.lock.Unlock()
+	return out
+}
+
+// requestCatalog returns the snapshot to serve a request from and records its
+// version on the request's span. With reloadCatalog set, the store is read
+// again first, which is the slow path of the demo's reloading bug.
+func requestCatalog(ctx context.Context) (*catalogSnapshot, error) {
+	if reloadCatalog.Load() {
+		_ = catalog.reload(ctx, true)
+		sleepRandom(50)
+	}
+	s := catalog.snapshot()
+	if s == nil {
+		return nil, status.Error(codes.Unavailable, "the product catalog is not loaded")
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("app.catalog.version", s.version))
+	return s, nil
+}
+
+func (p *productCatalog) GetCatalogInfo(ctx context.Context, _ *pb.Empty) (*pb.CatalogInfo, error) {
+	out := catalog.info()
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("app.catalog.version", out.Version),
+		attribute.Int("app.catalog.products", int(out.ProductCount)),
+	)
+	return out, nil
+}
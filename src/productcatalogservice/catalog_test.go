@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	twoProducts = `{"products": [
+		{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "USD", "units": 8}},
+		{"id": "B", "name": "Plate", "priceUsd": {"currencyCode": "USD", "units": 12}}
+	]}`
+	oneProduct = `{"products": [{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "USD", "units": 9}}]}`
+)
+
+// writeCatalog writes data to path and backdates it by age, so successive
+// writes have different modification times even on coarse file systems.
+func writeCatalog(t *testing.T, path, data string, age time.Duration) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(-age)
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestSnapshot builds a snapshot from a JSON catalog.
+func newTestSnapshot(data string) (*catalogSnapshot, error) {
+	products, err := decodeJSONCatalog([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	return newCatalogSnapshot(products, "test")
+}
+
+func TestNewCatalogSnapshot(t *testing.T) {
+	s, err := newTestSnapshot(twoProducts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.products) != 2 || s.byID["B"].GetName() != "Plate" || s.index.Len() != 2 {
+		t.Errorf("got snapshot %+v", s)
+	}
+	if len(s.version) != 16 {
+		t.Errorf("got version %q", s.version)
+	}
+	if other, _ := newTestSnapshot(oneProduct); other.version == s.version {
+		t.Error("different catalogs have the same version")
+	}
+
+	for name, data := range map[string]string{
+		"not json":      `{"products": [`,
+		"unknown field": `{"items": []}`,
+		"empty":         `{"products": []}`,
+		"no id":         `{"products": [{"name": "Mug", "priceUsd": {"currencyCode": "USD", "units": 8}}]}`,
+		"duplicate id":  `{"products": [{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "USD"}}, {"id": "A", "name": "Cup", "priceUsd": {"currencyCode": "USD"}}]}`,
+		"no name":       `{"products": [{"id": "A", "priceUsd": {"currencyCode": "USD", "units": 8}}]}`,
+		"no price":      `{"products": [{"id": "A", "name": "Mug"}]}`,
+		"not USD":       `{"products": [{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "EUR", "units": 8}}]}`,
+		"negative":      `{"products": [{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "USD", "units": -8}}]}`,
+	} {
+		if _, err := newTestSnapshot(data); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func newTestWatcher(t *testing.T, path string, interval time.Duration) *catalogWatcher {
+	t.Helper()
+	store, err := newCatalogStore("json", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newCatalogWatcher(store, interval)
+	if err := w.reload(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestCatalogWatcherKeepsLastValidCatalog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "products.json")
+	writeCatalog(t, path, twoProducts, 3*time.Hour)
+	w := newTestWatcher(t, path, 0)
+	first := w.snapshot()
+
+	// an unchanged file keeps the same snapshot
+	if err := w.reload(ctx, false); err != nil || w.snapshot() != first {
+
+		t.Errorf("reloading an unchanged file replaced the snapshot, err %v", err)
+	}
+
+	writeCatalog(t, path, `{"products": [{"id": "A"}]}`, 2*time.Hour)
+	if err := w.reload(ctx, false); err == nil {
+		t.Error("an invalid file was accepted")
+	}
+	if w.snapshot() != first {
+		t.Error("an invalid file replaced the snapshot")
+	}
+	if info := w.info(); info.Version != first.version || !strings.Contains(info.LastError, "no name") {
+		t.Errorf("got info %v", info)
+	}
+
+	writeCatalog(t, path, oneProduct, time.Hour)
+	if err := w.reload(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	second := w.snapshot()
+	if second.version == first.version || second.byID["A"].GetPriceUsd().GetUnits() != 9 {
+		t.Errorf("the new file wasn't loaded, got %+v", second)
+	}
+	if info := w.info(); info.LastError != "" || info.ProductCount != 1 {
+		t.Errorf("got info %v", info)
+	}
+	if first.byID["A"].GetPriceUsd().GetUnits() != 8 {
+		t.Error("the old snapshot changed")
+	}
+}
+
+func TestCatalogWatcherPolls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.json")
+	writeCatalog(t, path, twoProducts, time.Hour)
+	w := newTestWatcher(t, path, time.Millisecond)
+	w.start()
+	defer w.shutdown()
+
+	// readers see one snapshot or the other, never a mix
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if s := w.snapshot(); len(s.products) != len(s.byID) || len(s.products) != s.index.Len() {
+				t.Errorf("inconsistent snapshot %+v", s)
+				return
+			}
+		}
+	}()
+
+	writeCatalog(t, path, oneProduct, 0)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(w.snapshot().products) != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+	if len(w.snapshot().products) != 1 {
+		t.Error("the changed file was not loaded")
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/inventory"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultInitialStock    = 100
+	defaultReservationTTL  = 10 * time.Minute
+	defaultRestockInterval = 5 * time.Minute
+)
+
+// newStockFromEnv creates the stock store from INVENTORY_INITIAL_STOCK and
+// INVENTORY_RESERVATION_TTL, and returns INVENTORY_RESTOCK_INTERVAL.
+func newStockFromEnv() (*inventory.Store, time.Duration, error) {
+	initial, ttl, restock := int64(defaultInitialStock), defaultReservationTTL, defaultRestockInterval
+	if v := os.Getenv("INVENTORY_INITIAL_STOCK"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("invalid INVENTORY_INITIAL_STOCK %q", v)
+		}
+		initial = n
+	}
+	for env, d := range map[string]*time.Duration{"INVENTORY_RESERVATION_TTL": &ttl, "INVENTORY_RESTOCK_INTERVAL": &restock} {
+		if v := os.Getenv(env); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid %s %q: %w", env, v, err)
+			}
+			*d = parsed
+		}
+	}
+	return inventory.New(int32(initial), ttl), restock, nil
+}
+
+// restockEvery refills the stock every interval, as if a delivery arrived, so
+// the load generator doesn't sell the shop out for good.
+func restockEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		if n := stock.Restock(); n > 0 {
+			log.WithField("products", n).Info("restocked products")
+		}
+	}
+}
+
+// inventoryService serves the InventoryService for the products in the catalog.
+type inventoryService struct{}
+
+// inCatalog checks every product ID is in the current catalog.
+func inCatalog(ids ...string) error {
+	s := catalog.snapshot()
+	if s == nil {
+		return status.Error(codes.Unavailable, "the product catalog is not loaded")
+	}
+	for _, id := range ids {
+		if _, ok := s.byID[id]; !ok {
+			return status.Errorf(codes.NotFound, "no product with ID %s", id)
+		}
+	}
+	return nil
+}
+
+func (i *inventoryService) GetStock(ctx context.Context, req *pb.GetStockRequest) (*pb.GetStockResponse, error) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("app.product_ids", req.GetProductIds()))
+	s := catalog.snapshot()
+	if s == nil {
+		return nil, status.Error(codes.Unavailable, "the product catalog is not loaded")
+	}
+	res := &pb.GetStockResponse{}
+	seen := make(map[string]bool, len(req.GetProductIds()))
+	for _, id := range req.GetProductIds() {
+		if _, ok := s.byID[id]; !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		res.Levels = append(res.Levels, &pb.StockLevel{ProductId: id, Available: stock.Available(id)})
+	}
+	return res, nil
+}
+
+func (i *inventoryService) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("app.reservation_id", req.GetReservationId()),
+		attribute.Int("app.reservation.items", len(req.GetItems())),
+	)
+	if req.GetReservationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "a reservation ID is required")
+	}
+	items := make([]inventory.Item, len(req.GetItems()))
+	ids := make([]string, len(req.GetItems()))
+	for n, it := range req.GetItems() {
+		items[n] = inventory.Item{ProductID: it.GetProductId(), Quantity: it.GetQuantity()}
+		ids[n] = it.GetProductId()
+	}
+	if err := inCatalog(ids...); err != nil {
+		return nil, err
+	}
+
+	expires, err := stock.Reserve(req.GetReservationId(), items)
+	var short *inventory.InsufficientStockError
+	switch {
+	case errors.As(err, &short):
+		span.SetAttributes(
+			attribute.String("app.stock.short_product_id", short.ProductID),
+			attribute.Int("app.stock.available", int(short.Available)),
+		)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, inventory.ErrReservationConflict):
+		return nil, status.Errorf(codes.AlreadyExists, "reservation %s: %v", req.GetReservationId(), err)
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	log.WithFields(logrus.Fields{"reservation_id": req.GetReservationId(), "product_ids": ids}).Debug("reserved stock")
+	return &pb.ReserveStockResponse{ExpiresAt: expires.Unix()}, nil
+}
+
+func (i *inventoryService) CommitReservation(ctx context.Context, req *pb.ReservationRequest) (*pb.Empty, error) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("app.reservation_id", req.GetReservationId()))
+	if err := stock.Commit(req.GetReservationId()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "reservation %s: %v", req.GetReservationId(), err)
+	}
+	log.WithField("reservation_id", req.GetReservationId()).Debug("committed reservation")
+	return &pb.Empty{}, nil
+}
+
+func (i *inventoryService) ReleaseReservation(ctx context.Context, req *pb.ReservationRequest) (*pb.Empty, error) {
+	released := stock.Release(req.GetReservationId())
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("app.reservation_id", req.GetReservationId()),
+		attribute.Bool("app.reservation.released", released),
+	)
+	if released {
+		log.WithField("reservation_id", req.GetReservationId()).Info("released reservation")
+	}
+	return &pb.Empty{}, nil
+}
+
+func (i *inventoryService) SetStock(ctx context.Context, req *pb.SetStockRequest) (*pb.StockLevel, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.product_id", req.GetProductId()))
+	if err := inCatalog(req.GetProductId()); err != nil {
+		return nil, err
+	}
+	available, err := stock.Set(req.GetProductId(), req.GetQuantity())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	span.SetAttributes(attribute.Int("app.stock.available", int(available)))
+	log.WithFields(logrus.Fields{"product_id": req.GetProductId(), "quantity": req.GetQuantity()}).Info("stock set")
+	return &pb.StockLevel{ProductId: req.GetProductId(), Available: available}, nil
+}
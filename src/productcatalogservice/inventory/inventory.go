@@ -0,0 +1,261 @@
+// Package inventory keeps the stock level of every product and the
+// reservations orders hold against it.
+//
+// An order reserves its items before the customer is charged. Reserved items
+// are no longer available to other orders, but stay on hand until the
+// reservation is committed, when the order is placed, or released, when it
+// fails. A reservation that is neither committed nor released in time expires
+// and its items become available again, so a checkout that dies part way
+// can't hold stock forever.
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownReservation is returned for a reservation that doesn't exist
+	// or has expired.
+	ErrUnknownReservation = errors.New("unknown reservation")
+	// ErrReservationConflict is returned when a reservation ID is reused for
+	// different items.
+	ErrReservationConflict = errors.New("the reservation exists with different items")
+)
+
+// InsufficientStockError is returned when a reservation asks for more of a
+// product than is available.
+type InsufficientStockError struct {
+	ProductID string
+	Requested int32
+	Available int32
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("only %d of product %s available, %d requested", e.Available, e.ProductID, e.Requested)
+}
+
+// Item is a quantity of a product.
+type Item struct {
+	ProductID string
+	Quantity  int32
+}
+
+type level struct {
+	onHand int32
+	held   int32
+}
+
+// available is never negative, even when the stock on hand was set below
+// what is reserved.
+func (l *level) available() int32 {
+	return max(l.onHand-l.held, 0)
+}
+
+type reservation struct {
+	items     []Item
+	expires   time.Time
+	committed bool
+}
+
+// Store holds the stock levels in memory. Products it hasn't seen yet start
+// with the initial stock. It is safe for concurrent use.
+type Store struct {
+	initial int32
+	ttl     time.Duration
+	// now is replaced in tests
+	now func() time.Time
+
+	lock         sync.Mutex
+	levels       map[string]*level
+	reservations map[string]*reservation
+}
+
+// New returns a store where every product starts with initial items on hand,
+// and reservations expire after ttl. Committed reservations are kept for the
+// same time, so an order that fails after committing can still release them.
+func New(initial int32, ttl time.Duration) *Store {
+	return &Store{
+		initial:      initial,
+		ttl:          ttl,
+		now:          time.Now,
+		levels:       make(map[string]*level),
+		reservations: make(map[string]*reservation),
+	}
+}
+
+func (s *Store) level(productID string) *level {
+	l, ok := s.levels[productID]
+	if !ok {
+		l = &level{onHand: s.initial}
+		s.levels[productID] = l
+	}
+	return l
+}
+
+// expire releases reservations that weren't committed in time, and forgets
+// committed ones once they can no longer be released.
+func (s *Store) expire() {
+	now := s.now()
+	for id, r := range s.reservations {
+		if now.Before(r.expires) {
+			continue
+		}
+		if !r.committed {
+			for _, it := range r.items {
+				s.level(it.ProductID).held -= it.Quantity
+			}
+		}
+		delete(s.reservations, id)
+	}
+}
+
+// Available returns how many of the product can be reserved.
+func (s *Store) Available(productID string) int32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expire()
+	return s.level(productID).available()
+}
+
+// Set changes the stock on hand of a product and returns how many are
+// available, which is less than quantity if some are reserved.
+func (s *Store) Set(productID string, quantity int32) (int32, error) {
+	if quantity < 0 {
+		return 0, fmt.Errorf("stock can't be negative, got %d", quantity)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expire()
+	l := s.level(productID)
+	l.onHand = quantity
+	return l.available(), nil
+}
+
+// Restock brings every product with less than the initial stock on hand back
+// up to it, and returns how many products it restocked.
+func (s *Store) Restock() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := 0
+	for _, l := range s.levels {
+		if l.onHand < s.initial {
+			l.onHand = s.initial
+			n++
+		}
+	}
+	return n
+}
+
+// merge adds up the quantities of each product, in product ID order.
+func merge(items []Item) ([]Item, error) {
+	byID := make(map[string]int32, len(items))
+	for _, it := range items {
+		if it.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity of product %s must be positive, got %d", it.ProductID, it.Quantity)
+		}
+		byID[it.ProductID] += it.Quantity
+	}
+	out := make([]Item, 0, len(byID))
+	for id, q := range byID {
+		out = append(out, Item{ProductID: id, Quantity: q})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProductID < out[j].ProductID })
+	return out, nil
+}
+
+func sameItems(a, b []Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reserve holds the items under the reservation ID, all of them or none, and
+// returns when the reservation expires. Reserving the same items under the
+// same ID again returns the existing reservation.
+func (s *Store) Reserve(id string, items []Item) (time.Time, error) {
+	items, err := merge(items)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(items) == 0 {
+		return time.Time{}, errors.New("a reservation needs at least one item")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expire()
+
+	if r, ok := s.reservations[id]; ok {
+		if !sameItems(r.items, items) {
+			return time.Time{}, ErrReservationConflict
+		}
+		return r.expires, nil
+	}
+	for _, it := range items {
+		l := s.level(it.ProductID)
+		if available := l.available(); it.Quantity > available {
+			return time.Time{}, &InsufficientStockError{ProductID: it.ProductID, Requested: it.Quantity, Available: available}
+		}
+	}
+	for _, it := range items {
+		s.level(it.ProductID).held += it.Quantity
+	}
+	r := &reservation{items: items, expires: s.now().Add(s.ttl)}
+	s.reservations[id] = r
+	return r.expires, nil
+}
+
+// Commit takes the reserved items out of stock. Committing twice is the same
+// as committing once.
+func (s *Store) Commit(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expire()
+	r, ok := s.reservations[id]
+	if !ok {
+		return ErrUnknownReservation
+	}
+	if r.committed {
+		return nil
+	}
+	for _, it := range r.items {
+		l := s.level(it.ProductID)
+		l.held -= it.Quantity
+		l.onHand = max(l.onHand-it.Quantity, 0)
+	}
+	r.committed = true
+	r.expires = s.now().Add(s.ttl)
+	return nil
+}
+
+// Release makes the items of a reservation available again, putting them back
+// in stock if it was committed. It reports whether there was anything to
+// release: releasing an unknown or expired reservation does nothing.
+func (s *Store) Release(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expire()
+	r, ok := s.reservations[id]
+	if !ok {
+		return false
+	}
+	for _, it := range r.items {
+		l := s.level(it.ProductID)
+		if r.committed {
+			l.onHand += it.Quantity
+		} else {
+			l.held -= it.Quantity
+		}
+	}
+	delete(s.reservations, id)
+	return true
+}
@@ -0,0 +1,150 @@
+package inventory
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestStore(initial int32) (*Store, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	s := New(initial, time.Minute)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestReserveCommitRelease(t *testing.T) {
+	s, _ := newTestStore(5)
+
+	if _, err := s.Reserve("order-1", []Item{{"A", 2}, {"B", 1}, {"A", 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Available("A"); got != 2 {
+		t.Errorf("got %d of A available after reserving 3, want 2", got)
+	}
+
+	_, err := s.Reserve("order-2", []Item{{"B", 1}, {"A", 3}})
+	var short *InsufficientStockError
+	if !errors.As(err, &short) || short.ProductID != "A" || short.Available != 2 || short.Requested != 3 {
+		t.Fatalf("got %v, want an InsufficientStockError for A", err)
+	}
+	if got := s.Available("B"); got != 4 {
+		t.Errorf("a failed reservation held B: %d available, want 4", got)
+	}
+
+	if err := s.Commit("order-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Commit("order-1"); err != nil {
+		t.Errorf("committing twice: %v", err)
+	}
+	if got := s.Available("A"); got != 2 {
+		t.Errorf("got %d of A after committing, want 2", got)
+	}
+	if !s.Release("order-1") {
+		t.Error("a committed reservation wasn't released")
+	}
+	if got := s.Available("A"); got != 5 {
+		t.Errorf("got %d of A after releasing the committed order, want 5", got)
+	}
+	if s.Release("order-1") {
+		t.Error("a reservation was released twice")
+	}
+	if err := s.Commit("nope"); !errors.Is(err, ErrUnknownReservation) {
+		t.Errorf("got %v committing an unknown reservation", err)
+	}
+}
+
+func TestReserveIsIdempotent(t *testing.T) {
+	s, _ := newTestStore(5)
+	first, err := s.Reserve("order-1", []Item{{"A", 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.Reserve("order-1", []Item{{"A", 1}, {"A", 1}})
+	if err != nil || !again.Equal(first) {
+		t.Errorf("reserving again returned %v, %v", again, err)
+	}
+	if got := s.Available("A"); got != 3 {
+		t.Errorf("got %d available, want 3", got)
+	}
+	if _, err := s.Reserve("order-1", []Item{{"A", 3}}); !errors.Is(err, ErrReservationConflict) {
+		t.Errorf("got %v for different items, want ErrReservationConflict", err)
+	}
+	for _, items := range [][]Item{nil, {{"A", 0}}, {{"A", -1}}} {
+		if _, err := s.Reserve("order-2", items); err == nil {
+			t.Errorf("reserved %v", items)
+		}
+	}
+}
+
+func TestReservationsExpire(t *testing.T) {
+	s, now := newTestStore(5)
+	if _, err := s.Reserve("order-1", []Item{{"A", 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reserve("order-2", []Item{{"B", 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Commit("order-2"); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(time.Minute)
+	if got := s.Available("A"); got != 5 {
+		t.Errorf("got %d of A after the reservation expired, want 5", got)
+	}
+	if err := s.Commit("order-1"); !errors.Is(err, ErrUnknownReservation) {
+		t.Errorf("committed an expired reservation: %v", err)
+	}
+	if s.Release("order-2") || s.Available("B") != 3 {
+		t.Error("a committed reservation was released after it expired")
+	}
+}
+
+func TestSetAndRestock(t *testing.T) {
+	s, _ := newTestStore(5)
+	if _, err := s.Reserve("order-1", []Item{{"A", 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Set("A", 1); err != nil || got != 0 {
+		t.Errorf("got %d, %v after setting A below what is reserved", got, err)
+	}
+	if _, err := s.Set("A", -1); err == nil {
+		t.Error("set a negative stock")
+	}
+	if err := s.Commit("order-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Set("B", 9); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.Restock(); n != 1 {
+		t.Errorf("restocked %d products, want 1", n)
+	}
+	if a, b := s.Available("A"), s.Available("B"); a != 5 || b != 9 {
+		t.Errorf("got A=%d B=%d after restocking, want 5 and 9", a, b)
+	}
+}
+
+func TestReserveConcurrently(t *testing.T) {
+	s, _ := newTestStore(10)
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.Reserve(string(rune('a'+i)), []Item{{"A", 1}}); err == nil {
+				lock.Lock()
+				reserved++
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if reserved != 10 || s.Available("A") != 0 {
+		t.Errorf("reserved %d with %d left, want 10 and 0", reserved, s.Available("A"))
+	}
+}
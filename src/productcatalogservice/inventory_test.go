@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/inventory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestInventoryService(t *testing.T) {
+	old := stock
+	stock = inventory.New(3, time.Minute)
+	defer func() { stock = old }()
+
+	conn, err := grpc.NewClient(run("0"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewInventoryServiceClient(conn)
+	ctx := context.Background()
+
+	levels := func() map[string]int32 {
+		t.Helper()
+		res, err := client.GetStock(ctx, &pb.GetStockRequest{ProductIds: []string{"OLJCESPC7Z", "66VCHSJNUP", "N/A", "OLJCESPC7Z"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := map[string]int32{}
+		for _, l := range res.Levels {
+			out[l.ProductId] = l.Available
+		}
+		return out
+	}
+	if got := levels(); len(got) != 2 || got["OLJCESPC7Z"] != 3 {
+		t.Errorf("got levels %v", got)
+	}
+
+	reserve := func(id string, quantity int32) error {
+		_, err := client.ReserveStock(ctx, &pb.ReserveStockRequest{ReservationId: id, Items: []*pb.CartItem{
+			{ProductId: "OLJCESPC7Z", Quantity: quantity},
+			{ProductId: "66VCHSJNUP", Quantity: 1},
+		}})
+		return err
+	}
+	if err := reserve("order-1", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := reserve("order-2", 2); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("got %v reserving more than is left, want FailedPrecondition", err)
+	}
+	if err := reserve("order-1", 1); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("got %v reusing a reservation ID, want AlreadyExists", err)
+	}
+	if _, err := client.ReserveStock(ctx, &pb.ReserveStockRequest{ReservationId: "order-3", Items: []*pb.CartItem{{ProductId: "N/A", Quantity: 1}}}); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v reserving an unknown product, want NotFound", err)
+	}
+
+	if _, err := client.CommitReservation(ctx, &pb.ReservationRequest{ReservationId: "order-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CommitReservation(ctx, &pb.ReservationRequest{ReservationId: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v committing an unknown reservation, want NotFound", err)
+	}
+	if got := levels(); got["OLJCESPC7Z"] != 1 || got["66VCHSJNUP"] != 2 {
+		t.Errorf("got levels %v after committing", got)
+	}
+	if _, err := client.ReleaseReservation(ctx, &pb.ReservationRequest{ReservationId: "order-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := levels(); got["OLJCESPC7Z"] != 3 {
+		t.Errorf("got levels %v after releasing", got)
+	}
+
+	level, err := client.SetStock(ctx, &pb.SetStockRequest{ProductId: "OLJCESPC7Z", Quantity: 0})
+	if err != nil || level.Available != 0 {
+		t.Errorf("got %v, %v setting the stock", level, err)
+	}
+	if _, err := client.SetStock(ctx, &pb.SetStockRequest{ProductId: "OLJCESPC7Z", Quantity: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v setting a negative stock, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// productIDPattern is the format of the IDs in products.json, which new
+// products must follow too.
+var productIDPattern = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+
+const productIDChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func newProductID() string {
+	b := make([]byte, 10)
+	for i := range b {
+		b[i] = productIDChars[rand.Intn(len(productIDChars))]
+	}
+	return string(b)
+}
+
+// validatePrice checks that m is a valid, non-negative USD amount.
+func validatePrice(m *pb.Money) error {
+	switch {
+	case m == nil:
+		return errors.New("a price is required")
+	case m.GetCurrencyCode() != "USD":
+		return fmt.Errorf("prices must be in USD, not %q", m.GetCurrencyCode())
+	case !validMoney(m):
+		return errors.New("the price is not a valid amount")
+	case negativeMoney(m):
+		return errors.New("the price is negative")
+	}
+	return nil
+}
+
+// validMoney is whether the units and nanos of m have the same sign, and the
+// nanos are less than a unit.
+func validMoney(m *pb.Money) bool {
+	units, nanos := m.GetUnits(), m.GetNanos()
+	return -1e9 < nanos && nanos < 1e9 && (units == 0 || nanos == 0 || (units < 0) == (nanos < 0))
+}
+
+func negativeMoney(m *pb.Money) bool {
+	return m.GetUnits() < 0 || (m.GetUnits() == 0 && m.GetNanos() < 0)
+}
+
+// validateProduct checks a product that a client wants in the catalog.
+func validateProduct(p *pb.Product) error {
+	if strings.TrimSpace(p.GetName()) == "" {
+		return errors.New("a name is required")
+	}
+	if err := validatePrice(p.GetPriceUsd()); err != nil {
+		return err
+	}
+	if len(p.GetCategories()) == 0 {
+		return errors.New("at least one category is required")
+	}
+	seen := make(map[string]bool, len(p.GetCategories()))
+	for _, c := range p.GetCategories() {
+		if strings.TrimSpace(c) == "" {
+			return errors.New("categories can't be empty")
+		}
+		if seen[c] {
+			return fmt.Errorf("category %q is listed twice", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// indexOfProduct returns the position of the product with the given ID, or -1.
+func indexOfProduct(products []*pb.Product, id string) int {
+	for i, p := range products {
+		if p.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateCatalog applies fn to the catalog and records the change on the span
+// and in the log.
+func updateCatalog(ctx context.Context, action, id string, fn func([]*pb.Product) ([]*pb.Product, error)) error {
+	span := trace.SpanFromContext(ctx)
+	s, err := catalog.update(ctx, fn)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(
+		attribute.String("app.product_id", id),
+		attribute.String("app.catalog.version", s.version),
+	)
+	log.WithFields(logrus.Fields{"product_id": id, "version": s.version}).Infof("product %s", action)
+	return nil
+}
+
+func (p *productCatalog) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.Product, error) {
+	if err := admin.Authorize(ctx, p.adminToken); err != nil {
+		return nil, err
+	}
+	if req.GetProduct() == nil {
+		return nil, status.Error(codes.InvalidArgument, "a product is required")
+	}
+	product := proto.Clone(req.GetProduct()).(*pb.Product)
+	if product.Id == "" {
+		product.Id = newProductID()
+	} else if !productIDPattern.MatchString(product.Id) {
+		return nil, status.Errorf(codes.InvalidArgument, "product id %q is not 10 capital letters or digits", product.Id)
+	}
+	if err := validateProduct(product); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err := updateCatalog(ctx, "created", product.Id, func(products []*pb.Product) ([]*pb.Product, error) {
+		if indexOfProduct(products, product.Id) >= 0 {
+			return nil, status.Errorf(codes.AlreadyExists, "product %s already exists", product.Id)
+		}
+		return append(products, product), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (p *productCatalog) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.Product, error) {
+	if err := admin.Authorize(ctx, p.adminToken); err != nil {
+		return nil, err
+	}
+	if req.GetProduct() == nil {
+		return nil, status.Error(codes.InvalidArgument, "a product is required")
+	}
+	product := proto.Clone(req.GetProduct()).(*pb.Product)
+	if err := validateProduct(product); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err := updateCatalog(ctx, "updated", product.Id, func(products []*pb.Product) ([]*pb.Product, error) {
+		i := indexOfProduct(products, product.Id)
+		if i < 0 {
+			return nil, status.Errorf(codes.NotFound, "no product with ID %s", product.Id)
+		}
+		products[i] = product
+		return products, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (p *productCatalog) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.Empty, error) {
+	if err := admin.Authorize(ctx, p.adminToken); err != nil {
+		return nil, err
+	}
+	err := updateCatalog(ctx, "deleted", req.GetId(), func(products []*pb.Product) ([]*pb.Product, error) {
+		i := indexOfProduct(products, req.GetId())
+		if i < 0 {
+			return nil, status.Errorf(codes.NotFound, "no product with ID %s", req.GetId())
+		}
+		if len(products) == 1 {
+			return nil, status.Error(codes.FailedPrecondition, "the last product can't be deleted")
+		}
+		return append(products[:i], products[i+1:]...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (p *productCatalog) SetPrice(ctx context.Context, req *pb.SetPriceRequest) (*pb.Product, error) {
+	if err := admin.Authorize(ctx, p.adminToken); err != nil {
+		return nil, err
+	}
+	if err := validatePrice(req.GetPriceUsd()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var product *pb.Product
+	err := updateCatalog(ctx, "repriced", req.GetId(), func(products []*pb.Product) ([]*pb.Product, error) {
+		i := indexOfProduct(products, req.GetId())
+		if i < 0 {
+			return nil, status.Errorf(codes.NotFound, "no product with ID %s", req.GetId())
+		}
+		product = proto.Clone(products[i]).(*pb.Product)
+		product.PriceUsd = proto.Clone(req.GetPriceUsd()).(*pb.Money)
+		products[i] = product
+		return products, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
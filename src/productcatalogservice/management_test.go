@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/search"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// useTestCatalog serves the catalog in a temporary copy of twoProducts for
+// the rest of the test, and returns the path of the copy.
+func useTestCatalog(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "products.json")
+	writeCatalog(t, path, twoProducts, time.Hour)
+	old := catalog
+	catalog = newTestWatcher(t, path, 0)
+	t.Cleanup(func() { catalog = old })
+	return path
+}
+
+func usd(units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+func TestManageProducts(t *testing.T) {
+	path := useTestCatalog(t)
+	ctx := adminContext("secret")
+	svc := &productCatalog{adminToken: "secret"}
+	version := catalog.snapshot().version
+
+	created, err := svc.CreateProduct(ctx, &pb.CreateProductRequest{Product: &pb.Product{
+		Name: "Teapot", PriceUsd: usd(24, 500000000), Categories: []string{"kitchen"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !productIDPattern.MatchString(created.Id) {
+		t.Errorf("got generated id %q", created.Id)
+	}
+	if _, err := svc.CreateProduct(ctx, &pb.CreateProductRequest{Product: created}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("creating a product twice returned %v, want AlreadyExists", err)
+	}
+
+	updated := proto.Clone(created).(*pb.Product)
+	updated.Description = "Holds six cups."
+	if _, err := svc.UpdateProduct(ctx, &pb.UpdateProductRequest{Product: updated}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetPrice(ctx, &pb.SetPriceRequest{Id: "A", PriceUsd: usd(9, 990000000)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "B"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := catalog.snapshot()
+	if s.version == version {
+		t.Error("the catalog version didn't change")
+	}
+	got, err := svc.GetProduct(ctx, &pb.GetProductRequest{Id: created.Id})
+	if err != nil || got.Description != "Holds six cups." {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if hits := s.index.Search(search.Query{Text: "teapot"}); hits.Total != 1 {
+		t.Errorf("the new product isn't searchable")
+	}
+
+	// the file holds the same catalog, and reloading it changes nothing
+	store, _ := newCatalogStore("json", path)
+	saved, _ := loadAll(t, store)
+	if diff := cmp.Diff(s.products, saved, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("saved catalog (-served +saved):\n%s", diff)
+	}
+	if err := catalog.reload(ctx, false); err != nil || catalog.snapshot() != s {
+		t.Errorf("reloading after a save replaced the snapshot, err %v", err)
+	}
+
+	for name, err := range map[string]error{
+		"update missing": func() error {
+			_, err := svc.UpdateProduct(ctx, &pb.UpdateProductRequest{Product: &pb.Product{Id: "NOSUCHPROD", Name: "X", PriceUsd: usd(1, 0), Categories: []string{"x"}}})
+			return err
+		}(),
+		"reprice missing": func() error {
+			_, err := svc.SetPrice(ctx, &pb.SetPriceRequest{Id: "NOSUCHPROD", PriceUsd: usd(1, 0)})
+			return err
+		}(),
+		"delete missing": func() error {
+			_, err := svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "NOSUCHPROD"})
+			return err
+		}(),
+	} {
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("%s: got %v, want NotFound", name, err)
+		}
+	}
+}
+
+func TestManageProductsValidates(t *testing.T) {
+	useTestCatalog(t)
+	ctx := adminContext("secret")
+	svc := &productCatalog{adminToken: "secret"}
+	valid := func() *pb.Product {
+		return &pb.Product{Id: "TEAPOT0001", Name: "Teapot", PriceUsd: usd(24, 0), Categories: []string{"kitchen"}}
+	}
+	for name, change := range map[string]func(p *pb.Product){
+		"lowercase id":       func(p *pb.Product) { p.Id = "teapot0001" },
+		"short id":           func(p *pb.Product) { p.Id = "TEAPOT" },
+		"no name":            func(p *pb.Product) { p.Name = " " },
+		"no price":           func(p *pb.Product) { p.PriceUsd = nil },
+		"euros":              func(p *pb.Product) { p.PriceUsd.CurrencyCode = "EUR" },
+		"negative price":     func(p *pb.Product) { p.PriceUsd = usd(-1, 0) },
+		"mixed signs":        func(p *pb.Product) { p.PriceUsd = usd(1, -5) },
+		"nanos out of range": func(p *pb.Product) { p.PriceUsd = usd(1, 1e9) },
+		"no categories":      func(p *pb.Product) { p.Categories = nil },
+		"empty category":     func(p *pb.Product) { p.Categories = []string{"kitchen", ""} },
+		"repeated category":  func(p *pb.Product) { p.Categories = []string{"kitchen", "kitchen"} },
+	} {
+		p := valid()
+		change(p)
+		if _, err := svc.CreateProduct(ctx, &pb.CreateProductRequest{Product: p}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", name, err)
+		}
+	}
+	if _, err := svc.SetPrice(ctx, &pb.SetPriceRequest{Id: "A", PriceUsd: usd(0, -1)}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative price: got %v, want InvalidArgument", err)
+	}
+	if _, err := svc.CreateProduct(ctx, &pb.CreateProductRequest{Product: valid()}); err != nil {
+		t.Errorf("a valid product was rejected: %v", err)
+	}
+
+	_, _ = svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "B"})
+	_, _ = svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "TEAPOT0001"})
+	if _, err := svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "A"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("deleting the last product returned %v, want FailedPrecondition", err)
+	}
+}
+
+func TestManageProductsNeedsWritableStore(t *testing.T) {
+	old := catalog
+	defer func() { catalog = old }()
+	catalog = newCatalogWatcher(&httpCatalogStore{url: "http://catalog.invalid"}, 0)
+	_, err := (&productCatalog{adminToken: "secret"}).SetPrice(adminContext("secret"), &pb.SetPriceRequest{Id: "A", PriceUsd: usd(1, 0)})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestManageProductsRequiresToken(t *testing.T) {
+	useTestCatalog(t)
+	version := catalog.snapshot().version
+	svc := &productCatalog{adminToken: "secret"}
+	for name, ctx := range map[string]context.Context{"no token": context.Background(), "wrong token": adminContext("wrong")} {
+		if _, err := svc.CreateProduct(ctx, &pb.CreateProductRequest{Product: &pb.Product{Name: "Teapot", PriceUsd: usd(1, 0), Categories: []string{"kitchen"}}}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("CreateProduct with %s: got %v, want Unauthenticated", name, err)
+		}
+		if _, err := svc.UpdateProduct(ctx, &pb.UpdateProductRequest{Product: &pb.Product{Id: "A", Name: "A", PriceUsd: usd(1, 0), Categories: []string{"kitchen"}}}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("UpdateProduct with %s: got %v, want Unauthenticated", name, err)
+		}
+		if _, err := svc.SetPrice(ctx, &pb.SetPriceRequest{Id: "A", PriceUsd: usd(0, 1)}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("SetPrice with %s: got %v, want Unauthenticated", name, err)
+		}
+		if _, err := svc.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: "B"}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("DeleteProduct with %s: got %v, want Unauthenticated", name, err)
+		}
+	}
+	if _, err := (&productCatalog{}).SetPrice(adminContext("secret"), &pb.SetPriceRequest{Id: "A", PriceUsd: usd(0, 1)}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("SetPrice without ADMIN_TOKEN: got %v, want PermissionDenied", err)
+	}
+	if catalog.snapshot().version != version {
+		t.Error("an unauthorized call changed the catalog")
+	}
+}
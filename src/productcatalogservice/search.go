@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/search"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultSearchPageSize = 10
+	maxSearchPageSize     = 100
+)
+
+// pageToken is the position of the next page of a search. It carries a hash
+// of the search so a token can't be used to page through a different one.
+type pageToken struct {
+	Offset int    `json:"o"`
+	Search uint64 `json:"s"`
+}
+
+func (t pageToken) String() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parsePageToken(s string) (pageToken, error) {
+	var t pageToken
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &t)
+	}
+	if err != nil || t.Offset < 0 {
+		return pageToken{}, fmt.Errorf("invalid page token %q", s)
+	}
+	return t, nil
+}
+
+// searchHash identifies a search by everything but its paging.
+func searchHash(req *pb.SearchProductsRequest) uint64 {
+	req = proto.Clone(req).(*pb.SearchProductsRequest)
+	req.PageSize, req.PageToken = 0, ""
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+func (p *productCatalog) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("app.search.query", req.GetQuery()),
+		attribute.StringSlice("app.search.categories", req.GetCategories()),
+		attribute.String("app.search.sort", req.GetSort().String()),
+	)
+
+	size := int(req.GetPageSize())
+	if size < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page size %d is negative", size)
+	} else if size == 0 {
+		size = defaultSearchPageSize
+	} else if size > maxSearchPageSize {
+		size = maxSearchPageSize
+	}
+	hash := searchHash(req)
+	var offset int
+	if req.GetPageToken() != "" {
+		t, err := parsePageToken(req.GetPageToken())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if t.Search != hash {
+			return nil, status.Error(codes.InvalidArgument, "page token is from a different search")
+		}
+		offset = t.Offset
+	}
+	if req.GetMinPrice() != nil && req.GetMaxPrice() != nil &&
+		req.GetMinPrice().GetUnits()*1e9+int64(req.GetMinPrice().GetNanos()) > req.GetMaxPrice().GetUnits()*1e9+int64(req.GetMaxPrice().GetNanos()) {
+		return nil, status.Error(codes.InvalidArgument, "min price is above max price")
+	}
+
+	snapshot, err := requestCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := snapshot.index
+
+	tracer := otel.GetTracerProvider().Tracer("")
+	_, searchSpan := tracer.Start(ctx, "search index")
+	res := idx.Search(search.Query{
+		Text:       req.GetQuery(),
+		Categories: req.GetCategories(),
+		MinPrice:   req.GetMinPrice(),
+		MaxPrice:   req.GetMaxPrice(),
+		Sort:       req.GetSort(),
+		Offset:     offset,
+		Limit:      size,
+	})
+	searchSpan.SetAttributes(
+		attribute.Int("app.search.indexed", idx.Len()),
+		attribute.Int("app.search.total", res.Total),
+	)
+	searchSpan.End()
+
+	out := &pb.SearchProductsResponse{
+		Results:        res.Products,
+		TotalSize:      int32(res.Total),
+		CategoryFacets: res.Categories,
+		PriceFacets:    res.Prices,
+	}
+	if next := offset + len(res.Products); next < res.Total {
+		out.NextPageToken = pageToken{Offset: next, Search: hash}.String()
+	}
+	span.SetAttributes(
+		attribute.Int("app.search.results", len(res.Products)),
+		attribute.Int("app.search.total", res.Total),
+		attribute.Int("app.search.offset", offset),
+	)
+	return out, nil
+}
@@ -0,0 +1,399 @@
+// Package search is an in-memory full-text index of the product catalog.
+//
+// The index is built once per catalog load. It maps every word in a product's
+// name, categories and description to the products that contain it, so a
+// search looks up the words of the query instead of scanning every product.
+// Query words match indexed words exactly, by prefix, or within a small edit
+// distance, and results are ranked by how well and where they matched: a
+// name match counts more than a category match, which counts more than one in
+// the description, and rare words count more than common ones.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+)
+
+// Weights of a word by the field it appears in.
+const (
+	nameWeight        = 3
+	categoryWeight    = 2
+	descriptionWeight = 1
+)
+
+// How much a match counts, relative to an exact one.
+const (
+	prefixQuality = 0.5 // plus up to 0.5 for how much of the word the prefix covers
+	fuzzyQuality  = 0.6 // less 0.2 for every edit past the first
+)
+
+// DefaultPriceRanges are the bounds, in USD, of the price facets.
+var DefaultPriceRanges = []int64{25, 50, 100}
+
+// stopWords are too common to be worth indexing or searching for.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "any": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "like": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "with": true, "you": true, "your": true,
+}
+
+// Tokenize splits s into lowercase words, leaving out stop words.
+func Tokenize(s string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Query is a search of the index.
+type Query struct {
+	// Text is the words to search for. Every word must match. Text without
+	// any words to search for matches every product.
+	Text string
+	// Categories limit the results to products in any of them.
+	Categories []string
+	// MinPrice and MaxPrice limit the results to products whose USD price is
+	// within them, inclusive. Either can be nil.
+	MinPrice *pb.Money
+	MaxPrice *pb.Money
+	Sort     pb.SearchSortOrder
+	// Offset and Limit select a page of the results. A Limit of 0 returns them all.
+	Offset int
+	Limit  int
+}
+
+// Result is a page of results and the facets of the whole search.
+type Result struct {
+	Products []*pb.Product
+	// Scores are the relevance of each of Products.
+	Scores []float64
+	// Total is the number of results on all pages.
+	Total int
+	// Categories count the results by category, ignoring Query.Categories.
+	Categories []*pb.CategoryFacet
+	// Prices count the results by price range, ignoring the price filter.
+	Prices []*pb.PriceFacet
+}
+
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// Index is a searchable set of products. It is safe for concurrent use, and
+// must be rebuilt when the products change.
+type Index struct {
+	products []*pb.Product
+	prices   []int64 // USD nanos
+	names    []string
+	postings map[string][]posting
+	// terms are the keys of postings, sorted for prefix lookups
+	terms  []string
+	ranges []int64 // nanos
+}
+
+// NewIndex indexes products, with price facets split at DefaultPriceRanges.
+func NewIndex(products []*pb.Product) *Index {
+	idx := &Index{
+		products: products,
+		prices:   make([]int64, len(products)),
+		names:    make([]string, len(products)),
+		postings: map[string][]posting{},
+	}
+	for _, r := range DefaultPriceRanges {
+		idx.ranges = append(idx.ranges, r*1e9)
+	}
+	for doc, p := range products {
+		idx.prices[doc] = nanos(p.GetPriceUsd())
+		idx.names[doc] = strings.ToLower(p.GetName())
+
+		weights := map[string]float64{}
+		for _, w := range Tokenize(p.GetName()) {
+			weights[w] += nameWeight
+		}
+		for _, c := range p.GetCategories() {
+			for _, w := range Tokenize(c) {
+				weights[w] += categoryWeight
+			}
+		}
+		for _, w := range Tokenize(p.GetDescription()) {
+			weights[w] += descriptionWeight
+		}
+		for w, weight := range weights {
+			idx.postings[w] = append(idx.postings[w], posting{doc: doc, weight: weight})
+		}
+	}
+	idx.terms = make([]string, 0, len(idx.postings))
+	for t := range idx.postings {
+		idx.terms = append(idx.terms, t)
+	}
+	sort.Strings(idx.terms)
+	return idx
+}
+
+// Len returns the number of products in the index.
+func (idx *Index) Len() int {
+	return len(idx.products)
+}
+
+// Search runs q against the index.
+func (idx *Index) Search(q Query) Result {
+	scores := idx.match(Tokenize(q.Text))
+
+	// each facet ignores its own filter, so apply the two separately
+	inCategories := make(map[int]bool, len(scores))
+	inPrice := make(map[int]bool, len(scores))
+	for doc := range scores {
+		inCategories[doc] = idx.inCategories(doc, q.Categories)
+		inPrice[doc] = idx.inPrice(doc, q.MinPrice, q.MaxPrice)
+	}
+
+	var docs []int
+	categories := map[string]int{}
+	prices := make([]int, len(idx.ranges)+1)
+	for doc := range scores {
+		if inPrice[doc] {
+			for _, c := range idx.products[doc].GetCategories() {
+				categories[strings.ToLower(c)]++
+			}
+		}
+		if inCategories[doc] {
+			prices[idx.priceRange(doc)]++
+		}
+		if inCategories[doc] && inPrice[doc] {
+			docs = append(docs, doc)
+		}
+	}
+	idx.sort(docs, scores, q.Sort)
+
+	res := Result{
+		Total:      len(docs),
+		Categories: categoryFacets(categories),
+		Prices:     idx.priceFacets(prices),
+	}
+	if q.Offset > len(docs) {
+		q.Offset = len(docs)
+	}
+	docs = docs[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(docs) {
+		docs = docs[:q.Limit]
+	}
+	for _, doc := range docs {
+		res.Products = append(res.Products, idx.products[doc])
+		res.Scores = append(res.Scores, scores[doc])
+	}
+	return res
+}
+
+// match returns the products that match every word, and their scores.
+func (idx *Index) match(words []string) map[int]float64 {
+	scores := make(map[int]float64, len(idx.products))
+	if len(words) == 0 {
+		for doc := range idx.products {
+			scores[doc] = 0
+		}
+		return scores
+	}
+	for i, w := range words {
+		// a word scores its best match in each product
+		best := map[int]float64{}
+		for term, quality := range idx.expand(w) {
+			idf := math.Log(1 + float64(len(idx.products))/float64(len(idx.postings[term])))
+			for _, p := range idx.postings[term] {
+				if s := quality * p.weight * idf; s > best[p.doc] {
+					best[p.doc] = s
+				}
+			}
+		}
+		if i == 0 {
+			scores = best
+			continue
+		}
+		for doc := range scores {
+			if s, ok := best[doc]; ok {
+				scores[doc] += s
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+	return scores
+}
+
+// expand returns the indexed terms that w matches, and how well.
+func (idx *Index) expand(w string) map[string]float64 {
+	out := map[string]float64{}
+	if _, ok := idx.postings[w]; ok {
+		out[w] = 1
+	}
+	if len(w) >= 2 {
+		for i := sort.SearchStrings(idx.terms, w); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], w); i++ {
+			if t := idx.terms[i]; t != w {
+				out[t] = prefixQuality + 0.5*float64(len(w))/float64(len(t))
+			}
+		}
+	}
+	if max := maxEdits(w); max > 0 {
+		for _, t := range idx.terms {
+			if _, ok := out[t]; ok || abs(len(t)-len(w)) > max {
+				continue
+			}
+			if d := distance(w, t, max); d <= max {
+				out[t] = fuzzyQuality - 0.2*float64(d-1)
+			}
+		}
+	}
+	return out
+}
+
+// maxEdits is how many typos a word of w's length may have.
+func maxEdits(w string) int {
+	switch n := len([]rune(w)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance returns the optimal string alignment distance between a and b, or
+// max+1 once it is known to be more than max.
+func distance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+func (idx *Index) inCategories(doc int, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, c := range idx.products[doc].GetCategories() {
+		for _, want := range categories {
+			if strings.EqualFold(c, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (idx *Index) inPrice(doc int, minPrice, maxPrice *pb.Money) bool {
+	if minPrice != nil && idx.prices[doc] < nanos(minPrice) {
+		return false
+	}
+	return maxPrice == nil || idx.prices[doc] <= nanos(maxPrice)
+}
+
+func (idx *Index) priceRange(doc int) int {
+	return sort.Search(len(idx.ranges), func(i int) bool { return idx.prices[doc] < idx.ranges[i] })
+}
+
+// sort orders docs by the sort order, breaking ties by catalog order.
+func (idx *Index) sort(docs []int, scores map[int]float64, order pb.SearchSortOrder) {
+	sort.Slice(docs, func(i, j int) bool {
+		a, b := docs[i], docs[j]
+		switch order {
+		case pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_ASCENDING:
+			if idx.prices[a] != idx.prices[b] {
+				return idx.prices[a] < idx.prices[b]
+			}
+		case pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_DESCENDING:
+			if idx.prices[a] != idx.prices[b] {
+				return idx.prices[a] > idx.prices[b]
+			}
+		case pb.SearchSortOrder_SEARCH_SORT_ORDER_NAME:
+			if idx.names[a] != idx.names[b] {
+				return idx.names[a] < idx.names[b]
+			}
+		default:
+			if scores[a] != scores[b] {
+				return scores[a] > scores[b]
+			}
+		}
+		return a < b
+	})
+}
+
+func categoryFacets(counts map[string]int) []*pb.CategoryFacet {
+	out := make([]*pb.CategoryFacet, 0, len(counts))
+	for c, n := range counts {
+		out = append(out, &pb.CategoryFacet{Category: c, Count: int32(n)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
+
+// priceFacets describes the ranges with any results.
+func (idx *Index) priceFacets(counts []int) []*pb.PriceFacet {
+	var out []*pb.PriceFacet
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		f := &pb.PriceFacet{Min: usd(0), Count: int32(n)}
+		if i > 0 {
+			f.Min = usd(idx.ranges[i-1])
+		}
+		if i < len(idx.ranges) {
+			f.Max = usd(idx.ranges[i])
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func nanos(m *pb.Money) int64 {
+	return m.GetUnits()*1e9 + int64(m.GetNanos())
+}
+
+func usd(n int64) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: n / 1e9, Nanos: int32(n % 1e9)}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
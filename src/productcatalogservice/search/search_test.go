@@ -0,0 +1,164 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+)
+
+func usdPrice(units int64) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: units}
+}
+
+func testIndex() *Index {
+	return NewIndex([]*pb.Product{
+		{Id: "TYPE", Name: "Vintage Typewriter", Description: "This typewriter looks good in your living room.", Categories: []string{"vintage"}, PriceUsd: usdPrice(67)},
+		{Id: "CAM", Name: "Vintage Camera Lens", Description: "You won't have a camera to use it and it probably doesn't work anyway.", Categories: []string{"photography", "vintage"}, PriceUsd: usdPrice(12)},
+		{Id: "PLANT", Name: "Terrarium", Description: "This terrarium will look great in your white painted living room.", Categories: []string{"gardening"}, PriceUsd: usdPrice(36)},
+		{Id: "BIKE", Name: "City Bike", Description: "This single gear bike probably cannot climb the hills of San Francisco.", Categories: []string{"cycling"}, PriceUsd: usdPrice(789)},
+		{Id: "RECORD", Name: "Record Player", Description: "It still works. A vintage piece for the living room.", Categories: []string{"music", "vintage"}, PriceUsd: usdPrice(65)},
+	})
+}
+
+func ids(res Result) []string {
+	var out []string
+	for _, p := range res.Products {
+		out = append(out, p.Id)
+	}
+	return out
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Vintage-Typewriter, for YOUR living room!")
+	want := []string{"vintage", "typewriter", "living", "room"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex()
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		// name matches outrank categories, which outrank descriptions
+		{"vintage", []string{"TYPE", "CAM", "RECORD"}},
+		// ties keep catalog order
+		{"living room", []string{"TYPE", "PLANT", "RECORD"}},
+		// every word has to match
+		{"vintage camera", []string{"CAM"}},
+		{"vintage bike", nil},
+		// prefixes and typos
+		{"typew", []string{"TYPE"}},
+		{"terarium", []string{"PLANT"}},
+		{"typewritter", []string{"TYPE"}},
+		{"bkie", []string{"BIKE"}},
+		// short words have to be exact
+		{"cty", nil},
+		// no words matches everything, in catalog order
+		{"", []string{"TYPE", "CAM", "PLANT", "BIKE", "RECORD"}},
+		{"the", []string{"TYPE", "CAM", "PLANT", "BIKE", "RECORD"}},
+	} {
+		if got := ids(idx.Search(Query{Text: tc.query})); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestSearchExactBeatsFuzzy(t *testing.T) {
+	idx := NewIndex([]*pb.Product{
+		{Id: "MUG", Name: "Mugs", PriceUsd: usdPrice(1)},
+		{Id: "RUG", Name: "Rugs", PriceUsd: usdPrice(1)},
+	})
+	res := idx.Search(Query{Text: "rugs"})
+	if got := ids(res); !reflect.DeepEqual(got, []string{"RUG", "MUG"}) {
+		t.Fatalf("got %v, want the exact match first", got)
+	}
+	if res.Scores[0] <= res.Scores[1] {
+		t.Errorf("got scores %v, want the exact match to score higher", res.Scores)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	idx := testIndex()
+	res := idx.Search(Query{Categories: []string{"Vintage", "gardening"}, MinPrice: usdPrice(36), MaxPrice: &pb.Money{CurrencyCode: "USD", Units: 65}})
+	if got := ids(res); !reflect.DeepEqual(got, []string{"PLANT", "RECORD"}) {
+		t.Errorf("got %v, want the terrarium and the record player", got)
+	}
+	if res.Total != 2 {
+		t.Errorf("got a total of %d, want 2", res.Total)
+	}
+}
+
+func TestSearchFacets(t *testing.T) {
+	idx := testIndex()
+	res := idx.Search(Query{Text: "vintage", Categories: []string{"music"}, MaxPrice: usdPrice(50)})
+	if res.Total != 0 {
+		t.Errorf("got %v, want no results", ids(res))
+	}
+	// categories of the vintage products under 50, prices of the vintage music products
+	var categories, prices []string
+	for _, f := range res.Categories {
+		categories = append(categories, fmt.Sprintf("%s=%d", f.Category, f.Count))
+	}
+	for _, f := range res.Prices {
+		prices = append(prices, fmt.Sprintf("%d-%d=%d", f.Min.GetUnits(), f.Max.GetUnits(), f.Count))
+	}
+	if want := []string{"photography=1", "vintage=1"}; !reflect.DeepEqual(categories, want) {
+		t.Errorf("got category facets %v, want %v", categories, want)
+	}
+	if want := []string{"50-100=1"}; !reflect.DeepEqual(prices, want) {
+		t.Errorf("got price facets %v, want %v", prices, want)
+	}
+
+	res = idx.Search(Query{})
+	last := res.Prices[len(res.Prices)-1]
+	if last.Min.GetUnits() != 100 || last.Max != nil || last.Count != 1 {
+		t.Errorf("got top price facet %v, want 100 and up with the bike", last)
+	}
+}
+
+func TestSearchSortAndPage(t *testing.T) {
+	idx := testIndex()
+	for _, tc := range []struct {
+		sort pb.SearchSortOrder
+		want []string
+	}{
+		{pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_ASCENDING, []string{"CAM", "PLANT", "RECORD", "TYPE", "BIKE"}},
+		{pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_DESCENDING, []string{"BIKE", "TYPE", "RECORD", "PLANT", "CAM"}},
+		{pb.SearchSortOrder_SEARCH_SORT_ORDER_NAME, []string{"BIKE", "RECORD", "PLANT", "CAM", "TYPE"}},
+	} {
+		if got := ids(idx.Search(Query{Sort: tc.sort})); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.sort, got, tc.want)
+		}
+	}
+
+	res := idx.Search(Query{Sort: pb.SearchSortOrder_SEARCH_SORT_ORDER_NAME, Offset: 3, Limit: 3})
+	if got := ids(res); !reflect.DeepEqual(got, []string{"CAM", "TYPE"}) || res.Total != 5 {
+		t.Errorf("got page %v of %d, want the last two of 5", got, res.Total)
+	}
+	if res := idx.Search(Query{Offset: 10}); len(res.Products) != 0 || res.Total != 5 {
+		t.Errorf("got %v past the end", ids(res))
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"bike", "bike", 0},
+		{"bkie", "bike", 1},
+		{"terarium", "terrarium", 1},
+		{"typewritter", "typewriter", 1},
+		{"camera", "cmaeta", 2},
+		{"camera", "lens", 3},
+	} {
+		if got := distance(tc.a, tc.b, 2); got != tc.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSearchProductsPages(t *testing.T) {
+	ctx := context.Background()
+	svc := &productCatalog{}
+	req := &pb.SearchProductsRequest{PageSize: 5, Sort: pb.SearchSortOrder_SEARCH_SORT_ORDER_PRICE_ASCENDING}
+
+	var seen []*pb.Product
+	for page := 0; ; page++ {
+		res, err := svc.SearchProducts(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(res.TotalSize) != len(catalog.snapshot().products) {
+			t.Errorf("page %d: got a total of %d, want every product", page, res.TotalSize)
+		}
+		seen = append(seen, res.Results...)
+		if res.NextPageToken == "" {
+			break
+		}
+		if page > len(catalog.snapshot().products) {
+			t.Fatal("paging doesn't end")
+		}
+		req.PageToken = res.NextPageToken
+	}
+	if len(seen) != len(catalog.snapshot().products) {
+		t.Fatalf("paged through %d products, want %d", len(seen), len(catalog.snapshot().products))
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i].PriceUsd.Units < seen[i-1].PriceUsd.Units {
+			t.Errorf("%s is cheaper than %s before it", seen[i].Id, seen[i-1].Id)
+		}
+	}
+}
+
+func TestSearchProductsRanksAndFilters(t *testing.T) {
+	res, err := (&productCatalog{}).SearchProducts(context.Background(), &pb.SearchProductsRequest{
+		Query:      "typewritter",
+		Categories: []string{"vintage"},
+		MaxPrice:   &pb.Money{CurrencyCode: "USD", Units: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) == 0 || res.Results[0].Id != "OLJCESPC7Z" {
+		t.Errorf("got %v, want the typewriter first", res.Results)
+	}
+	if len(res.CategoryFacets) == 0 || len(res.PriceFacets) == 0 {
+		t.Errorf("got no facets: %v", res)
+	}
+}
+
+func TestSearchProductsRejectsBadRequests(t *testing.T) {
+	ctx := context.Background()
+	svc := &productCatalog{}
+	first, err := svc.SearchProducts(ctx, &pb.SearchProductsRequest{PageSize: 1})
+	if err != nil || first.NextPageToken == "" {
+		t.Fatalf("got %v, %v, want a next page", first, err)
+	}
+
+	for name, req := range map[string]*pb.SearchProductsRequest{
+		"garbage token":  {PageToken: "not a token"},
+		"other search":   {Query: "mug", PageToken: first.NextPageToken},
+		"negative size":  {PageSize: -1},
+		"inverted price": {MinPrice: &pb.Money{Units: 50}, MaxPrice: &pb.Money{Units: 10}},
+	} {
+		if _, err := svc.SearchProducts(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", name, err)
+		}
+	}
+}
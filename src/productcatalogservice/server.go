@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"flag"
 	"fmt"
@@ -13,20 +12,18 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
-	"io/ioutil"
 	"math/rand"
 	"net"
 	"os"
 	"os/signal"
-	"strings"
-	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/productcatalogservice/inventory"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
-	"github.com/golang/protobuf/jsonpb"
 	"github.com/sirupsen/logrus"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -37,13 +34,19 @@ import (
 )
 
 var (
-	cat          pb.ListProductsResponse
-	catalogMutex *sync.Mutex
-	log          *logrus.Logger
+	log *logrus.Logger
 
 	port = "3550"
 
-	reloadCatalog bool
+	// catalog serves the products in the store chosen by CATALOG_STORE.
+	catalog *catalogWatcher
+
+	// stock is the inventory of the products in the catalog.
+	stock = inventory.New(defaultInitialStock, defaultReservationTTL)
+
+	// reloadCatalog makes every request re-read the catalog file. It is
+	// toggled with SIGUSR1/SIGUSR2 or the reload_catalog admin setting.
+	reloadCatalog atomic.Bool
 )
 
 func init() {
@@ -57,13 +60,37 @@ func init() {
 		TimestampFormat: time.RFC3339Nano,
 	}
 	log.Out = os.Stdout
-	catalogMutex = &sync.Mutex{}
-	err := readCatalogFile(&cat)
+	store, err := newCatalogStoreFromEnv()
 	if err != nil {
-		log.Warnf("could not parse product catalog")
+		log.Fatal(err)
+	}
+	catalog = newCatalogWatcher(store, 5*time.Second)
+	if err := catalog.reload(context.Background(), false); err != nil {
+		log.Warnf("could not load product catalog: %v", err)
 	}
 }
 
+// newCatalogStoreFromEnv creates the store named by CATALOG_STORE, at
+// CATALOG_FILE, CATALOG_DB or CATALOG_URL.
+func newCatalogStoreFromEnv() (CatalogStore, error) {
+	kind := os.Getenv("CATALOG_STORE")
+	var location string
+	switch kind {
+	case "", "json":
+		location = "products.json"
+	case "yaml":
+		location = "products.yaml"
+	case "sqlite":
+		location = "products.db"
+	}
+	for _, env := range []string{"CATALOG_FILE", "CATALOG_DB", "CATALOG_URL"} {
+		if v := os.Getenv(env); v != "" {
+			location = v
+		}
+	}
+	return newCatalogStore(kind, location)
+}
+
 func initOtelTracing(ctx context.Context, log logrus.FieldLogger) *sdktrace.TracerProvider {
 	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 	if endpoint == "" {
@@ -118,15 +145,35 @@ func main() {
 			sig := <-sigs
 			log.Printf("Received signal: %s", sig)
 			if sig == syscall.SIGUSR1 {
-				reloadCatalog = true
+				reloadCatalog.Store(true)
 				log.Infof("Enable catalog reloading")
 			} else {
-				reloadCatalog = false
+				reloadCatalog.Store(false)
 				log.Infof("Disable catalog reloading")
 			}
 		}
 	}()
 
+	if v := os.Getenv("CATALOG_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid CATALOG_POLL_INTERVAL %q: %v", v, err)
+		}
+		catalog.interval = interval
+	}
+	catalog.start()
+	defer catalog.shutdown()
+	defer catalog.store.Close()
+
+	var restock time.Duration
+	var err error
+	if stock, restock, err = newStockFromEnv(); err != nil {
+		log.Fatal(err)
+	}
+	if restock > 0 {
+		go restockEvery(restock)
+	}
+
 	if os.Getenv("PORT") != "" {
 		port = os.Getenv("PORT")
 	}
@@ -146,45 +193,23 @@ func run(port string) string {
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 
-	svc := &productCatalog{}
+	token := os.Getenv("ADMIN_TOKEN")
+	svc := &productCatalog{adminToken: token}
 
 	pb.RegisterProductCatalogServiceServer(srv, svc)
 	healthpb.RegisterHealthServer(srv, svc)
+	pb.RegisterAdminServiceServer(srv, newAdminService(token))
+	pb.RegisterInventoryServiceServer(srv, &inventoryService{})
 	go func() {
 		_ = srv.Serve(l)
 	}()
 	return l.Addr().String()
 }
 
-type productCatalog struct{}
-
-func readCatalogFile(catalog *pb.ListProductsResponse) error {
-	catalogMutex.Lock()
-	defer catalogMutex.Unlock()
-	catalogJSON, err := ioutil.ReadFile("products.json")
-	if err != nil {
-		log.Fatalf("failed to open product catalog json file: %v", err)
-		return err
-	}
-	if err := jsonpb.Unmarshal(bytes.NewReader(catalogJSON), catalog); err != nil {
-		log.Warnf("failed to parse the catalog JSON: %v", err)
-		return err
-	}
-	log.Info("successfully parsed product catalog json")
-
-	sleepRandom(50)
-
-	return nil
-}
-
-func parseCatalog() []*pb.Product {
-	if reloadCatalog || len(cat.Products) == 0 {
-		err := readCatalogFile(&cat)
-		if err != nil {
-			return []*pb.Product{}
-		}
-	}
-	return cat.Products
+type productCatalog struct {
+	// adminToken guards the RPCs that change the catalog, like the
+	// AdminService's.
+	adminToken string
 }
 
 func getRandomWaitTime(max int, buckets int) float32 {
@@ -201,16 +226,6 @@ func sleepRandom(max int) {
 	time.Sleep((time.Duration(rnd)) * time.Millisecond)
 }
 
-func mockDatabaseCall(ctx context.Context, maxTime int, name, query string) {
-	tracer := otel.GetTracerProvider().Tracer("")
-	ctx, span := tracer.Start(ctx, name)
-	span.SetAttributes(attribute.String("db.statement", query),
-		attribute.String("db.name", "productcatalog"))
-	defer span.End()
-
-	sleepRandom(maxTime)
-}
-
 func (p *productCatalog) Check(_ context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
 	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
 }
@@ -220,39 +235,57 @@ func (p *productCatalog) Watch(_ *healthpb.HealthCheckRequest, _ healthpb.Health
 }
 
 func (p *productCatalog) ListProducts(ctx context.Context, _ *pb.Empty) (*pb.ListProductsResponse, error) {
-	mockDatabaseCall(ctx, 40, "SELECT productcatalog.products", "SELECT * FROM products")
-	return &pb.ListProductsResponse{Products: parseCatalog()}, nil
+	s, err := requestCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListProductsResponse{Products: s.products}, nil
 }
 
 func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(attribute.String("app.product_id", req.GetId()))
 
-	//mockDatabaseCall(ctx, 30, "SELECT productcatalog.products", "SELECT * FROM products WHERE product_id = ?")
 	sleepRandom(30)
 
-	var found *pb.Product
-	for i := 0; i < len(parseCatalog()); i++ {
-		if req.Id == parseCatalog()[i].Id {
-			found = parseCatalog()[i]
-		}
+	s, err := requestCatalog(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if found == nil {
+	found, ok := s.byID[req.Id]
+	if !ok {
 		return nil, status.Errorf(codes.NotFound, "no product with ID %s", req.Id)
 	}
 	return found, nil
 }
 
-func (p *productCatalog) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
-	mockDatabaseCall(ctx, 50, "SELECT productcatalog.products", "SELECT * FROM products WHERE name LIKE ? OR description LIKE ?")
+func (p *productCatalog) GetProducts(ctx context.Context, req *pb.GetProductsRequest) (*pb.GetProductsResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.StringSlice("app.product_ids", req.GetIds()),
+		attribute.Int("app.products.requested", len(req.GetIds())),
+	)
 
-	// Interpret query as a substring match in name or description.
-	var ps []*pb.Product
-	for _, p := range parseCatalog() {
-		if strings.Contains(strings.ToLower(p.Name), strings.ToLower(req.Query)) ||
-			strings.Contains(strings.ToLower(p.Description), strings.ToLower(req.Query)) {
-			ps = append(ps, p)
+	s, err := requestCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.GetProductsResponse{}
+	seen := make(map[string]bool, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if p, ok := s.byID[id]; ok {
+			res.Products = append(res.Products, p)
+		} else {
+			res.MissingIds = append(res.MissingIds, id)
 		}
 	}
-	return &pb.SearchProductsResponse{Results: ps}, nil
+	span.SetAttributes(
+		attribute.Int("app.products.found", len(res.Products)),
+		attribute.StringSlice("app.products.missing", res.MissingIds),
+	)
+	return res, nil
 }
@@ -29,7 +29,8 @@ func TestServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if diff := cmp.Diff(res.Products, parseCatalog(), cmp.Comparer(proto.Equal)); diff != "" {
+	products := catalog.snapshot().byID
+	if diff := cmp.Diff(res.Products, catalog.snapshot().products, cmp.Comparer(proto.Equal)); diff != "" {
 		t.Error(diff)
 	}
 
@@ -37,7 +38,7 @@ func TestServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if want := parseCatalog()[0]; !proto.Equal(got, want) {
+	if want := products["OLJCESPC7Z"]; !proto.Equal(got, want) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 	_, err = client.GetProduct(ctx, &pb.GetProductRequest{Id: "N/A"})
@@ -49,7 +50,34 @@ func TestServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if diff := cmp.Diff(sres.Results, []*pb.Product{parseCatalog()[0]}, cmp.Comparer(proto.Equal)); diff != "" {
+	if diff := cmp.Diff(sres.Results, []*pb.Product{products["OLJCESPC7Z"]}, cmp.Comparer(proto.Equal)); diff != "" {
 		t.Error(diff)
 	}
+
+	info, err := client.GetCatalogInfo(ctx, &pb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != catalog.snapshot().version || int(info.ProductCount) != len(products) {
+		t.Errorf("got catalog info %v", info)
+	}
+}
+
+func TestGetProducts(t *testing.T) {
+	res, err := (&productCatalog{}).GetProducts(context.Background(), &pb.GetProductsRequest{
+		Ids: []string{"66VCHSJNUP", "N/A", "OLJCESPC7Z", "66VCHSJNUP"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, p := range res.Products {
+		ids = append(ids, p.Id)
+	}
+	if diff := cmp.Diff([]string{"66VCHSJNUP", "OLJCESPC7Z"}, ids); diff != "" {
+		t.Errorf("products (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"N/A"}, res.MissingIds); diff != "" {
+		t.Errorf("missing ids (-want +got):\n%s", diff)
+	}
 }
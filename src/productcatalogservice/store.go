@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+// errCatalogNotModified is returned by CatalogStore.Load when the catalog is
+// still at the revision the caller already has.
+var errCatalogNotModified = errors.New("catalog not modified")
+
+const defaultStoreTimeout = 10 * time.Second
+
+// CatalogStore is where the products of the catalog are kept.
+type CatalogStore interface {
+	// Load returns every product, in catalog order, and the revision of the
+	// store they were read at. If the store is still at revision since, it
+	// returns errCatalogNotModified instead; an empty since always loads. A
+	// store that has no cheap way to tell returns an empty revision.
+	Load(ctx context.Context, since string) ([]*pb.Product, string, error)
+	// Source describes the store, such as its file path or URL.
+	Source() string
+	Close() error
+}
+
+// WritableCatalogStore is a CatalogStore whose products can be changed.
+type WritableCatalogStore interface {
+	CatalogStore
+	// Save replaces every product in the store, all at once, and returns the
+	// store's new revision.
+	Save(ctx context.Context, products []*pb.Product) (string, error)
+}
+
+// newCatalogStore creates the store named by kind: "json" (the default) or
+// "yaml" for a catalog file at location, "sqlite" for a database file at
+// location, or "http" to fetch a JSON catalog from the URL at location.
+func newCatalogStore(kind, location string) (CatalogStore, error) {
+	switch kind {
+	case "", "json":
+		return &fileCatalogStore{path: location, format: "json", decode: decodeJSONCatalog, encode: encodeJSONCatalog}, nil
+	case "yaml":
+		return &fileCatalogStore{path: location, format: "yaml", decode: decodeYAMLCatalog, encode: encodeYAMLCatalog}, nil
+	case "sqlite":
+		return newSQLiteCatalogStore(location, "products.json")
+	case "http":
+		if location == "" {
+			return nil, errors.New("the http catalog store needs a URL")
+		}
+		return &httpCatalogStore{url: location, client: &http.Client{Timeout: defaultStoreTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("unknown catalog store %q", kind)
+	}
+}
+
+// startStoreSpan starts a span describing one operation on a catalog store.
+func startStoreSpan(ctx context.Context, system, operation, statement string) (context.Context, trace.Span) {
+	tracer := otel.GetTracerProvider().Tracer("")
+	ctx, span := tracer.Start(ctx, operation+" productcatalog.products", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", system),
+		attribute.String("db.name", "productcatalog"),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", statement),
+	)
+	return ctx, span
+}
+
+// endStoreSpan ends a span started by startStoreSpan, recording the number of
+// rows, or products, it read or wrote.
+func endStoreSpan(span trace.Span, rows int, err error) {
+	if err != nil && !errors.Is(err, errCatalogNotModified) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	} else if err == nil {
+		span.SetAttributes(attribute.Int("db.rows", rows))
+	}
+	span.End()
+}
+
+func decodeJSONCatalog(data []byte) ([]*pb.Product, error) {
+	var list pb.ListProductsResponse
+	if err := jsonpb.Unmarshal(bytes.NewReader(data), &list); err != nil {
+		return nil, err
+	}
+	return list.Products, nil
+}
+
+func encodeJSONCatalog(products []*pb.Product) ([]byte, error) {
+	var buf bytes.Buffer
+	m := jsonpb.Marshaler{Indent: "    "}
+	if err := m.Marshal(&buf, &pb.ListProductsResponse{Products: products}); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// decodeYAMLCatalog reads the same document as decodeJSONCatalog, written in
+// YAML.
+func decodeYAMLCatalog(data []byte) ([]*pb.Product, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONCatalog(b)
+}
+
+func encodeYAMLCatalog(products []*pb.Product) ([]byte, error) {
+	b, err := encodeJSONCatalog(products)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// fileCatalogStore reads the catalog from a file. Its revision is the file's
+// modification time and size.
+type fileCatalogStore struct {
+	path   string
+	format string
+	decode func([]byte) ([]*pb.Product, error)
+	encode func([]*pb.Product) ([]byte, error)
+}
+
+func fileRevision(info os.FileInfo) string {
+	return info.ModTime().UTC().Format(time.RFC3339Nano) + "/" + strconv.FormatInt(info.Size(), 10)
+}
+
+func (s *fileCatalogStore) Source() string { return s.path }
+
+func (s *fileCatalogStore) Close() error { return nil }
+
+func (s *fileCatalogStore) Load(ctx context.Context, since string) (products []*pb.Product, revision string, err error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, "", err
+	}
+	revision = fileRevision(info)
+	if since != "" && since == revision {
+		return nil, revision, errCatalogNotModified
+	}
+
+	_, span := startStoreSpan(ctx, s.format, "READ", s.path)
+	defer func() { endStoreSpan(span, len(products), err) }()
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, "", err
+	}
+	products, err = s.decode(data)
+	if err != nil {
+		return nil, revision, fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	return products, revision, nil
+}
+
+// Save writes the products to a temporary file next to the catalog and
+// renames it over the catalog, so readers see the old file or the new one.
+func (s *fileCatalogStore) Save(ctx context.Context, products []*pb.Product) (revision string, err error) {
+	_, span := startStoreSpan(ctx, s.format, "WRITE", s.path)
+	defer func() { endStoreSpan(span, len(products), err) }()
+
+	data, err := s.encode(products)
+	if err != nil {
+		return "", err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(s.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", s.path, err)
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+	return fileRevision(info), nil
+}
+
+// httpCatalogStore fetches a JSON catalog from a URL. Its revision is the
+// response's ETag, which is sent back in If-None-Match so an unchanged catalog
+// isn't downloaded again.
+type httpCatalogStore struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpCatalogStore) Source() string { return s.url }
+
+func (s *httpCatalogStore) Close() error { return nil }
+
+func (s *httpCatalogStore) Load(ctx context.Context, since string) (products []*pb.Product, revision string, err error) {
+	ctx, span := startStoreSpan(ctx, "http", "GET", s.url)
+	defer func() { endStoreSpan(span, len(products), err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if since != "" {
+		req.Header.Set("If-None-Match", since)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	revision = resp.Header.Get("ETag")
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, since, errCatalogNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, "", fmt.Errorf("catalog fetch returned %d: %s", resp.StatusCode, msg)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch catalog: %w", err)
+	}
+	products, err = decodeJSONCatalog(data)
+	if err != nil {
+		return nil, revision, fmt.Errorf("parse %s: %w", s.url, err)
+	}
+	return products, revision, nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema keeps the catalog revision in catalog_meta, bumped by triggers
+// on every change, so a reload only reads the products when they changed.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS products (
+	id TEXT PRIMARY KEY,
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	picture TEXT NOT NULL DEFAULT '',
+	price_currency_code TEXT NOT NULL,
+	price_units INTEGER NOT NULL,
+	price_nanos INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS product_categories (
+	product_id TEXT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+	category TEXT NOT NULL,
+	PRIMARY KEY (product_id, category)
+);
+CREATE TABLE IF NOT EXISTS catalog_meta (
+	revision INTEGER NOT NULL
+);
+INSERT INTO catalog_meta (revision) SELECT 0 WHERE NOT EXISTS (SELECT 1 FROM catalog_meta);
+CREATE TRIGGER IF NOT EXISTS products_insert AFTER INSERT ON products BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+CREATE TRIGGER IF NOT EXISTS products_update AFTER UPDATE ON products BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+CREATE TRIGGER IF NOT EXISTS products_delete AFTER DELETE ON products BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+CREATE TRIGGER IF NOT EXISTS categories_insert AFTER INSERT ON product_categories BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+CREATE TRIGGER IF NOT EXISTS categories_update AFTER UPDATE ON product_categories BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+CREATE TRIGGER IF NOT EXISTS categories_delete AFTER DELETE ON product_categories BEGIN UPDATE catalog_meta SET revision = revision + 1; END;
+`
+
+const (
+	selectRevisionSQL   = "SELECT revision FROM catalog_meta"
+	selectProductsSQL   = "SELECT id, name, description, picture, price_currency_code, price_units, price_nanos FROM products ORDER BY position"
+	selectCategoriesSQL = "SELECT product_id, category FROM product_categories ORDER BY product_id, rowid"
+	deleteProductsSQL   = "DELETE FROM products"
+	insertProductSQL    = "INSERT INTO products (id, position, name, description, picture, price_currency_code, price_units, price_nanos) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	insertCategorySQL   = "INSERT INTO product_categories (product_id, category) VALUES (?, ?)"
+)
+
+// sqliteCatalogStore keeps the catalog in an embedded SQLite database.
+type sqliteCatalogStore struct {
+	path string
+	db   *sql.DB
+}
+
+// newSQLiteCatalogStore opens the database at path, creating it if needed,
+// and fills it from the JSON catalog at seedPath if it has no products.
+func newSQLiteCatalogStore(path, seedPath string) (*sqliteCatalogStore, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	s := &sqliteCatalogStore{path: path, db: db}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize catalog database %s: %w", path, err)
+	}
+	if err := s.seed(context.Background(), seedPath); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *sqliteCatalogStore) Source() string { return s.path }
+
+func (s *sqliteCatalogStore) Close() error { return s.db.Close() }
+
+func (s *sqliteCatalogStore) seed(ctx context.Context, seedPath string) error {
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM products").Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 || seedPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(seedPath)
+	if err != nil {
+		return fmt.Errorf("failed to seed the catalog database: %w", err)
+	}
+	products, err := decodeJSONCatalog(data)
+	if err != nil {
+		return fmt.Errorf("failed to seed the catalog database from %s: %w", seedPath, err)
+	}
+	log.Infof("seeding catalog database %s with %d products from %s", s.path, len(products), seedPath)
+	_, err = s.Save(ctx, products)
+	return err
+}
+
+// Save replaces the products in one transaction.
+func (s *sqliteCatalogStore) Save(ctx context.Context, products []*pb.Product) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := s.deleteProducts(ctx, tx); err != nil {
+		return "", err
+	}
+	if err := s.insertProducts(ctx, tx, products); err != nil {
+		return "", err
+	}
+	revision, err := s.selectRevision(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	return revision, tx.Commit()
+}
+
+func (s *sqliteCatalogStore) deleteProducts(ctx context.Context, tx *sql.Tx) (err error) {
+	ctx, span := startStoreSpan(ctx, "sqlite", "DELETE", deleteProductsSQL)
+	var n int64
+	defer func() { endStoreSpan(span, int(n), err) }()
+	res, err := tx.ExecContext(ctx, deleteProductsSQL)
+	if err != nil {
+		return err
+	}
+	n, err = res.RowsAffected()
+	return err
+}
+
+func (s *sqliteCatalogStore) insertProducts(ctx context.Context, tx *sql.Tx, products []*pb.Product) (err error) {
+	ctx, span := startStoreSpan(ctx, "sqlite", "INSERT", insertProductSQL)
+	defer func() { endStoreSpan(span, len(products), err) }()
+
+	for i, p := range products {
+		price := p.GetPriceUsd()
+		if _, err := tx.ExecContext(ctx, insertProductSQL, p.Id, i, p.Name, p.Description, p.Picture,
+			price.GetCurrencyCode(), price.GetUnits(), price.GetNanos()); err != nil {
+			return fmt.Errorf("failed to insert product %s: %w", p.Id, err)
+		}
+		for _, c := range p.Categories {
+			if _, err := tx.ExecContext(ctx, insertCategorySQL, p.Id, c); err != nil {
+				return fmt.Errorf("failed to insert product %s: %w", p.Id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *sqliteCatalogStore) Load(ctx context.Context, since string) (products []*pb.Product, revision string, err error) {
+	// read the revision and both tables from the same snapshot of the database
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if revision, err = s.selectRevision(ctx, tx); err != nil {
+		return nil, "", err
+	}
+	if since != "" && since == revision {
+		return nil, revision, errCatalogNotModified
+	}
+	if products, err = s.selectProducts(ctx, tx); err != nil {
+		return nil, "", err
+	}
+	if err := s.selectCategories(ctx, tx, products); err != nil {
+		return nil, "", err
+	}
+	return products, revision, nil
+}
+
+func (s *sqliteCatalogStore) selectRevision(ctx context.Context, tx *sql.Tx) (revision string, err error) {
+	ctx, span := startStoreSpan(ctx, "sqlite", "SELECT", selectRevisionSQL)
+	defer func() { endStoreSpan(span, 1, err) }()
+	var n int64
+	if err := tx.QueryRowContext(ctx, selectRevisionSQL).Scan(&n); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+func (s *sqliteCatalogStore) selectProducts(ctx context.Context, tx *sql.Tx) (products []*pb.Product, err error) {
+	ctx, span := startStoreSpan(ctx, "sqlite", "SELECT", selectProductsSQL)
+	defer func() { endStoreSpan(span, len(products), err) }()
+
+	rows, err := tx.QueryContext(ctx, selectProductsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p := &pb.Product{PriceUsd: &pb.Money{}}
+		if err := rows.Scan(&p.Id, &p.Name, &p.Description, &p.Picture,
+			&p.PriceUsd.CurrencyCode, &p.PriceUsd.Units, &p.PriceUsd.Nanos); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+func (s *sqliteCatalogStore) selectCategories(ctx context.Context, tx *sql.Tx, products []*pb.Product) (err error) {
+	ctx, span := startStoreSpan(ctx, "sqlite", "SELECT", selectCategoriesSQL)
+	var n int
+	defer func() { endStoreSpan(span, n, err) }()
+
+	byID := make(map[string]*pb.Product, len(products))
+	for _, p := range products {
+		byID[p.Id] = p
+	}
+	rows, err := tx.QueryContext(ctx, selectCategoriesSQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, category string
+		if err := rows.Scan(&id, &category); err != nil {
+			return err
+		}
+		n++
+		if p, ok := byID[id]; ok {
+			p.Categories = append(p.Categories, category)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/honeycombio/microservices-demo/src/productcatalogservice/demo/msdemo"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const yamlProducts = `
+products:
+  - id: A
+    name: Mug
+    priceUsd: {currencyCode: USD, units: 8}
+    categories: [kitchen, gifts]
+  - id: B
+    name: Plate
+    priceUsd: {currencyCode: USD, units: 12}
+`
+
+func loadAll(t *testing.T, store CatalogStore) ([]*pb.Product, string) {
+	t.Helper()
+	products, revision, err := store.Load(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return products, revision
+}
+
+func TestFileCatalogStores(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath, yamlPath := filepath.Join(dir, "products.json"), filepath.Join(dir, "products.yaml")
+	writeCatalog(t, jsonPath, `{"products": [
+		{"id": "A", "name": "Mug", "priceUsd": {"currencyCode": "USD", "units": 8}, "categories": ["kitchen", "gifts"]},
+		{"id": "B", "name": "Plate", "priceUsd": {"currencyCode": "USD", "units": 12}}
+	]}`, 0)
+	writeCatalog(t, yamlPath, yamlProducts, 0)
+
+	jsonStore, _ := newCatalogStore("json", jsonPath)
+	yamlStore, _ := newCatalogStore("yaml", yamlPath)
+	fromJSON, revision := loadAll(t, jsonStore)
+	fromYAML, _ := loadAll(t, yamlStore)
+	if diff := cmp.Diff(fromJSON, fromYAML, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("YAML catalog differs (-json +yaml):\n%s", diff)
+	}
+	if _, _, err := jsonStore.Load(context.Background(), revision); !errors.Is(err, errCatalogNotModified) {
+		t.Errorf("got %v for an unchanged file, want errCatalogNotModified", err)
+	}
+
+	writeCatalog(t, yamlPath, "products: [", 0)
+	if _, _, err := yamlStore.Load(context.Background(), ""); err == nil {
+		t.Error("invalid YAML was loaded")
+	}
+	if _, err := newCatalogStore("xml", jsonPath); err == nil {
+		t.Error("an unknown store was created")
+	}
+}
+
+func TestSQLiteCatalogStore(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "products.json")
+	writeCatalog(t, seed, twoProducts, 0)
+	db := filepath.Join(dir, "products.db")
+	store, err := newSQLiteCatalogStore(db, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	products, revision := loadAll(t, store)
+	want, _ := decodeJSONCatalog([]byte(twoProducts))
+	if diff := cmp.Diff(want, products, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("seeded catalog (-want +got):\n%s", diff)
+	}
+	ctx := context.Background()
+	if _, _, err := store.Load(ctx, revision); !errors.Is(err, errCatalogNotModified) {
+		t.Errorf("got %v for an unchanged database, want errCatalogNotModified", err)
+	}
+
+	if _, err := store.db.Exec("INSERT INTO product_categories (product_id, category) VALUES ('B', 'kitchen')"); err != nil {
+		t.Fatal(err)
+	}
+	products, _, err = store.Load(ctx, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"kitchen"}, products[1].Categories); diff != "" {
+		t.Errorf("categories of B (-want +got):\n%s", diff)
+	}
+
+	// reopening keeps the products rather than seeding them again
+	if err := os.Remove(seed); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+	store, err = newSQLiteCatalogStore(db, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if products, _ := loadAll(t, store); len(products) != 2 {
+		t.Errorf("got %d products after reopening, want 2", len(products))
+	}
+}
+
+func TestSQLiteCatalogStoreSpans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "products.json")
+	writeCatalog(t, seed, twoProducts, 0)
+	store, err := newSQLiteCatalogStore(filepath.Join(dir, "products.db"), seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	loadAll(t, store)
+
+	var statements []string
+	for _, s := range rec.Ended() {
+		attrs := map[string]string{}
+		for _, kv := range s.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		if attrs["db.system"] != "sqlite" || attrs["db.name"] != "productcatalog" {
+			t.Errorf("span %s has attributes %v", s.Name(), attrs)
+		}
+		statements = append(statements, attrs["db.statement"])
+	}
+	want := []string{
+		deleteProductsSQL, insertProductSQL, selectRevisionSQL, // seeding
+		selectRevisionSQL, selectProductsSQL, selectCategoriesSQL,
+	}
+	if diff := cmp.Diff(want, statements); diff != "" {
+		t.Errorf("statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestHTTPCatalogStore(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(twoProducts))
+	}))
+	defer srv.Close()
+
+	store, err := newCatalogStore("http", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	products, revision := loadAll(t, store)
+	if len(products) != 2 || revision != `"v1"` {
+		t.Errorf("got %d products at revision %s", len(products), revision)
+	}
+	if _, _, err := store.Load(context.Background(), revision); !errors.Is(err, errCatalogNotModified) {
+		t.Errorf("got %v for an unchanged catalog, want errCatalogNotModified", err)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+
+	if _, err := newCatalogStore("http", ""); err == nil {
+		t.Error("an http store was created without a URL")
+	}
+}
+
+func TestCatalogStoresSave(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed.json")
+	writeCatalog(t, seed, twoProducts, 0)
+	want, _ := decodeJSONCatalog([]byte(twoProducts))
+	want = want[1:]
+
+	yamlPath := filepath.Join(dir, "products.yaml")
+	writeCatalog(t, yamlPath, yamlProducts, 0)
+	yamlStore, _ := newCatalogStore("yaml", yamlPath)
+	sqliteStore, err := newSQLiteCatalogStore(filepath.Join(dir, "products.db"), seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqliteStore.Close()
+
+	for _, store := range []CatalogStore{yamlStore, sqliteStore} {
+		_, before := loadAll(t, store)
+		revision, err := store.(WritableCatalogStore).Save(context.Background(), want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if revision == before {
+			t.Errorf("%s: saving didn't change the revision", store.Source())
+		}
+		got, after := loadAll(t, store)
+		if diff := cmp.Diff(want, got, cmp.Comparer(proto.Equal)); diff != "" {
+			t.Errorf("%s: saved catalog (-want +got):\n%s", store.Source(), diff)
+		}
+		if after != revision {
+			t.Errorf("%s: got revision %s after saving, want %s", store.Source(), after, revision)
+		}
+	}
+}
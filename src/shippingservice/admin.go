@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/honeycombio/microservices-demo/src/internal/admin"
+	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/shipments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminService serves the AdminService, which lets presenters change demo
+// settings without restarting the service. Calls must carry token.
+type adminService struct {
+	token    string
+	settings *admin.Settings
+}
+
+func newAdminService(token string) *adminService {
+	return &adminService{token: token, settings: admin.NewSettings(map[string]admin.Setting{
+		"rate_strategy": {
+			Get: func() string {
+				v, _ := rateStrategy.Load().(string)
+				return v
+			},
+			Parse: parseRateStrategy,
+		},
+		"shipment_speed": {
+			Get: func() string {
+				return strconv.FormatFloat(shipmentLifecycle.Load().(shipments.Lifecycle).Speed, 'g', -1, 64)
+			},
+			Parse: parseShipmentSpeed,
+		},
+		"tracking_lambda_url": {
+			Get: func() string {
+				if lambda := trackingLambda.Load(); lambda != nil {
+					return lambda.URL()
+				}
+				return ""
+			},
+			Parse: func(v string) (func(), error) {
+				if err := checkTrackingLambdaHost(v); err != nil {
+					return nil, err
+				}
+				return parseTrackingLambdaURL(v)
+			},
+		},
+	})}
+}
+
+func (a *adminService) GetSettings(ctx context.Context, _ *pb.Empty) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	return &pb.AdminSettings{Settings: a.settings.Current()}, nil
+}
+
+func (a *adminService) UpdateSettings(ctx context.Context, in *pb.AdminSettings) (*pb.AdminSettings, error) {
+	if err := admin.Authorize(ctx, a.token); err != nil {
+		return nil, err
+	}
+	current, err := a.settings.Update(ctx, log, in.Settings)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.AdminSettings{Settings: current}, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+)
+
+// adminContext returns the context of an AdminService call carrying token.
+func adminContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+// TestAdminTrackingLambdaURL checks that the tracking lambda can be switched at
+// runtime, but only to an allowed host.
+func TestAdminTrackingLambdaURL(t *testing.T) {
+	defer trackingLambda.Store(nil)
+	defer func(hosts []string) { trackingLambdaHosts = hosts }(trackingLambdaHosts)
+	trackingLambdaHosts = []string{"*.example.com"}
+	admin := newAdminService("secret")
+	ctx := adminContext("secret")
+
+	url := "https://lambda.example.com/tracking"
+	if _, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"tracking_lambda_url": url}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := admin.GetSettings(ctx, &pb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Settings["tracking_lambda_url"] != url {
+		t.Errorf("TestAdminTrackingLambdaURL: got %q, want %q", got.Settings["tracking_lambda_url"], url)
+	}
+
+	for _, bad := range []string{"ftp://lambda.example.com", "http://169.254.169.254/latest/meta-data", "https://example.com.evil.test/"} {
+		_, err = admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"tracking_lambda_url": bad}})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("TestAdminTrackingLambdaURL: got %v for %s, want InvalidArgument", err, bad)
+		}
+	}
+	if got := trackingLambda.Load().URL(); got != url {
+		t.Errorf("TestAdminTrackingLambdaURL: a rejected update switched the lambda to %q", got)
+	}
+}
+
+// TestAdminRateStrategy checks that the pricing strategy can be switched at runtime.
+func TestAdminRateStrategy(t *testing.T) {
+	defer rateStrategy.Store(strategyZoned)
+	admin := newAdminService("secret")
+	ctx := adminContext("secret")
+
+	got, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"rate_strategy": strategyLegacy}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Settings["rate_strategy"] != strategyLegacy {
+		t.Errorf("TestAdminRateStrategy: got %q, want %q", got.Settings["rate_strategy"], strategyLegacy)
+	}
+
+	_, err = admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"rate_strategy": "cheapest"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TestAdminRateStrategy: got %v, want InvalidArgument", err)
+	}
+}
+
+// TestAdminShipmentSpeed checks that shipments can be sped up at runtime.
+func TestAdminShipmentSpeed(t *testing.T) {
+	defer shipmentLifecycle.Store(shipmentLifecycle.Load())
+	admin := newAdminService("secret")
+	ctx := adminContext("secret")
+
+	got, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"shipment_speed": "3600"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Settings["shipment_speed"] != "3600" {
+		t.Errorf("TestAdminShipmentSpeed: got %q, want %q", got.Settings["shipment_speed"], "3600")
+	}
+
+	_, err = admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"shipment_speed": "0"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TestAdminShipmentSpeed: got %v, want InvalidArgument", err)
+	}
+}
+
+// TestAdminRequiresToken checks that settings can't be read or changed without
+// the admin token, and that a rejected update changes nothing.
+func TestAdminRequiresToken(t *testing.T) {
+	defer rateStrategy.Store(strategyZoned)
+	admin := newAdminService("secret")
+	for _, ctx := range []context.Context{context.Background(), adminContext("wrong")} {
+		if _, err := admin.GetSettings(ctx, &pb.Empty{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("TestAdminRequiresToken: GetSettings got %v, want Unauthenticated", err)
+		}
+		_, err := admin.UpdateSettings(ctx, &pb.AdminSettings{Settings: map[string]string{"rate_strategy": strategyLegacy}})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("TestAdminRequiresToken: UpdateSettings got %v, want Unauthenticated", err)
+		}
+	}
+	_, err := admin.UpdateSettings(adminContext("secret"), &pb.AdminSettings{Settings: map[string]string{
+		"rate_strategy":  strategyLegacy,
+		"shipment_speed": "-1",
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TestAdminRequiresToken: got %v, want InvalidArgument", err)
+	}
+	if v, _ := rateStrategy.Load().(string); v != strategyZoned {
+		t.Errorf("TestAdminRequiresToken: rate strategy is %q, want it left %q", v, strategyZoned)
+	}
+}
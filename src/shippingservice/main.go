@@ -1,10 +1,9 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
@@ -13,7 +12,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
-	"io/ioutil"
+	"go.opentelemetry.io/otel/trace"
 	"math/rand"
 	"net"
 	"os"
@@ -22,6 +21,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/shipments"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -36,7 +36,6 @@ const (
 )
 
 var log *logrus.Logger
-var trackingLambdaURL string
 
 func init() {
 	log = logrus.New()
@@ -103,9 +102,26 @@ func main() {
 	}
 	port = fmt.Sprintf(":%s", port)
 
-	if value, ok := os.LookupEnv("TRACKING_LAMBDA_URL"); ok {
-		trackingLambdaURL = value
-		log.Infof("Using Tracking Lambda URL: %s", value)
+	if err := configureRatesFromEnv(); err != nil {
+		log.Fatalf("failed to configure shipping rates: %v", err)
+	}
+	log.Infof("Quoting shipping with the %s strategy", rateStrategy.Load())
+
+	if err := configureTrackingIDsFromEnv(); err != nil {
+		log.Fatalf("failed to configure tracking IDs: %v", err)
+	}
+	log.Infof("Tracking IDs start with %s", trackingIDs.Load().Node())
+
+	if err := configureShipmentsFromEnv(); err != nil {
+		log.Fatalf("failed to configure shipments: %v", err)
+	}
+	go advanceShipments(ctx, shipmentTick)
+
+	if err := configureTrackingLambdaFromEnv(); err != nil {
+		log.Fatalf("failed to configure the tracking lambda: %v", err)
+	}
+	if lambda := trackingLambda.Load(); lambda != nil {
+		log.Infof("Using Tracking Lambda URL: %s", lambda.URL())
 	}
 
 	lis, err := net.Listen("tcp", port)
@@ -121,6 +137,7 @@ func main() {
 	svc := &server{}
 	pb.RegisterShippingServiceServer(srv, svc)
 	healthpb.RegisterHealthServer(srv, svc)
+	pb.RegisterAdminServiceServer(srv, newAdminService(os.Getenv("ADMIN_TOKEN")))
 	log.Infof("Shipping Service listening on port %s", port)
 
 	// Register reflection service on gRPC server.
@@ -154,16 +171,38 @@ func (s *server) GetQuote(ctx context.Context, in *pb.GetQuoteRequest) (*pb.GetQ
 	}
 	mockDatabaseCall(ctx, 50, "SELECT shipping.products", "SELECT * from products WHERE product_id IN (?)")
 
-	// 2. Generate a quote based on the total number of items to be shipped.
-	quote := CreateQuoteFromCount(count)
+	// 2. Generate the shipping options, priced from the zone and weight of the
+	// shipment or the total number of items to be shipped.
+	strategy, _ := rateStrategy.Load().(string)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.shipping.strategy", strategy), attribute.Int("app.shipping.items", count))
+	var options []*pb.ShippingOption
+	if strategy == strategyLegacy {
+		options = []*pb.ShippingOption{legacyOption(count, time.Now())}
+	} else {
+		quotes, err := rateEngine.Quote(shipmentFromRequest(in), time.Now())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to quote shipping: %v", err)
+		}
+		q := quotes[0]
+		span.SetAttributes(
+			attribute.String("app.shipping.zone", q.Zone),
+			attribute.String("app.shipping.carrier", q.Carrier),
+			attribute.Int("app.shipping.billable_grams", q.BillableGrams),
+			attribute.Bool("app.shipping.free", q.Free),
+		)
+		log.WithFields(logrus.Fields{"zone": q.Zone, "carrier": q.Carrier, "billable_grams": q.BillableGrams, "free": q.Free}).Debug("quoted shipping")
+		for _, q := range quotes {
+			options = append(options, shippingOption(q))
+		}
+	}
+	span.SetAttributes(attribute.Int("app.shipping.options", len(options)))
 	mockDatabaseCall(ctx, 75, "INSERT shipping.quotes", "INSERT INTO quotes (quote_id, item_count, price, created_at) VALUES (?, ?, ?, ?)")
 
-	// 3. Generate a response.
+	// 3. Generate a response, costing the default option.
 	return &pb.GetQuoteResponse{
-		CostUsd: &pb.Money{
-			CurrencyCode: "USD",
-			Units:        int64(quote.Dollars),
-			Nanos:        int32(quote.Cents * 10000000)},
+		CostUsd: options[0].CostUsd,
+		Options: options,
 	}, nil
 }
 
@@ -172,33 +211,66 @@ func (s *server) GetQuote(ctx context.Context, in *pb.GetQuoteRequest) (*pb.GetQ
 func (s *server) ShipOrder(ctx context.Context, in *pb.ShipOrderRequest) (*pb.ShipOrderResponse, error) {
 	log.Info("[ShipOrder] received request")
 	defer log.Info("[ShipOrder] completed request")
+	if id := in.ShippingOptionId; id != "" {
+		if _, ok := rateEngine.Service(id); !ok && id != legacyOptionID {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown shipping option %q", id)
+		}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("app.shipping.option_id", in.ShippingOptionId))
 	// 1. Create a Tracking ID
-	baseAddress := fmt.Sprintf("%s, %s, %s", in.Address.StreetAddress, in.Address.City, in.Address.State)
+	id := newTrackingId(ctx, in.Address)
 
-	var id string
-	if trackingLambdaURL != "" {
-		resp, err := otelhttp.Post(ctx, trackingLambdaURL, "application/text",
-			bytes.NewBuffer([]byte(baseAddress)))
-		if err != nil {
-			log.Fatalf("An Error Occured %v", err)
-		}
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Fatalln(err)
-		}
-		id = string(body)
+	mockDatabaseCall(ctx, 40, "INSERT shipping.shipments", "INSERT INTO shipments (order_id, tracking_id, created_at) VALUES(?, ?, ?)")
+	// 2. Start tracking the shipment.
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.tracking_id", id))
+	now := time.Now()
+	lifecycle := shipmentLifecycle.Load().(shipments.Lifecycle)
+	shipment, transitions, err := shipmentStore.Create(planShipment(in, id, now), lifecycle, now)
+	if err != nil {
+		// the order still ships, it just can't be tracked
+		log.WithField("tracking_id", id).Warnf("failed to track shipment: %v", err)
+		span.SetAttributes(attribute.String("app.shipment.error", err.Error()))
 	} else {
-		id = CreateTrackingId(baseAddress)
+		span.SetAttributes(
+			attribute.String("app.shipment.carrier", shipment.Carrier),
+			attribute.String("app.shipment.estimated_delivery", shipment.Delivery.Format("2006-01-02")),
+		)
+		recordTransitions(span, transitions)
 	}
 
-	mockDatabaseCall(ctx, 40, "INSERT shipping.shipments", "INSERT INTO shipments (order_id, tracking_id, created_at) VALUES(?, ?, ?)")
-	// 2. Generate a response.
+	// 3. Generate a response.
 	return &pb.ShipOrderResponse{
 		TrackingId: id,
 	}, nil
 }
 
+// CancelShipment mocks that a previously requested shipment will not be sent.
+// It is used by checkout to compensate a ShipOrder when a later step of the order fails.
+func (s *server) CancelShipment(ctx context.Context, in *pb.CancelShipmentRequest) (*pb.Empty, error) {
+	log.Infof("[CancelShipment] received request for tracking_id=%q", in.TrackingId)
+	defer log.Info("[CancelShipment] completed request")
+	if in.TrackingId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "tracking_id is required")
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.tracking_id", in.TrackingId))
+	mockDatabaseCall(ctx, 40, "DELETE shipping.shipments", "DELETE FROM shipments WHERE tracking_id = ?")
+	_, transitions, err := shipmentStore.Cancel(in.TrackingId, time.Now())
+	recordTransitions(span, transitions)
+	switch {
+	case errors.Is(err, shipments.ErrNotFound):
+		// shipped before the service restarted, or never tracked
+		log.WithField("tracking_id", in.TrackingId).Debug("cancelled an untracked shipment")
+	case errors.Is(err, shipments.ErrFinal):
+		return nil, status.Errorf(codes.FailedPrecondition, "shipment %q was already delivered", in.TrackingId)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to cancel shipment: %v", err)
+	}
+	return &pb.Empty{}, nil
+}
+
 func getRandomWaitTime(max int, buckets int) float32 {
 	num := float32(0)
 	val := float32(max / buckets)
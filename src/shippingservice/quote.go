@@ -1,10 +1,153 @@
 package main
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"math"
+	"os"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/rates"
+)
+
+// How GetQuote prices shipping: by zone and weight, or with the original
+// formula on the number of items, which older demos expect.
+const (
+	strategyZoned  = "zoned"
+	strategyLegacy = "legacy"
 )
 
+// defaultRates are the rates used unless SHIPPING_RATES_FILE names others.
+//
+//go:embed rates.json
+var defaultRates []byte
+
+// rateStrategy holds the pricing strategy, one of the strategy constants.
+var rateStrategy atomic.Value
+
+// rateEngine prices shipments for the zoned strategy.
+var rateEngine *rates.Engine
+
+func init() {
+	rateStrategy.Store(strategyZoned)
+	e, err := rates.Load(bytes.NewReader(defaultRates))
+	if err != nil {
+		panic(fmt.Sprintf("the default rates are invalid: %v", err))
+	}
+	rateEngine = e
+}
+
+// setRateStrategy switches the pricing strategy.
+func setRateStrategy(v string) error {
+	apply, err := parseRateStrategy(v)
+	if err != nil {
+		return err
+	}
+	apply()
+	return nil
+}
+
+// parseRateStrategy checks v is a pricing strategy, and returns a function
+// that switches to it.
+func parseRateStrategy(v string) (func(), error) {
+	if v != strategyZoned && v != strategyLegacy {
+		return nil, fmt.Errorf("unknown rate strategy %q, want %q or %q", v, strategyZoned, strategyLegacy)
+	}
+	return func() { rateStrategy.Store(v) }, nil
+}
+
+// configureRatesFromEnv loads the rates from SHIPPING_RATES_FILE and picks the
+// strategy from SHIPPING_RATE_STRATEGY, when they are set.
+func configureRatesFromEnv() error {
+	if path := os.Getenv("SHIPPING_RATES_FILE"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		e, err := rates.Load(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		rateEngine = e
+	}
+	if v := os.Getenv("SHIPPING_RATE_STRATEGY"); v != "" {
+		return setRateStrategy(v)
+	}
+	return nil
+}
+
+// shipmentFromRequest turns a quote request into a shipment to price. A
+// request without an address is priced as a domestic shipment.
+func shipmentFromRequest(in *pb.GetQuoteRequest) rates.Shipment {
+	s := rates.Shipment{
+		Destination: rates.Location{
+			Country: in.GetAddress().GetCountry(),
+			State:   in.GetAddress().GetState(),
+		},
+		Items: make([]rates.Item, len(in.GetItems())),
+	}
+	if zip := in.GetAddress().GetZipCode(); zip > 0 {
+		s.Destination.Zip = fmt.Sprintf("%05d", zip)
+	}
+	for i, it := range in.GetItems() {
+		s.Items[i] = rates.Item{ProductID: it.GetProductId(), Quantity: int(it.GetQuantity())}
+	}
+	if sub := in.GetSubtotalUsd(); sub.GetCurrencyCode() == "" || sub.GetCurrencyCode() == "USD" {
+		s.SubtotalCents = sub.GetUnits()*100 + int64(sub.GetNanos()/10000000)
+	}
+	return s
+}
+
+// CreateQuoteFromCents takes a price in cents and creates a Quote struct.
+func CreateQuoteFromCents(cents int64) Quote {
+	return Quote{uint32(cents / 100), uint32(cents % 100)}
+}
+
+// The legacy strategy has a single option, which takes as long as standard
+// shipping across the country.
+const (
+	legacyOptionID     = "standard"
+	legacyCarrier      = "Demo Post"
+	legacyBusinessDays = 5
+)
+
+// money converts the Quote to USD.
+func (q Quote) money() *pb.Money {
+	return &pb.Money{
+		CurrencyCode: "USD",
+		Units:        int64(q.Dollars),
+		Nanos:        int32(q.Cents * 10000000)}
+}
+
+// shippingOption converts a rate quote to an option for GetQuote.
+func shippingOption(q rates.Quote) *pb.ShippingOption {
+	return &pb.ShippingOption{
+		Id:                    q.ServiceID,
+		Name:                  q.ServiceName,
+		Carrier:               q.Carrier,
+		CostUsd:               CreateQuoteFromCents(q.Cents).money(),
+		BusinessDays:          int32(q.BusinessDays),
+		EstimatedDeliveryDate: q.Delivery.Format("2006-01-02"),
+	}
+}
+
+// legacyOption is the only option of the legacy strategy, priced on the
+// number of items.
+func legacyOption(count int, orderedAt time.Time) *pb.ShippingOption {
+	return &pb.ShippingOption{
+		Id:                    legacyOptionID,
+		Name:                  "Standard",
+		Carrier:               legacyCarrier,
+		CostUsd:               CreateQuoteFromCount(count).money(),
+		BusinessDays:          legacyBusinessDays,
+		EstimatedDeliveryDate: rateEngine.Calendar().Delivery(orderedAt, legacyBusinessDays).Format("2006-01-02"),
+	}
+}
+
 // Quote represents a currency value.
 type Quote struct {
 	Dollars uint32
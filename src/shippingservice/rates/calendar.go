@@ -0,0 +1,75 @@
+package rates
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar says which days parcels move on: weekdays that aren't holidays.
+// Times are taken in UTC.
+type Calendar struct {
+	// Holidays are days nothing ships or is delivered, as YYYY-MM-DD.
+	Holidays []string `json:"holidays"`
+	// CutoffHour is the hour after which orders ship the next business day.
+	// 0 means orders ship the day they are placed whatever the time.
+	CutoffHour int `json:"cutoff_hour"`
+
+	holidays map[string]bool
+}
+
+func (c *Calendar) init() error {
+	if c.CutoffHour < 0 || c.CutoffHour > 23 {
+		return fmt.Errorf("cutoff_hour must be between 0 and 23, got %d", c.CutoffHour)
+	}
+	c.holidays = make(map[string]bool, len(c.Holidays))
+	for _, h := range c.Holidays {
+		d, err := time.Parse(dateLayout, h)
+		if err != nil {
+			return fmt.Errorf("invalid holiday %q: %w", h, err)
+		}
+		c.holidays[d.Format(dateLayout)] = true
+	}
+	return nil
+}
+
+// day truncates t to the start of its day in UTC.
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// BusinessDay reports whether parcels move on the day of t.
+func (c *Calendar) BusinessDay(t time.Time) bool {
+	switch t.UTC().Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !c.holidays[t.UTC().Format(dateLayout)]
+}
+
+// ShipDate returns the day an order placed at t leaves the warehouse.
+func (c *Calendar) ShipDate(t time.Time) time.Time {
+	d := day(t)
+	if c.CutoffHour > 0 && t.UTC().Hour() >= c.CutoffHour {
+		d = d.AddDate(0, 0, 1)
+	}
+	for !c.BusinessDay(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// Delivery returns the day a shipment taking businessDays arrives when the
+// order is placed at t.
+func (c *Calendar) Delivery(t time.Time, businessDays int) time.Time {
+	d := c.ShipDate(t)
+	for n := 0; n < businessDays; {
+		d = d.AddDate(0, 0, 1)
+		if c.BusinessDay(d) {
+			n++
+		}
+	}
+	return d
+}
@@ -0,0 +1,50 @@
+package rates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	c := &Calendar{Holidays: []string{"2026-12-25", "2027-01-01"}, CutoffHour: 15}
+	if err := c.init(); err != nil {
+		t.Fatal(err)
+	}
+	at := func(s string) time.Time {
+		t.Helper()
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	for _, tc := range []struct {
+		orderedAt string
+		days      int
+		ship      string
+		delivery  string
+	}{
+		{"2026-12-21T09:00:00Z", 1, "2026-12-21", "2026-12-22"},
+		{"2026-12-21T16:00:00Z", 1, "2026-12-22", "2026-12-23"},      // after the cutoff
+		{"2026-12-24T09:00:00Z", 1, "2026-12-24", "2026-12-28"},      // over Christmas and a weekend
+		{"2026-12-25T09:00:00Z", 0, "2026-12-28", "2026-12-28"},      // ordered on a holiday
+		{"2026-12-26T09:00:00Z", 5, "2026-12-28", "2027-01-05"},      // over New Year
+		{"2026-12-21T09:00:00-03:00", 1, "2026-12-21", "2026-12-22"}, // 12:00 UTC
+		{"2026-12-21T09:00:00-10:00", 1, "2026-12-22", "2026-12-23"}, // 19:00 UTC
+	} {
+		if got := c.ShipDate(at(tc.orderedAt)).Format(dateLayout); got != tc.ship {
+			t.Errorf("ShipDate(%s) = %s, want %s", tc.orderedAt, got, tc.ship)
+		}
+		if got := c.Delivery(at(tc.orderedAt), tc.days).Format(dateLayout); got != tc.delivery {
+			t.Errorf("Delivery(%s, %d) = %s, want %s", tc.orderedAt, tc.days, got, tc.delivery)
+		}
+	}
+
+	noCutoff := &Calendar{}
+	if err := noCutoff.init(); err != nil {
+		t.Fatal(err)
+	}
+	if got := noCutoff.ShipDate(at("2026-12-21T23:59:00Z")).Format(dateLayout); got != "2026-12-21" {
+		t.Errorf("without a cutoff a late order ships on %s, want the same day", got)
+	}
+}
@@ -0,0 +1,437 @@
+// Package rates prices shipments the way a carrier does: by the zone a parcel
+// travels to and its billable weight, which is the larger of what it weighs
+// and its dimensional weight, the weight a parcel of its size is charged as.
+// Each service level, such as express, scales the price and takes a number of
+// business days to each zone.
+//
+// Zones are worked out from the origin and destination. Within the origin's
+// state a parcel goes to the local zone. Elsewhere in the origin's country
+// the zone depends on how far apart the first three digits of the two zip
+// codes are, which roughly follows distance across the US. Other countries
+// have a zone each, or a default one.
+package rates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Location is where a parcel is sent from or to.
+type Location struct {
+	Country string `json:"country"`
+	State   string `json:"state"`
+	Zip     string `json:"zip"`
+}
+
+// Product is the weight and box size of one unit of a product.
+type Product struct {
+	WeightGrams int     `json:"weight_g"`
+	LengthCm    float64 `json:"length_cm"`
+	WidthCm     float64 `json:"width_cm"`
+	HeightCm    float64 `json:"height_cm"`
+}
+
+func (p Product) volume() float64 { return p.LengthCm * p.WidthCm * p.HeightCm }
+
+// ZoneRate is what a carrier charges to ship to a zone: a base price plus a
+// price for each kilogram of billable weight, both in USD.
+type ZoneRate struct {
+	Base  float64 `json:"base"`
+	PerKg float64 `json:"per_kg"`
+}
+
+// Carrier is a carrier's rate table, by zone. A carrier without a rate for a
+// zone doesn't ship there.
+type Carrier struct {
+	Name  string              `json:"name"`
+	Rates map[string]ZoneRate `json:"rates"`
+}
+
+// DomesticZone is the zone of destinations whose zip code prefix is at most
+// MaxDistance away from the origin's.
+type DomesticZone struct {
+	MaxDistance int    `json:"max_distance"`
+	Zone        string `json:"zone"`
+}
+
+// Zones maps destinations to zones.
+type Zones struct {
+	// SameState is the zone for destinations in the origin's state.
+	SameState string `json:"same_state"`
+	// Domestic is checked in order for the rest of the origin's country.
+	Domestic []DomesticZone `json:"domestic"`
+	// DomesticDefault is the zone for domestic destinations without a zip
+	// code, or further away than every Domestic zone.
+	DomesticDefault string `json:"domestic_default"`
+	// International is the zone of each country code.
+	International map[string]string `json:"international"`
+	// InternationalDefault is the zone for countries not in International.
+	InternationalDefault string `json:"international_default"`
+}
+
+// Service is a level of service parcels can be shipped with.
+type Service struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Multiplier scales the carriers' rates for the service.
+	Multiplier float64 `json:"multiplier"`
+	// BusinessDays is how many business days the service takes to each
+	// zone. The service isn't offered to zones it has no entry for.
+	BusinessDays map[string]int `json:"business_days"`
+}
+
+// FreeShipping ships orders of at least MinSubtotal USD for free, to the
+// listed zones with the listed services. No zones or services means any.
+type FreeShipping struct {
+	MinSubtotal float64  `json:"min_subtotal"`
+	Zones       []string `json:"zones"`
+	Services    []string `json:"services"`
+}
+
+// Config is everything the engine needs to price a shipment.
+type Config struct {
+	Origin Location `json:"origin"`
+	// Countries maps country names, in lower case, to the codes zones use.
+	// Codes are matched without it.
+	Countries map[string]string `json:"countries"`
+	Zones     Zones             `json:"zones"`
+	// Products are the weights and sizes of the products, by ID. Products
+	// not listed are DefaultProduct.
+	Products       map[string]Product `json:"products"`
+	DefaultProduct Product            `json:"default_product"`
+	// DimDivisor is how many cubic centimetres of parcel are charged as a
+	// kilogram. It defaults to 5000.
+	DimDivisor float64   `json:"dim_divisor"`
+	Carriers   []Carrier `json:"carriers"`
+	// Services are the service levels, the default first.
+	Services     []Service     `json:"services"`
+	Calendar     Calendar      `json:"calendar"`
+	FreeShipping *FreeShipping `json:"free_shipping"`
+}
+
+const defaultDimDivisor = 5000
+
+// billingStepGrams is what billable weight is rounded up to.
+const billingStepGrams = 500
+
+// Item is a quantity of a product.
+type Item struct {
+	ProductID string
+	Quantity  int
+}
+
+// Shipment is the items to send, where to and what they cost in USD cents.
+type Shipment struct {
+	Destination   Location
+	Items         []Item
+	SubtotalCents int64
+}
+
+// Quote is the price of a shipment with a service, with the cheapest carrier.
+type Quote struct {
+	ServiceID     string
+	ServiceName   string
+	Zone          string
+	Carrier       string
+	BillableGrams int
+	Cents         int64
+	// Free is set when the order qualifies for free shipping, in which case
+	// Cents is 0.
+	Free         bool
+	BusinessDays int
+	// Delivery is the day the shipment is expected to arrive.
+	Delivery time.Time
+}
+
+// Engine prices shipments. It doesn't change after it is created, so it is
+// safe for concurrent use.
+type Engine struct {
+	cfg    Config
+	origin Location
+}
+
+// New checks the config and returns an engine for it.
+func New(cfg Config) (*Engine, error) {
+	if cfg.DimDivisor == 0 {
+		cfg.DimDivisor = defaultDimDivisor
+	}
+	if cfg.DimDivisor < 0 {
+		return nil, fmt.Errorf("dim_divisor must be positive, got %v", cfg.DimDivisor)
+	}
+	e := &Engine{cfg: cfg}
+	e.origin = e.normalize(cfg.Origin)
+	if e.origin.Country == "" {
+		return nil, errors.New("the origin needs a country")
+	}
+	if cfg.Zones.DomesticDefault == "" || cfg.Zones.InternationalDefault == "" {
+		return nil, errors.New("zones need a domestic_default and an international_default")
+	}
+	if len(cfg.Carriers) == 0 {
+		return nil, errors.New("at least one carrier is required")
+	}
+	for _, c := range cfg.Carriers {
+		if c.Name == "" {
+			return nil, errors.New("every carrier needs a name")
+		}
+		for zone, r := range c.Rates {
+			if r.Base < 0 || r.PerKg < 0 {
+				return nil, fmt.Errorf("carrier %s has a negative rate for zone %s", c.Name, zone)
+			}
+		}
+	}
+	if len(cfg.Services) == 0 {
+		return nil, errors.New("at least one service is required")
+	}
+	ids := make(map[string]bool, len(cfg.Services))
+	for _, svc := range cfg.Services {
+		if svc.ID == "" || ids[svc.ID] {
+			return nil, fmt.Errorf("every service needs a unique ID, got %q", svc.ID)
+		}
+		ids[svc.ID] = true
+		if svc.Multiplier <= 0 {
+			return nil, fmt.Errorf("service %s needs a positive multiplier", svc.ID)
+		}
+		for zone, days := range svc.BusinessDays {
+			if days < 1 {
+				return nil, fmt.Errorf("service %s takes %d business days to zone %s, want at least 1", svc.ID, days, zone)
+			}
+		}
+	}
+	if err := e.cfg.Calendar.init(); err != nil {
+		return nil, err
+	}
+	for id, p := range cfg.Products {
+		if p.WeightGrams < 0 || p.volume() < 0 {
+			return nil, fmt.Errorf("product %s has a negative weight or size", id)
+		}
+	}
+	for _, zone := range e.zones() {
+		if !e.served(zone) {
+			return nil, fmt.Errorf("no carrier ships to zone %q", zone)
+		}
+		if len(e.services(zone)) == 0 {
+			return nil, fmt.Errorf("no service is offered to zone %q", zone)
+		}
+	}
+	if f := cfg.FreeShipping; f != nil && f.MinSubtotal < 0 {
+		return nil, fmt.Errorf("free_shipping.min_subtotal must not be negative, got %v", f.MinSubtotal)
+	}
+	return e, nil
+}
+
+// Load reads a JSON config and returns an engine for it.
+func Load(r io.Reader) (*Engine, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid rates config: %w", err)
+	}
+	return New(cfg)
+}
+
+// zones returns every zone the config can put a destination in.
+func (e *Engine) zones() []string {
+	z := e.cfg.Zones
+	all := []string{z.SameState, z.DomesticDefault, z.InternationalDefault}
+	for _, d := range z.Domestic {
+		all = append(all, d.Zone)
+	}
+	for _, zone := range z.International {
+		all = append(all, zone)
+	}
+	out := all[:0]
+	for _, zone := range all {
+		if zone != "" {
+			out = append(out, zone)
+		}
+	}
+	return out
+}
+
+func (e *Engine) served(zone string) bool {
+	for _, c := range e.cfg.Carriers {
+		if _, ok := c.Rates[zone]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// services returns the services offered to the zone, in config order.
+func (e *Engine) services(zone string) []Service {
+	var out []Service
+	for _, svc := range e.cfg.Services {
+		if _, ok := svc.BusinessDays[zone]; ok {
+			out = append(out, svc)
+		}
+	}
+	return out
+}
+
+// Service returns the service with the ID.
+func (e *Engine) Service(id string) (Service, bool) {
+	for _, svc := range e.cfg.Services {
+		if svc.ID == id {
+			return svc, true
+		}
+	}
+	return Service{}, false
+}
+
+// Calendar returns the business-day calendar delivery dates are worked out
+// with.
+func (e *Engine) Calendar() *Calendar { return &e.cfg.Calendar }
+
+// Origin returns where shipments leave from.
+func (e *Engine) Origin() Location { return e.origin }
+
+// normalize turns country names into codes and upper cases codes and states.
+func (e *Engine) normalize(l Location) Location {
+	country := strings.TrimSpace(l.Country)
+	if code, ok := e.cfg.Countries[strings.ToLower(country)]; ok {
+		country = code
+	}
+	return Location{
+		Country: strings.ToUpper(country),
+		State:   strings.ToUpper(strings.TrimSpace(l.State)),
+		Zip:     strings.TrimSpace(l.Zip),
+	}
+}
+
+// zipPrefix returns the first three digits of a zip code.
+func zipPrefix(zip string) (int, bool) {
+	if len(zip) < 3 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(zip[:3])
+	return n, err == nil
+}
+
+// Zone returns the zone of a destination. A destination without a country is
+// taken to be in the origin's.
+func (e *Engine) Zone(dest Location) string {
+	dest = e.normalize(dest)
+	z := e.cfg.Zones
+	if dest.Country != "" && dest.Country != e.origin.Country {
+		if zone, ok := z.International[dest.Country]; ok {
+			return zone
+		}
+		return z.InternationalDefault
+	}
+	if dest.State != "" && dest.State == e.origin.State && z.SameState != "" {
+		return z.SameState
+	}
+	from, okFrom := zipPrefix(e.origin.Zip)
+	to, okTo := zipPrefix(dest.Zip)
+	if okFrom && okTo {
+		distance := from - to
+		if distance < 0 {
+			distance = -distance
+		}
+		for _, d := range z.Domestic {
+			if distance <= d.MaxDistance {
+				return d.Zone
+			}
+		}
+	}
+	return z.DomesticDefault
+}
+
+// BillableGrams returns the weight the items are charged as, rounded up to
+// the next half kilogram.
+func (e *Engine) BillableGrams(items []Item) int {
+	var grams, volume float64
+	for _, it := range items {
+		p, ok := e.cfg.Products[it.ProductID]
+		if !ok {
+			p = e.cfg.DefaultProduct
+		}
+		grams += float64(it.Quantity * p.WeightGrams)
+		volume += float64(it.Quantity) * p.volume()
+	}
+	dimensional := volume / e.cfg.DimDivisor * 1000
+	billable := math.Max(grams, dimensional)
+	return int(math.Ceil(billable/billingStepGrams)) * billingStepGrams
+}
+
+// Quote prices the shipment with every service offered to its zone, the
+// default first, using the cheapest carrier that ships there, or the first one
+// listed if several cost the same. Delivery dates count from orderedAt.
+func (e *Engine) Quote(s Shipment, orderedAt time.Time) ([]Quote, error) {
+	count := 0
+	for _, it := range s.Items {
+		if it.Quantity < 0 {
+			return nil, fmt.Errorf("quantity of product %s can't be negative, got %d", it.ProductID, it.Quantity)
+		}
+		count += it.Quantity
+	}
+	zone := e.Zone(s.Destination)
+	grams := e.BillableGrams(s.Items)
+
+	var carrier string
+	var cost float64
+	for _, c := range e.cfg.Carriers {
+		r, ok := c.Rates[zone]
+		if !ok {
+			continue
+		}
+		price := r.Base + r.PerKg*float64(grams)/1000
+		if carrier == "" || price < cost {
+			carrier, cost = c.Name, price
+		}
+	}
+	if carrier == "" {
+		return nil, fmt.Errorf("no carrier ships to zone %q", zone)
+	}
+
+	var out []Quote
+	for _, svc := range e.services(zone) {
+		q := Quote{
+			ServiceID:     svc.ID,
+			ServiceName:   svc.Name,
+			Zone:          zone,
+			Carrier:       carrier,
+			BillableGrams: grams,
+			BusinessDays:  svc.BusinessDays[zone],
+			Delivery:      e.cfg.Calendar.Delivery(orderedAt, svc.BusinessDays[zone]),
+		}
+		switch {
+		case count == 0:
+			// nothing to ship costs nothing
+		case e.freeShipping(zone, svc.ID, s.SubtotalCents):
+			q.Free = true
+		default:
+			q.Cents = int64(math.Round(cost * svc.Multiplier * 100))
+		}
+		out = append(out, q)
+	}
+	return out, nil
+}
+
+func (e *Engine) freeShipping(zone, service string, subtotalCents int64) bool {
+	f := e.cfg.FreeShipping
+	if f == nil || subtotalCents < int64(math.Round(f.MinSubtotal*100)) {
+		return false
+	}
+	return anyOrContains(f.Zones, zone) && anyOrContains(f.Services, service)
+}
+
+// anyOrContains reports whether list is empty or contains s.
+func anyOrContains(list []string, s string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package rates
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testConfig() Config {
+	return Config{
+		Origin:    Location{Country: "US", State: "NV", Zip: "89502"},
+		Countries: map[string]string{"united states": "US", "england": "GB"},
+		Zones: Zones{
+			SameState:            "local",
+			Domestic:             []DomesticZone{{MaxDistance: 100, Zone: "regional"}},
+			DomesticDefault:      "national",
+			International:        map[string]string{"GB": "europe"},
+			InternationalDefault: "world",
+		},
+		Products: map[string]Product{
+			"HEAVY": {WeightGrams: 2100, LengthCm: 10, WidthCm: 10, HeightCm: 10},
+			// 50x40x30 cm is charged as 12 kg
+			"BULKY": {WeightGrams: 1000, LengthCm: 50, WidthCm: 40, HeightCm: 30},
+		},
+		DefaultProduct: Product{WeightGrams: 200, LengthCm: 10, WidthCm: 10, HeightCm: 5},
+		Carriers: []Carrier{
+			{Name: "Slow", Rates: map[string]ZoneRate{
+				"local": {Base: 4, PerKg: 1}, "regional": {Base: 5, PerKg: 1},
+				"national": {Base: 8, PerKg: 2}, "europe": {Base: 20, PerKg: 5},
+			}},
+			{Name: "Fast", Rates: map[string]ZoneRate{
+				"local": {Base: 4, PerKg: 1}, "national": {Base: 5, PerKg: 3},
+				"world": {Base: 30, PerKg: 10},
+			}},
+		},
+		Services: []Service{
+			{ID: "standard", Name: "Standard", Multiplier: 1, BusinessDays: map[string]int{
+				"local": 2, "regional": 3, "national": 4, "europe": 6, "world": 8,
+			}},
+			{ID: "express", Name: "Express", Multiplier: 2, BusinessDays: map[string]int{"local": 1, "national": 2}},
+		},
+		Calendar:     Calendar{Holidays: []string{"2026-12-25"}, CutoffHour: 15},
+		FreeShipping: &FreeShipping{MinSubtotal: 50, Zones: []string{"local", "regional", "national"}, Services: []string{"standard"}},
+	}
+}
+
+// wednesday is a Wednesday morning two days before a holiday.
+var wednesday = time.Date(2026, 12, 23, 10, 0, 0, 0, time.UTC)
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	e, err := New(testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestZone(t *testing.T) {
+	e := newTestEngine(t)
+	for _, tc := range []struct {
+		dest Location
+		want string
+	}{
+		{Location{Country: "US", State: "nv", Zip: "89101"}, "local"},
+		{Location{Country: "United States", State: "CA", Zip: "94043"}, "regional"},
+		{Location{Country: "usa", State: "CA", Zip: "94043"}, "world"}, // not an alias in this config
+		{Location{Country: "US", State: "NY", Zip: "10001"}, "national"},
+		{Location{Country: "US", State: "CA"}, "national"},
+		{Location{State: "CA", Zip: "94043"}, "regional"},
+		{Location{}, "national"},
+		{Location{Country: "England", Zip: "89502"}, "europe"},
+		{Location{Country: "gb"}, "europe"},
+		{Location{Country: "JP"}, "world"},
+	} {
+		if got := e.Zone(tc.dest); got != tc.want {
+			t.Errorf("Zone(%+v) = %q, want %q", tc.dest, got, tc.want)
+		}
+	}
+}
+
+func TestBillableGrams(t *testing.T) {
+	e := newTestEngine(t)
+	for _, tc := range []struct {
+		items []Item
+		want  int
+	}{
+		{[]Item{{"HEAVY", 1}}, 2500},
+		{[]Item{{"HEAVY", 2}}, 4500},
+		{[]Item{{"BULKY", 1}}, 12000},
+		{[]Item{{"UNKNOWN", 1}}, 500},
+		{[]Item{{"UNKNOWN", 3}, {"HEAVY", 1}}, 3000},
+		{nil, 0},
+	} {
+		if got := e.BillableGrams(tc.items); got != tc.want {
+			t.Errorf("BillableGrams(%v) = %d, want %d", tc.items, got, tc.want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	e := newTestEngine(t)
+	for name, tc := range map[string]struct {
+		shipment Shipment
+		want     Quote
+	}{
+		"tie goes to the first carrier": {
+			Shipment{Destination: Location{Country: "US", State: "NV"}, Items: []Item{{"HEAVY", 1}}},
+			Quote{Zone: "local", Carrier: "Slow", BillableGrams: 2500, Cents: 650},
+		},
+		"only one carrier ships to the zone": {
+			Shipment{Destination: Location{Country: "US", State: "CA", Zip: "94043"}, Items: []Item{{"HEAVY", 1}}},
+			Quote{Zone: "regional", Carrier: "Slow", BillableGrams: 2500, Cents: 750},
+		},
+		"cheapest carrier": {
+			Shipment{Destination: Location{Country: "US", State: "NY", Zip: "10001"}, Items: []Item{{"HEAVY", 1}}},
+			Quote{Zone: "national", Carrier: "Fast", BillableGrams: 2500, Cents: 1250},
+		},
+		"dimensional weight": {
+			Shipment{Destination: Location{Country: "GB"}, Items: []Item{{"BULKY", 1}}},
+			Quote{Zone: "europe", Carrier: "Slow", BillableGrams: 12000, Cents: 8000},
+		},
+		"free shipping": {
+			Shipment{Destination: Location{Country: "US", State: "NY"}, Items: []Item{{"HEAVY", 1}}, SubtotalCents: 5000},
+			Quote{Zone: "national", Carrier: "Fast", BillableGrams: 2500, Free: true},
+		},
+		"not free just below the threshold": {
+			Shipment{Destination: Location{Country: "US", State: "NY"}, Items: []Item{{"HEAVY", 1}}, SubtotalCents: 4999},
+			Quote{Zone: "national", Carrier: "Fast", BillableGrams: 2500, Cents: 1250},
+		},
+		"no free shipping abroad": {
+			Shipment{Destination: Location{Country: "JP"}, Items: []Item{{"HEAVY", 1}}, SubtotalCents: 100000},
+			Quote{Zone: "world", Carrier: "Fast", BillableGrams: 2500, Cents: 5500},
+		},
+		"nothing to ship": {
+			Shipment{Destination: Location{Country: "JP"}},
+			Quote{Zone: "world", Carrier: "Fast"},
+		},
+	} {
+		quotes, err := e.Quote(tc.shipment, wednesday)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		got := quotes[0]
+		got = Quote{Zone: got.Zone, Carrier: got.Carrier, BillableGrams: got.BillableGrams, Cents: got.Cents, Free: got.Free}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", name, got, tc.want)
+		}
+	}
+
+	if _, err := e.Quote(Shipment{Items: []Item{{"HEAVY", -1}}}, wednesday); err == nil {
+		t.Error("a negative quantity was quoted")
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	for name, change := range map[string]func(*Config){
+		"no origin country":      func(c *Config) { c.Origin.Country = "" },
+		"no carriers":            func(c *Config) { c.Carriers = nil },
+		"unnamed carrier":        func(c *Config) { c.Carriers[0].Name = "" },
+		"negative rate":          func(c *Config) { c.Carriers[0].Rates["local"] = ZoneRate{Base: -1} },
+		"negative weight":        func(c *Config) { c.Products["HEAVY"] = Product{WeightGrams: -1} },
+		"unserved zone":          func(c *Config) { c.Zones.International["FR"] = "mars" },
+		"no default zone":        func(c *Config) { c.Zones.InternationalDefault = "" },
+		"negative divisor":       func(c *Config) { c.DimDivisor = -1 },
+		"negative free shipping": func(c *Config) { c.FreeShipping.MinSubtotal = -1 },
+		"no services":            func(c *Config) { c.Services = nil },
+		"duplicate service":      func(c *Config) { c.Services[1].ID = "standard" },
+		"zero multiplier":        func(c *Config) { c.Services[1].Multiplier = 0 },
+		"zero days":              func(c *Config) { c.Services[1].BusinessDays["local"] = 0 },
+		"zone without service":   func(c *Config) { delete(c.Services[0].BusinessDays, "europe") },
+		"invalid holiday":        func(c *Config) { c.Calendar.Holidays = []string{"25/12/2026"} },
+		"invalid cutoff":         func(c *Config) { c.Calendar.CutoffHour = 24 },
+	} {
+		cfg := testConfig()
+		change(&cfg)
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%s: New accepted the config", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	e, err := Load(strings.NewReader(`{
+		"origin": {"country": "US"},
+		"zones": {"domestic_default": "us", "international_default": "abroad"},
+		"default_product": {"weight_g": 400},
+		"carriers": [{"name": "Post", "rates": {"us": {"base": 5}, "abroad": {"base": 15, "per_kg": 2.5}}}],
+		"services": [{"id": "standard", "name": "Standard", "multiplier": 1, "business_days": {"us": 3, "abroad": 7}}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := e.Quote(Shipment{Destination: Location{Country: "FR"}, Items: []Item{{"ANY", 1}}}, wednesday)
+	if err != nil || len(q) != 1 || q[0].Cents != 1500+125 {
+		t.Errorf("got %+v, %v, want 16.25 to ship a default product abroad", q, err)
+	}
+
+	if _, err := Load(strings.NewReader(`{"origin": {"country": "US"}, "carrier": []}`)); err == nil {
+		t.Error("Load accepted an unknown field")
+	}
+}
+
+func TestQuoteServices(t *testing.T) {
+	e := newTestEngine(t)
+	type option struct {
+		id       string
+		cents    int64
+		free     bool
+		days     int
+		delivery string
+	}
+	for name, tc := range map[string]struct {
+		shipment Shipment
+		want     []option
+	}{
+		"local": {
+			Shipment{Destination: Location{Country: "US", State: "NV"}, Items: []Item{{"HEAVY", 1}}},
+			// Christmas and the weekend don't count
+			[]option{{"standard", 650, false, 2, "2026-12-28"}, {"express", 1300, false, 1, "2026-12-24"}},
+		},
+		"express isn't offered": {
+			Shipment{Destination: Location{Country: "GB"}, Items: []Item{{"HEAVY", 1}}},
+			[]option{{"standard", 3250, false, 6, "2027-01-01"}},
+		},
+		"free shipping is standard only": {
+			Shipment{Destination: Location{Country: "US", State: "NY"}, Items: []Item{{"HEAVY", 1}}, SubtotalCents: 5000},
+			[]option{{"standard", 0, true, 4, "2026-12-30"}, {"express", 2500, false, 2, "2026-12-28"}},
+		},
+	} {
+		quotes, err := e.Quote(tc.shipment, wednesday)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		var got []option
+		for _, q := range quotes {
+			got = append(got, option{q.ServiceID, q.Cents, q.Free, q.BusinessDays, q.Delivery.Format("2006-01-02")})
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %+v, want %+v", name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: got %+v, want %+v", name, got, tc.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package shipments keeps track of shipped orders as they make their way to
+// the customer.
+package shipments
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Status is where a shipment has got to.
+type Status string
+
+const (
+	LabelCreated   Status = "label_created"
+	PickedUp       Status = "picked_up"
+	InTransit      Status = "in_transit"
+	OutForDelivery Status = "out_for_delivery"
+	Delivered      Status = "delivered"
+	// Exception is a hold-up, after which the shipment carries on.
+	Exception Status = "exception"
+	Cancelled Status = "cancelled"
+)
+
+// next lists the statuses a shipment can move to from each status.
+var next = map[Status][]Status{
+	LabelCreated:   {PickedUp, Cancelled},
+	PickedUp:       {InTransit, Cancelled},
+	InTransit:      {OutForDelivery, Exception, Cancelled},
+	Exception:      {InTransit, OutForDelivery, Cancelled},
+	OutForDelivery: {Delivered, Exception, Cancelled},
+}
+
+// CanMove reports whether a shipment can go from one status to the other.
+func CanMove(from, to Status) bool {
+	for _, s := range next[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Final reports whether nothing more happens to a shipment with the status.
+func (s Status) Final() bool { return len(next[s]) == 0 }
+
+// Event is a status a shipment reaches.
+type Event struct {
+	Status      Status
+	At          time.Time
+	Location    string
+	Description string
+}
+
+// Times of day, in UTC, deliveries go out and arrive on the delivery day.
+const (
+	outForDeliveryHour = 8
+	deliveredHour      = 14
+)
+
+// exceptions are what can hold a shipment up in transit.
+var exceptions = []string{
+	"Weather delay",
+	"Missed connection at the sorting facility",
+	"Address needs attention",
+}
+
+// Lifecycle says how shipments move along.
+type Lifecycle struct {
+	// Speed is how many times faster than real time shipments move, so a
+	// demo can watch one arrive. 0 is taken as 1.
+	Speed float64
+	// ExceptionRate is the share of shipments, from 0 to 1, that are held
+	// up on the way, arriving a day late.
+	ExceptionRate float64
+}
+
+// Validate checks the lifecycle can schedule shipments.
+func (l Lifecycle) Validate() error {
+	if l.Speed < 0 {
+		return fmt.Errorf("speed can't be negative, got %v", l.Speed)
+	}
+	if l.ExceptionRate < 0 || l.ExceptionRate > 1 {
+		return fmt.Errorf("exception rate must be between 0 and 1, got %v", l.ExceptionRate)
+	}
+	return nil
+}
+
+// step is an event that happens a while after the one before.
+type step struct {
+	Event
+	after time.Duration
+}
+
+// Schedule plans the events of the shipment p created at createdAt, from the
+// label being created to delivery. Whether the shipment is held up depends
+// only on its tracking ID, so the same shipment is always planned the same
+// way.
+//
+// The events are spaced as they would be in real time, with deliveries going
+// out on the morning of p.Delivery, and then brought closer to createdAt by
+// the lifecycle's speed.
+func (l Lifecycle) Schedule(p Plan, createdAt time.Time) []Event {
+	steps := []step{
+		{Event{Status: LabelCreated, Location: p.Origin, Description: "Shipping label created"}, 0},
+		{Event{Status: PickedUp, Location: p.Origin, Description: "Picked up by " + p.carrier()}, 2 * time.Hour},
+		{Event{Status: InTransit, Description: "Departed the origin facility"}, 4 * time.Hour},
+	}
+	delivery := day(p.Delivery)
+	r := rand.New(rand.NewSource(seed(p.TrackingID)))
+	if r.Float64() < l.ExceptionRate {
+		// held up on the way, and delivered a day late
+		steps = append(steps,
+			step{Event{Status: Exception, Description: exceptions[r.Intn(len(exceptions))]}, 12 * time.Hour},
+			step{Event{Status: InTransit, Description: "Back on its way"}, 12 * time.Hour},
+		)
+		delivery = delivery.AddDate(0, 0, 1)
+	}
+
+	var out []Event
+	at := createdAt
+	for _, s := range steps {
+		at = at.Add(s.after)
+		s.At = at
+		out = append(out, s.Event)
+	}
+	for _, e := range []struct {
+		Event
+		hour int
+	}{
+		{Event{Status: OutForDelivery, Location: p.Destination, Description: "Out for delivery"}, outForDeliveryHour},
+		{Event{Status: Delivered, Location: p.Destination, Description: "Delivered"}, deliveredHour},
+	} {
+		// leave at least an hour between events however soon the delivery day
+		at = maxTime(delivery.Add(time.Duration(e.hour)*time.Hour), at.Add(time.Hour))
+		e.At = at
+		out = append(out, e.Event)
+	}
+
+	speed := l.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	for i := range out {
+		out[i].At = createdAt.Add(time.Duration(float64(out[i].At.Sub(createdAt)) / speed))
+	}
+	return out
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// seed turns a tracking ID into a seed for the shipment's random choices.
+func seed(id string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return int64(h.Sum64())
+}
+
+// day truncates t to the start of its day in UTC.
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,122 @@
+package shipments
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// monday is a Monday morning, with the shipment due on Wednesday.
+var (
+	monday   = time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	testPlan = Plan{
+		TrackingID:  "AB-12345-6789012",
+		Carrier:     "Parcel Post",
+		Origin:      "Reno, NV",
+		Destination: "Mountain View, CA",
+		Delivery:    time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+// checkSchedule fails the test unless events are in order and each status can
+// follow the one before.
+func checkSchedule(t *testing.T, events []Event) {
+	t.Helper()
+	if len(events) == 0 || events[0].Status != LabelCreated || events[len(events)-1].Status != Delivered {
+		t.Fatalf("schedule %+v doesn't go from label created to delivered", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if !CanMove(events[i-1].Status, events[i].Status) {
+			t.Errorf("schedule moves from %s to %s", events[i-1].Status, events[i].Status)
+		}
+		if !events[i].At.After(events[i-1].At) {
+			t.Errorf("%s at %v isn't after %s at %v", events[i].Status, events[i].At, events[i-1].Status, events[i-1].At)
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	events := Lifecycle{}.Schedule(testPlan, monday)
+	checkSchedule(t, events)
+	want := []struct {
+		status Status
+		at     string
+	}{
+		{LabelCreated, "2026-10-19T09:00"},
+		{PickedUp, "2026-10-19T11:00"},
+		{InTransit, "2026-10-19T15:00"},
+		{OutForDelivery, "2026-10-21T08:00"},
+		{Delivered, "2026-10-21T14:00"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %+v", events)
+	}
+	for i, w := range want {
+		if got := events[i].At.Format("2006-01-02T15:04"); events[i].Status != w.status || got != w.at {
+			t.Errorf("event %d is %s at %s, want %s at %s", i, events[i].Status, got, w.status, w.at)
+		}
+	}
+	if events[1].Description != "Picked up by Parcel Post" || events[4].Location != "Mountain View, CA" {
+		t.Errorf("got %+v", events)
+	}
+}
+
+func TestScheduleSpeed(t *testing.T) {
+	normal := Lifecycle{}.Schedule(testPlan, monday)
+	fast := Lifecycle{Speed: 3600}.Schedule(testPlan, monday)
+	for i := range normal {
+		if got, want := fast[i].At.Sub(monday), normal[i].At.Sub(monday)/3600; got != want {
+			t.Errorf("%s is %v after the label at 3600x, want %v", fast[i].Status, got, want)
+		}
+	}
+}
+
+func TestScheduleDeliveryDayPassed(t *testing.T) {
+	p := testPlan
+	p.Delivery = monday.AddDate(0, 0, -1)
+	events := Lifecycle{}.Schedule(p, monday)
+	checkSchedule(t, events)
+	if got := events[len(events)-1].At.Sub(monday); got != 8*time.Hour {
+		t.Errorf("delivered %v after the label, want an hour after each event", got)
+	}
+}
+
+func TestScheduleExceptions(t *testing.T) {
+	held := 0
+	for i := 0; i < 200; i++ {
+		p := testPlan
+		p.TrackingID = fmt.Sprintf("ID-%d", i)
+		events := Lifecycle{ExceptionRate: 0.25}.Schedule(p, monday)
+		checkSchedule(t, events)
+		again := Lifecycle{ExceptionRate: 0.25}.Schedule(p, monday)
+		if len(again) != len(events) {
+			t.Fatalf("%s was planned two ways", p.TrackingID)
+		}
+		for _, e := range events {
+			if e.Status == Exception {
+				held++
+				if d := events[len(events)-1].At; d.Day() != 22 {
+					t.Errorf("%s was held up and still delivered on %v", p.TrackingID, d)
+				}
+			}
+		}
+	}
+	if held < 25 || held > 75 {
+		t.Errorf("%d of 200 shipments were held up at a rate of 0.25", held)
+	}
+
+	for _, e := range (Lifecycle{ExceptionRate: 1}).Schedule(testPlan, monday) {
+		if e.Status == Exception {
+			return
+		}
+	}
+	t.Error("a shipment wasn't held up at a rate of 1")
+}
+
+func TestLifecycleValidate(t *testing.T) {
+	for _, l := range []Lifecycle{{Speed: -1}, {ExceptionRate: -0.1}, {ExceptionRate: 1.5}} {
+		if l.Validate() == nil {
+			t.Errorf("%+v is valid", l)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package shipments
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for a tracking ID the store doesn't have.
+	ErrNotFound = errors.New("shipment not found")
+	// ErrFinal is returned for a change to a shipment that has been
+	// delivered or cancelled.
+	ErrFinal = errors.New("shipment already delivered or cancelled")
+)
+
+// Plan describes a shipment to track.
+type Plan struct {
+	TrackingID string
+	OptionID   string
+	Carrier    string
+	// Origin and Destination are where the shipment leaves from and goes
+	// to, as shown to the customer.
+	Origin      string
+	Destination string
+	// Delivery is the day the shipment is expected to arrive.
+	Delivery time.Time
+}
+
+func (p Plan) carrier() string {
+	if p.Carrier == "" {
+		return "the carrier"
+	}
+	return p.Carrier
+}
+
+// Shipment is a tracked shipment as of some time.
+type Shipment struct {
+	Plan
+	CreatedAt time.Time
+	Status    Status
+	// Events are what has happened to the shipment so far, oldest first.
+	Events []Event
+}
+
+// Transition is a shipment moving from one status to another.
+type Transition struct {
+	TrackingID string
+	From       Status
+	Event
+}
+
+// tracked is a shipment in the store and the events planned for it.
+type tracked struct {
+	Shipment
+	schedule []Event
+	// reached is how many events of schedule have happened
+	reached int
+}
+
+// advance moves s along its schedule to now, returning the transitions on the
+// way.
+func (s *tracked) advance(now time.Time) []Transition {
+	var out []Transition
+	for ; s.reached < len(s.schedule) && !s.schedule[s.reached].At.After(now); s.reached++ {
+		out = append(out, s.move(s.schedule[s.reached]))
+	}
+	return out
+}
+
+// move records the event e, which must be a status s can move to.
+func (s *tracked) move(e Event) Transition {
+	t := Transition{TrackingID: s.TrackingID, From: s.Status, Event: e}
+	if e.Status == Exception {
+		s.Delivery = s.Delivery.AddDate(0, 0, 1)
+	}
+	s.Status = e.Status
+	s.Events = append(s.Events, e)
+	return t
+}
+
+// snapshot copies the shipment so it can be read while s moves on.
+func (s *tracked) snapshot() Shipment {
+	out := s.Shipment
+	out.Events = append([]Event(nil), s.Events...)
+	return out
+}
+
+// Store keeps shipments in memory, moving them along as time passes. It is
+// safe for concurrent use.
+type Store struct {
+	// retention is how long delivered and cancelled shipments are kept after
+	// their last event, or 0 to keep them forever.
+	retention time.Duration
+
+	lock      sync.Mutex
+	shipments map[string]*tracked
+}
+
+// NewStore returns an empty store that keeps delivered and cancelled
+// shipments for retention after their last event, or forever if it is 0.
+func NewStore(retention time.Duration) *Store {
+	return &Store{retention: retention, shipments: make(map[string]*tracked)}
+}
+
+// Create starts tracking the shipment p at now, moving it along as l says.
+// The returned transitions are the ones already due, starting with the label
+// being created.
+func (s *Store) Create(p Plan, l Lifecycle, now time.Time) (Shipment, []Transition, error) {
+	if p.TrackingID == "" {
+		return Shipment{}, nil, errors.New("tracking ID is required")
+	}
+	if err := l.Validate(); err != nil {
+		return Shipment{}, nil, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.shipments[p.TrackingID]; ok {
+		return Shipment{}, nil, fmt.Errorf("tracking ID %q is already in use", p.TrackingID)
+	}
+	t := &tracked{
+		Shipment: Shipment{Plan: p, CreatedAt: now},
+		schedule: l.Schedule(p, now),
+	}
+	s.shipments[p.TrackingID] = t
+	transitions := t.advance(now)
+	return t.snapshot(), transitions, nil
+}
+
+// Get returns the shipment with the tracking ID as of now, and the
+// transitions it has made since it was last looked at.
+func (s *Store) Get(id string, now time.Time) (Shipment, []Transition, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.shipments[id]
+	if !ok {
+		return Shipment{}, nil, ErrNotFound
+	}
+	transitions := t.advance(now)
+	return t.snapshot(), transitions, nil
+}
+
+// Cancel stops the shipment with the tracking ID at now. It returns the
+// transitions the shipment made up to and including being cancelled.
+func (s *Store) Cancel(id string, now time.Time) (Shipment, []Transition, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.shipments[id]
+	if !ok {
+		return Shipment{}, nil, ErrNotFound
+	}
+	transitions := t.advance(now)
+	if !CanMove(t.Status, Cancelled) {
+		return t.snapshot(), transitions, ErrFinal
+	}
+	t.schedule = append(t.schedule[:t.reached], Event{Status: Cancelled, At: now, Description: "Shipment cancelled"})
+	transitions = append(transitions, t.advance(now)...)
+	return t.snapshot(), transitions, nil
+}
+
+// Advance moves every shipment along to now and returns the transitions they
+// made since they were last looked at, in the order they happened. It drops
+// finished shipments older than the store's retention.
+func (s *Store) Advance(now time.Time) []Transition {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var out []Transition
+	for id, t := range s.shipments {
+		out = append(out, t.advance(now)...)
+		if s.retention > 0 && t.Status.Final() && now.Sub(t.Events[len(t.Events)-1].At) > s.retention {
+			delete(s.shipments, id)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// Len returns the number of shipments in the store.
+func (s *Store) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.shipments)
+}
@@ -0,0 +1,141 @@
+package shipments
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func statuses(transitions []Transition) []Status {
+	var out []Status
+	for _, t := range transitions {
+		out = append(out, t.Status)
+	}
+	return out
+}
+
+func TestStoreMovesShipmentsAlong(t *testing.T) {
+	s := NewStore(0)
+	got, transitions, err := s.Create(testPlan, Lifecycle{}, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != LabelCreated || len(transitions) != 1 || transitions[0].From != "" {
+		t.Errorf("created %+v with transitions %+v", got, transitions)
+	}
+	if _, _, err := s.Create(testPlan, Lifecycle{}, monday); err == nil {
+		t.Error("a tracking ID was used twice")
+	}
+
+	got, transitions, err = s.Get(testPlan.TrackingID, monday.Add(7*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != InTransit || len(got.Events) != 3 || fmt.Sprint(statuses(transitions)) != "[picked_up in_transit]" {
+		t.Errorf("got %+v with transitions %+v, want the shipment in transit", got, transitions)
+	}
+	if transitions[1].From != PickedUp {
+		t.Errorf("got %+v, want a move from picked up", transitions[1])
+	}
+	if _, transitions, _ = s.Get(testPlan.TrackingID, monday.Add(7*time.Hour)); len(transitions) != 0 {
+		t.Errorf("transitions %+v were reported twice", transitions)
+	}
+
+	got, transitions, _ = s.Get(testPlan.TrackingID, monday.AddDate(0, 0, 3))
+	if got.Status != Delivered || len(transitions) != 2 {
+		t.Errorf("got %+v, want the shipment delivered", got)
+	}
+	if _, _, err := s.Cancel(testPlan.TrackingID, monday.AddDate(0, 0, 3)); !errors.Is(err, ErrFinal) {
+		t.Errorf("got %v cancelling a delivered shipment, want ErrFinal", err)
+	}
+	if _, _, err := s.Get("nope", monday); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for an unknown tracking ID, want ErrNotFound", err)
+	}
+}
+
+func TestStoreExceptionDelaysDelivery(t *testing.T) {
+	s := NewStore(0)
+	if _, _, err := s.Create(testPlan, Lifecycle{ExceptionRate: 1}, monday); err != nil {
+		t.Fatal(err)
+	}
+	got, _, _ := s.Get(testPlan.TrackingID, monday.Add(time.Hour))
+	if !got.Delivery.Equal(testPlan.Delivery) {
+		t.Errorf("delivery moved to %v before the shipment was held up", got.Delivery)
+	}
+	got, _, _ = s.Get(testPlan.TrackingID, monday.Add(24*time.Hour))
+	if got.Status != Exception || !got.Delivery.Equal(testPlan.Delivery.AddDate(0, 0, 1)) {
+		t.Errorf("got %+v, want a shipment held up and due a day late", got)
+	}
+}
+
+func TestStoreCancel(t *testing.T) {
+	s := NewStore(0)
+	if _, _, err := s.Create(testPlan, Lifecycle{}, monday); err != nil {
+		t.Fatal(err)
+	}
+	got, transitions, err := s.Cancel(testPlan.TrackingID, monday.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != Cancelled || fmt.Sprint(statuses(transitions)) != "[picked_up cancelled]" {
+		t.Errorf("got %+v with transitions %+v", got, transitions)
+	}
+	if got, _, _ := s.Get(testPlan.TrackingID, monday.AddDate(0, 0, 3)); got.Status != Cancelled {
+		t.Errorf("a cancelled shipment moved on to %s", got.Status)
+	}
+	if _, _, err := s.Cancel("nope", monday); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v cancelling an unknown shipment, want ErrNotFound", err)
+	}
+}
+
+func TestStoreAdvance(t *testing.T) {
+	s := NewStore(24 * time.Hour)
+	for i := 0; i < 3; i++ {
+		p := testPlan
+		p.TrackingID = fmt.Sprintf("ID-%d", i)
+		if _, _, err := s.Create(p, Lifecycle{}, monday.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := s.Cancel("ID-2", monday.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	transitions := s.Advance(monday.Add(3 * time.Hour))
+	if fmt.Sprint(statuses(transitions)) != "[picked_up picked_up]" || transitions[0].TrackingID != "ID-0" {
+		t.Errorf("got transitions %+v, want ID-0 then ID-1 picked up", transitions)
+	}
+	if s.Advance(monday.Add(26 * time.Hour)); s.Len() != 2 {
+		t.Errorf("the store holds %d shipments, want the cancelled one dropped", s.Len())
+	}
+	if s.Advance(monday.AddDate(0, 0, 5)); s.Len() != 0 {
+		t.Errorf("the store holds %d shipments after they were delivered", s.Len())
+	}
+}
+
+func TestStoreConcurrentUse(t *testing.T) {
+	s := NewStore(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				p := testPlan
+				p.TrackingID = fmt.Sprintf("ID-%d-%d", i, j)
+				now := monday.Add(time.Duration(j) * time.Hour)
+				if _, _, err := s.Create(p, Lifecycle{Speed: 10}, now); err != nil {
+					t.Error(err)
+					return
+				}
+				s.Get(p.TrackingID, now.Add(time.Hour))
+				if j%5 == 0 {
+					s.Cancel(p.TrackingID, now)
+				}
+				s.Advance(now)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
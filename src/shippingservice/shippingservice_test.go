@@ -1,9 +1,21 @@
 package main
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
 )
@@ -11,6 +23,11 @@ import (
 // TestGetQuote is a basic check on the GetQuote RPC service.
 func TestGetQuote(t *testing.T) {
 	s := server{}
+	// the expected price is from the legacy formula on the number of items
+	defer rateStrategy.Store(strategyZoned)
+	if err := setRateStrategy(strategyLegacy); err != nil {
+		t.Fatal(err)
+	}
 
 	// A basic test case to test logic and protobuf interactions.
 	req := &pb.GetQuoteRequest{
@@ -41,6 +58,78 @@ func TestGetQuote(t *testing.T) {
 	}
 }
 
+// TestGetQuoteZoned checks quotes from the default rates depend on where the
+// parcel goes and what the order costs.
+func TestGetQuoteZoned(t *testing.T) {
+	s := server{}
+	items := []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}}
+	for name, tc := range map[string]struct {
+		req   *pb.GetQuoteRequest
+		units int64
+		nanos int32
+	}{
+		"regional": {&pb.GetQuoteRequest{Address: &pb.Address{State: "CA", Country: "United States", ZipCode: 94043}, Items: items}, 6, 550000000},
+		"europe":   {&pb.GetQuoteRequest{Address: &pb.Address{City: "London", Country: "England"}, Items: items}, 28, 250000000},
+		"free": {&pb.GetQuoteRequest{
+			Address:     &pb.Address{State: "CA", Country: "United States", ZipCode: 94043},
+			Items:       items,
+			SubtotalUsd: &pb.Money{CurrencyCode: "USD", Units: 75},
+		}, 0, 0},
+		"free shipping is domestic": {&pb.GetQuoteRequest{
+			Address:     &pb.Address{City: "London", Country: "England"},
+			Items:       items,
+			SubtotalUsd: &pb.Money{CurrencyCode: "USD", Units: 75},
+		}, 28, 250000000},
+	} {
+		res, err := s.GetQuote(context.Background(), tc.req)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if res.CostUsd.GetUnits() != tc.units || res.CostUsd.GetNanos() != tc.nanos {
+			t.Errorf("%s: got %d.%09d, want %d.%09d", name, res.CostUsd.GetUnits(), res.CostUsd.GetNanos(), tc.units, tc.nanos)
+		}
+	}
+}
+
+// TestGetQuoteOptions checks every service offered to the destination is
+// quoted, the default first and in cost_usd.
+func TestGetQuoteOptions(t *testing.T) {
+	s := server{}
+	res, err := s.GetQuote(context.Background(), &pb.GetQuoteRequest{
+		Address: &pb.Address{State: "CA", Country: "United States", ZipCode: 94043},
+		Items:   []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, o := range res.Options {
+		ids = append(ids, o.Id)
+		if o.Carrier == "" || o.BusinessDays < 1 || len(o.EstimatedDeliveryDate) != len("2006-01-02") {
+			t.Errorf("TestGetQuoteOptions: option %v is incomplete", o)
+		}
+	}
+	if strings.Join(ids, ",") != "standard,express,overnight" {
+		t.Errorf("TestGetQuoteOptions: got options %v, want standard, express and overnight", ids)
+	}
+	if res.CostUsd.GetUnits() != res.Options[0].CostUsd.GetUnits() || res.CostUsd.GetNanos() != res.Options[0].CostUsd.GetNanos() {
+		t.Errorf("TestGetQuoteOptions: cost_usd %v isn't the cost of the default option", res.CostUsd)
+	}
+	if res.Options[1].CostUsd.GetUnits() <= res.Options[0].CostUsd.GetUnits() {
+		t.Errorf("TestGetQuoteOptions: express costs %v, no more than standard", res.Options[1].CostUsd)
+	}
+
+	// overnight isn't offered abroad
+	res, err = s.GetQuote(context.Background(), &pb.GetQuoteRequest{
+		Address: &pb.Address{City: "London", Country: "England"},
+		Items:   []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}},
+	})
+	if err != nil || len(res.Options) != 2 {
+		t.Errorf("TestGetQuoteOptions: got %v, %v, want standard and express to England", res.GetOptions(), err)
+	}
+}
+
 // TestShipOrder is a basic check on the ShipOrder RPC service.
 func TestShipOrder(t *testing.T) {
 	s := server{}
@@ -72,4 +161,194 @@ func TestShipOrder(t *testing.T) {
 	if len(res.TrackingId) != 18 {
 		t.Errorf("TestShipOrder: Tracking ID is malformed - has %d characters, %d expected", len(res.TrackingId), 18)
 	}
+	if err := ValidateTrackingId(res.TrackingId); err != nil {
+		t.Errorf("TestShipOrder: %v", err)
+	}
+}
+
+// TestCreateTrackingIdConcurrent checks tracking IDs made at the same time
+// are valid and never repeat.
+func TestCreateTrackingIdConcurrent(t *testing.T) {
+	const workers, each = 8, 1000
+	var (
+		lock sync.Mutex
+		seen = make(map[string]bool, workers*each)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				id := CreateTrackingId()
+				if err := ValidateTrackingId(id); err != nil {
+					t.Error(err)
+					return
+				}
+				lock.Lock()
+				if seen[id] {
+					t.Errorf("TestCreateTrackingIdConcurrent: %q was made twice", id)
+				}
+				seen[id] = true
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestShipOrderOption checks ShipOrder takes the option IDs GetQuote returns.
+func TestShipOrderOption(t *testing.T) {
+	s := server{}
+	req := &pb.ShipOrderRequest{
+		Address:          &pb.Address{StreetAddress: "1 Main St", City: "Reno", State: "NV", Country: "USA"},
+		Items:            []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}},
+		ShippingOptionId: "express",
+	}
+	if _, err := s.ShipOrder(context.Background(), req); err != nil {
+		t.Errorf("TestShipOrderOption: %v", err)
+	}
+	req.ShippingOptionId = "teleport"
+	if _, err := s.ShipOrder(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TestShipOrderOption: got %v for an unknown option, want InvalidArgument", err)
+	}
+}
+
+// TestShipOrderLambda checks ShipOrder uses tracking IDs from the lambda, and
+// falls back to making its own when the lambda fails, saying why on the span.
+func TestShipOrderLambda(t *testing.T) {
+	defer trackingLambda.Store(nil)
+	var broken atomic.Bool
+	lambda := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if broken.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, "LAMBDA-"+strings.Repeat("7", 10))
+	}))
+	defer lambda.Close()
+	if err := setTrackingLambdaURL(lambda.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	s := server{}
+	req := &pb.ShipOrderRequest{
+		Address: &pb.Address{StreetAddress: "1 Main St", City: "Reno", State: "NV", Country: "USA"},
+		Items:   []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}},
+	}
+	shipOrder := func() (string, map[attribute.Key]attribute.Value, []sdktrace.Event) {
+		recorder := tracetest.NewSpanRecorder()
+		ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("").Start(context.Background(), "ShipOrder")
+		res, err := s.ShipOrder(ctx, req)
+		span.End()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ended := range recorder.Ended() {
+			if ended.Name() == "ShipOrder" {
+				attrs := make(map[attribute.Key]attribute.Value)
+				for _, kv := range ended.Attributes() {
+					attrs[kv.Key] = kv.Value
+				}
+				return res.TrackingId, attrs, ended.Events()
+			}
+		}
+		t.Fatal("TestShipOrderLambda: no ShipOrder span")
+		return "", nil, nil
+	}
+
+	id, attrs, _ := shipOrder()
+	if id != "LAMBDA-7777777777" || attrs["app.tracking_id.source"].AsString() != "lambda" {
+		t.Errorf("TestShipOrderLambda: got %q from %s, want the lambda's ID", id, attrs["app.tracking_id.source"].AsString())
+	}
+
+	broken.Store(true)
+	id, attrs, events := shipOrder()
+	if err := ValidateTrackingId(id); err != nil {
+		t.Errorf("TestShipOrderLambda: got %v, want a local tracking ID", err)
+	}
+	if !attrs["app.tracking_lambda.fallback"].AsBool() || attrs["app.tracking_lambda.fallback_reason"].AsString() != "http_status" {
+		t.Errorf("TestShipOrderLambda: got attributes %v, want the fallback recorded", attrs)
+	}
+	var fallbacks int
+	for _, e := range events {
+		if e.Name == "tracking lambda fallback" {
+			fallbacks++
+		}
+	}
+	if fallbacks != 1 {
+		t.Errorf("TestShipOrderLambda: got %d fallback events, want 1", fallbacks)
+	}
+}
+
+// TestTrackShipment checks shipped orders can be tracked to delivery, and each
+// status change is recorded on the span.
+func TestTrackShipment(t *testing.T) {
+	s := server{}
+	ctx := context.Background()
+	defer shipmentLifecycle.Store(shipmentLifecycle.Load())
+	// a five day delivery takes under a second
+	if err := setShipmentSpeed("1000000"); err != nil {
+		t.Fatal(err)
+	}
+	req := &pb.ShipOrderRequest{
+		Address:          &pb.Address{StreetAddress: "1600 Amphitheatre Parkway", City: "Mountain View", State: "CA", Country: "United States", ZipCode: 94043},
+		Items:            []*pb.CartItem{{ProductId: "66VCHSJNUP", Quantity: 1}},
+		ShippingOptionId: "express",
+	}
+	shipped, err := s.ShipOrder(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Second)
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("").Start(ctx, "TrackShipment")
+	res, err := s.TrackShipment(ctx, &pb.TrackShipmentRequest{TrackingId: shipped.TrackingId})
+	span.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != pb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED || res.ShippingOptionId != "express" || res.Carrier == "" {
+		t.Errorf("TestTrackShipment: got %v, want an express shipment delivered", res)
+	}
+	if first, last := res.Events[0], res.Events[len(res.Events)-1]; first.Status != pb.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED || last.Location != "Mountain View, CA, United States" {
+		t.Errorf("TestTrackShipment: got events %v", res.Events)
+	}
+	events := recorder.Ended()[0].Events()
+	if len(events) != len(res.Events)-1 {
+		t.Errorf("TestTrackShipment: %d events on the span, want one for each status change since the label", len(events))
+	}
+	for _, e := range events {
+		if e.Name != "shipment status changed" {
+			t.Errorf("TestTrackShipment: unexpected span event %q", e.Name)
+		}
+	}
+
+	if _, err := s.CancelShipment(ctx, &pb.CancelShipmentRequest{TrackingId: shipped.TrackingId}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("TestTrackShipment: got %v cancelling a delivered shipment, want FailedPrecondition", err)
+	}
+	if _, err := s.TrackShipment(ctx, &pb.TrackShipmentRequest{TrackingId: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("TestTrackShipment: got %v for an unknown tracking ID, want NotFound", err)
+	}
+}
+
+// TestCancelShipment checks a cancelled shipment goes no further.
+func TestCancelShipment(t *testing.T) {
+	s := server{}
+	ctx := context.Background()
+	shipped, err := s.ShipOrder(ctx, &pb.ShipOrderRequest{Address: &pb.Address{City: "Reno", State: "NV", Country: "US"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CancelShipment(ctx, &pb.CancelShipmentRequest{TrackingId: shipped.TrackingId}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := s.TrackShipment(ctx, &pb.TrackShipmentRequest{TrackingId: shipped.TrackingId})
+	if err != nil || res.Status != pb.ShipmentStatus_SHIPMENT_STATUS_CANCELLED {
+		t.Errorf("TestCancelShipment: got %v, %v, want the shipment cancelled", res, err)
+	}
+	if _, err := s.CancelShipment(ctx, &pb.CancelShipmentRequest{TrackingId: "shipped-before-a-restart"}); err != nil {
+		t.Errorf("TestCancelShipment: got %v cancelling an untracked shipment", err)
+	}
 }
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/shipments"
+)
+
+const (
+	// defaultShipmentRetention is how long delivered and cancelled
+	// shipments can still be tracked.
+	defaultShipmentRetention = 7 * 24 * time.Hour
+	// shipmentTick is how often shipments are moved along when nobody is
+	// tracking them.
+	shipmentTick = 10 * time.Second
+)
+
+// shipmentStore holds every shipment since the service started.
+var shipmentStore = shipments.NewStore(defaultShipmentRetention)
+
+// shipmentLifecycle holds the shipments.Lifecycle new shipments follow.
+var shipmentLifecycle atomic.Value
+
+func init() {
+	shipmentLifecycle.Store(shipments.Lifecycle{Speed: 1, ExceptionRate: 0.05})
+}
+
+var shipmentStatuses = map[shipments.Status]pb.ShipmentStatus{
+	shipments.LabelCreated:   pb.ShipmentStatus_SHIPMENT_STATUS_LABEL_CREATED,
+	shipments.PickedUp:       pb.ShipmentStatus_SHIPMENT_STATUS_PICKED_UP,
+	shipments.InTransit:      pb.ShipmentStatus_SHIPMENT_STATUS_IN_TRANSIT,
+	shipments.OutForDelivery: pb.ShipmentStatus_SHIPMENT_STATUS_OUT_FOR_DELIVERY,
+	shipments.Delivered:      pb.ShipmentStatus_SHIPMENT_STATUS_DELIVERED,
+	shipments.Exception:      pb.ShipmentStatus_SHIPMENT_STATUS_EXCEPTION,
+	shipments.Cancelled:      pb.ShipmentStatus_SHIPMENT_STATUS_CANCELLED,
+}
+
+// setShipmentSpeed changes how many times faster than real time new shipments
+// move along.
+func setShipmentSpeed(v string) error {
+	apply, err := parseShipmentSpeed(v)
+	if err != nil {
+		return err
+	}
+	apply()
+	return nil
+}
+
+// parseShipmentSpeed checks v is a valid speed, and returns a function that
+// changes to it.
+func parseShipmentSpeed(v string) (func(), error) {
+	speed, err := strconv.ParseFloat(v, 64)
+	if err != nil || speed <= 0 {
+		return nil, fmt.Errorf("speed must be a positive number, got %q", v)
+	}
+	return func() {
+		l := shipmentLifecycle.Load().(shipments.Lifecycle)
+		l.Speed = speed
+		shipmentLifecycle.Store(l)
+	}, nil
+}
+
+// configureShipmentsFromEnv sets up the lifecycle from SHIPMENT_SPEED and
+// SHIPMENT_EXCEPTION_RATE, and the store from SHIPMENT_RETENTION, when they
+// are set.
+func configureShipmentsFromEnv() error {
+	if v := os.Getenv("SHIPMENT_SPEED"); v != "" {
+		if err := setShipmentSpeed(v); err != nil {
+			return fmt.Errorf("SHIPMENT_SPEED: %w", err)
+		}
+	}
+	if v := os.Getenv("SHIPMENT_EXCEPTION_RATE"); v != "" {
+		l := shipmentLifecycle.Load().(shipments.Lifecycle)
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("SHIPMENT_EXCEPTION_RATE: %w", err)
+		}
+		l.ExceptionRate = rate
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("SHIPMENT_EXCEPTION_RATE: %w", err)
+		}
+		shipmentLifecycle.Store(l)
+	}
+	if v := os.Getenv("SHIPMENT_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("SHIPMENT_RETENTION: %w", err)
+		}
+		shipmentStore = shipments.NewStore(d)
+	}
+	return nil
+}
+
+// planShipment works out the carrier and delivery day of the shipment in, sent
+// with the option it names at now.
+func planShipment(in *pb.ShipOrderRequest, trackingID string, now time.Time) shipments.Plan {
+	origin := rateEngine.Origin()
+	p := shipments.Plan{
+		TrackingID:  trackingID,
+		OptionID:    in.ShippingOptionId,
+		Carrier:     legacyCarrier,
+		Origin:      joinNonEmpty(strings.TrimSpace(origin.State+" "+origin.Zip), origin.Country),
+		Destination: joinNonEmpty(in.GetAddress().GetCity(), in.GetAddress().GetState(), in.GetAddress().GetCountry()),
+		Delivery:    rateEngine.Calendar().Delivery(now, legacyBusinessDays),
+	}
+	if p.OptionID == "" {
+		p.OptionID = legacyOptionID
+	}
+	if strategy, _ := rateStrategy.Load().(string); strategy == strategyLegacy {
+		return p
+	}
+	quotes, err := rateEngine.Quote(shipmentFromRequest(&pb.GetQuoteRequest{Address: in.Address, Items: in.Items}), now)
+	if err != nil {
+		return p
+	}
+	for _, q := range quotes {
+		if q.ServiceID == in.ShippingOptionId || in.ShippingOptionId == "" {
+			p.OptionID, p.Carrier, p.Delivery = q.ServiceID, q.Carrier, q.Delivery
+			break
+		}
+	}
+	return p
+}
+
+func joinNonEmpty(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// recordTransitions adds an event to span for each status change.
+func recordTransitions(span trace.Span, transitions []shipments.Transition) {
+	for _, t := range transitions {
+		span.AddEvent("shipment status changed",
+			trace.WithTimestamp(t.At),
+			trace.WithAttributes(
+				attribute.String("app.tracking_id", t.TrackingID),
+				attribute.String("app.shipment.from_status", string(t.From)),
+				attribute.String("app.shipment.status", string(t.Status)),
+				attribute.String("app.shipment.location", t.Location),
+				attribute.String("app.shipment.description", t.Description),
+			))
+		log.WithFields(logrus.Fields{
+			"tracking_id": t.TrackingID,
+			"from":        t.From,
+			"status":      t.Status,
+		}).Debug("shipment status changed")
+	}
+}
+
+// advanceShipments moves shipments along every tick until ctx is done, so
+// their transitions are recorded even if nobody tracks them.
+func advanceShipments(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	tracer := otel.GetTracerProvider().Tracer("")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			transitions := shipmentStore.Advance(now)
+			if len(transitions) == 0 {
+				continue
+			}
+			_, span := tracer.Start(ctx, "advanceShipments")
+			span.SetAttributes(
+				attribute.Int("app.shipments.transitions", len(transitions)),
+				attribute.Int("app.shipments.tracked", shipmentStore.Len()),
+			)
+			recordTransitions(span, transitions)
+			span.End()
+		}
+	}
+}
+
+// TrackShipment returns where the shipment with the tracking ID has got to.
+func (s *server) TrackShipment(ctx context.Context, in *pb.TrackShipmentRequest) (*pb.TrackShipmentResponse, error) {
+	log.Infof("[TrackShipment] received request for tracking_id=%q", in.TrackingId)
+	defer log.Info("[TrackShipment] completed request")
+	if in.TrackingId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "tracking_id is required")
+	}
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("app.tracking_id", in.TrackingId))
+	mockDatabaseCall(ctx, 30, "SELECT shipping.shipments", "SELECT * FROM shipments WHERE tracking_id = ?")
+
+	shipment, transitions, err := shipmentStore.Get(in.TrackingId, time.Now())
+	if errors.Is(err, shipments.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "no shipment with tracking ID %q", in.TrackingId)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to track shipment: %v", err)
+	}
+	recordTransitions(span, transitions)
+	span.SetAttributes(attribute.String("app.shipment.status", string(shipment.Status)))
+
+	out := &pb.TrackShipmentResponse{
+		TrackingId:            shipment.TrackingID,
+		Status:                shipmentStatuses[shipment.Status],
+		Carrier:               shipment.Carrier,
+		ShippingOptionId:      shipment.OptionID,
+		EstimatedDeliveryDate: shipment.Delivery.Format("2006-01-02"),
+		Events:                make([]*pb.ShipmentEvent, len(shipment.Events)),
+	}
+	for i, e := range shipment.Events {
+		out.Events[i] = &pb.ShipmentEvent{
+			Status:      shipmentStatuses[e.Status],
+			Time:        e.At.Unix(),
+			Location:    e.Location,
+			Description: e.Description,
+		}
+	}
+	return out, nil
+}
@@ -2,41 +2,184 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+
+	pb "github.com/honeycombio/microservices-demo/src/shippingservice/demo/msdemo"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/trackingid"
+	"github.com/honeycombio/microservices-demo/src/shippingservice/trackinglambda"
 )
 
-// seeded determines if the random number generator is ready.
-var seeded = false
+// trackingIDs holds the *trackingid.Generator that makes tracking IDs locally.
+var trackingIDs atomic.Pointer[trackingid.Generator]
 
-// CreateTrackingId generates a tracking ID.
-func CreateTrackingId(salt string) string {
-	if !seeded {
-		rand.Seed(time.Now().UnixNano())
-		seeded = true
+func init() {
+	host, _ := os.Hostname()
+	g, err := trackingid.New(trackingid.NodeFor(host))
+	if err != nil {
+		panic(fmt.Sprintf("failed to create the tracking ID generator: %v", err))
 	}
+	trackingIDs.Store(g)
+}
 
-	return fmt.Sprintf("%c%c-%d%s-%d%s",
-		getRandomLetterCode(),
-		getRandomLetterCode(),
-		len(salt),
-		getRandomNumber(3),
-		len(salt)/2,
-		getRandomNumber(7),
-	)
+var (
+	// trackingLambda is the client for the tracking ID lambda, or nil to
+	// generate tracking IDs locally.
+	trackingLambda atomic.Pointer[trackinglambda.Client]
+	// trackingLambdaConfig is how new tracking lambda clients are set up,
+	// apart from the URL.
+	trackingLambdaConfig trackinglambda.Config
+	// trackingLambdaHosts are the hosts the admin API may point the tracking
+	// lambda at. An entry starting with "*." matches any subdomain.
+	trackingLambdaHosts []string
+)
+
+// configureTrackingIDsFromEnv sets the node prefix of tracking IDs from
+// TRACKING_NODE, when it is set, instead of deriving it from the host name.
+func configureTrackingIDsFromEnv() error {
+	node := os.Getenv("TRACKING_NODE")
+	if node == "" {
+		return nil
+	}
+	g, err := trackingid.New(node)
+	if err != nil {
+		return fmt.Errorf("TRACKING_NODE: %w", err)
+	}
+	trackingIDs.Store(g)
+	return nil
 }
 
-// getRandomLetterCode generates a code point value for a capital letter.
-func getRandomLetterCode() uint32 {
-	return 65 + uint32(rand.Intn(25))
+// CreateTrackingId generates a tracking ID no other call in this process has
+// returned. See the trackingid package for the format.
+func CreateTrackingId() string {
+	return trackingIDs.Load().New()
 }
 
-// getRandomNumber generates a string representation of a number with the requested number of digits.
-func getRandomNumber(digits int) string {
-	str := ""
-	for i := 0; i < digits; i++ {
-		str = fmt.Sprintf("%s%d", str, rand.Intn(10))
+// ValidateTrackingId checks id is a well-formed tracking ID with the right
+// check digit.
+func ValidateTrackingId(id string) error {
+	return trackingid.Validate(id)
+}
+
+// setTrackingLambdaURL switches tracking IDs to come from the lambda at v, or
+// to be generated locally if v is empty. The new client starts with its
+// circuit closed.
+func setTrackingLambdaURL(v string) error {
+	apply, err := parseTrackingLambdaURL(v)
+	if err != nil {
+		return err
+	}
+	apply()
+	return nil
+}
+
+// parseTrackingLambdaURL checks v like setTrackingLambdaURL, and returns a
+// function that makes the switch.
+func parseTrackingLambdaURL(v string) (func(), error) {
+	if v == "" {
+		return func() { trackingLambda.Store(nil) }, nil
+	}
+	cfg := trackingLambdaConfig
+	cfg.URL = v
+	c, err := trackinglambda.New(cfg)
+	if err != nil {
+		return nil, err
 	}
+	return func() { trackingLambda.Store(c) }, nil
+}
 
-	return str
+// checkTrackingLambdaHost checks the URL v points at one of the
+// trackingLambdaHosts, so the admin API can't make the service post
+// addresses to an arbitrary host.
+func checkTrackingLambdaHost(v string) error {
+	if v == "" {
+		return nil
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range trackingLambdaHosts {
+		if host == allowed || strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %q isn't in TRACKING_LAMBDA_ALLOWED_HOSTS", u.Hostname())
+}
+
+// configureTrackingLambdaFromEnv sets up the tracking lambda from
+// TRACKING_LAMBDA_URL, with TRACKING_LAMBDA_TIMEOUT and
+// TRACKING_LAMBDA_ATTEMPTS overriding the client's defaults when they are set.
+// The admin API may switch to the host of TRACKING_LAMBDA_URL or one listed in
+// TRACKING_LAMBDA_ALLOWED_HOSTS.
+func configureTrackingLambdaFromEnv() error {
+	if v := os.Getenv("TRACKING_LAMBDA_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("TRACKING_LAMBDA_TIMEOUT: %w", err)
+		}
+		trackingLambdaConfig.Timeout = d
+	}
+	if v := os.Getenv("TRACKING_LAMBDA_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("TRACKING_LAMBDA_ATTEMPTS: %w", err)
+		}
+		trackingLambdaConfig.Attempts = n
+	}
+	for _, host := range strings.Split(os.Getenv("TRACKING_LAMBDA_ALLOWED_HOSTS"), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			trackingLambdaHosts = append(trackingLambdaHosts, host)
+		}
+	}
+	if err := setTrackingLambdaURL(os.Getenv("TRACKING_LAMBDA_URL")); err != nil {
+		return fmt.Errorf("TRACKING_LAMBDA_URL: %w", err)
+	}
+	if lambda := trackingLambda.Load(); lambda != nil {
+		u, _ := url.Parse(lambda.URL())
+		trackingLambdaHosts = append(trackingLambdaHosts, strings.ToLower(u.Hostname()))
+	}
+	return nil
+}
+
+// newTrackingId gets a tracking ID for the shipment to address from the
+// tracking lambda, if there is one. When the lambda can't supply one, it falls
+// back to CreateTrackingId and records why on the span in ctx.
+func newTrackingId(ctx context.Context, address *pb.Address) string {
+	span := trace.SpanFromContext(ctx)
+	lambda := trackingLambda.Load()
+	if lambda == nil {
+		span.SetAttributes(attribute.String("app.tracking_id.source", "local"))
+		return CreateTrackingId()
+	}
+	baseAddress := fmt.Sprintf("%s, %s, %s", address.GetStreetAddress(), address.GetCity(), address.GetState())
+	id, err := lambda.TrackingID(ctx, baseAddress)
+	if err == nil {
+		span.SetAttributes(attribute.String("app.tracking_id.source", "lambda"))
+		return id
+	}
+
+	id = CreateTrackingId()
+	reason := trackinglambda.Reason(err)
+	span.SetAttributes(
+		attribute.String("app.tracking_id.source", "local"),
+		attribute.Bool("app.tracking_lambda.fallback", true),
+		attribute.String("app.tracking_lambda.fallback_reason", reason),
+		attribute.String("app.tracking_lambda.circuit", string(lambda.State())),
+	)
+	span.AddEvent("tracking lambda fallback", trace.WithAttributes(
+		attribute.String("app.tracking_lambda.fallback_reason", reason),
+		attribute.String("app.tracking_lambda.error", err.Error()),
+	))
+	log.WithField("reason", reason).Warnf("falling back to a local tracking ID: %v", err)
+	return id
 }
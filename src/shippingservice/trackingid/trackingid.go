@@ -0,0 +1,146 @@
+// Package trackingid generates and checks shipment tracking IDs.
+//
+// A tracking ID looks like AB-123456-78901234: 18 characters made of
+//
+//   - a node prefix of two capital letters, which tells the replicas of the
+//     service apart;
+//   - a dash and six digits, then a dash and seven more, which together are
+//     the 13-digit serial of the ID;
+//   - a check digit.
+//
+// The check digit is the Luhn check digit of the prefix and serial, with the
+// letters of the prefix read as two digits each, A as 10 up to Z as 35. It
+// catches any single mistyped digit and most swapped pairs of them, and most
+// mistyped prefix letters.
+//
+// A Generator hands out serials from a counter that starts at a random point
+// and scrambles them with a bijection, so its IDs don't look sequential but
+// none repeats until 10^13 have been made. Generators with different node
+// prefixes never make the same ID.
+package trackingid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// Length is the length of every tracking ID.
+	Length = 18
+
+	serialDigits = 13
+	// serials is how many serials there are, 10^serialDigits.
+	serials = 10_000_000_000_000
+	// scramble and offset turn a count into a serial with
+	// count*scramble + offset mod serials. scramble shares no factors with
+	// serials, so every count below serials gets a different serial.
+	scramble = 7_368_219_450_611
+	offset   = 4_102_967_381_557
+)
+
+// ErrInvalid is returned, wrapped, for a tracking ID that isn't well formed
+// or whose check digit doesn't match.
+var ErrInvalid = errors.New("invalid tracking ID")
+
+// Generator makes tracking IDs. It is safe for concurrent use.
+type Generator struct {
+	node  string
+	count atomic.Uint64
+}
+
+// New returns a generator for the node prefix, two capital letters.
+func New(node string) (*Generator, error) {
+	if len(node) != 2 || !isLetter(node[0]) || !isLetter(node[1]) {
+		return nil, fmt.Errorf("node prefix must be two capital letters, got %q", node)
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, fmt.Errorf("failed to seed the generator: %w", err)
+	}
+	g := &Generator{node: node}
+	g.count.Store(binary.BigEndian.Uint64(b[:]) % serials)
+	return g, nil
+}
+
+// Node returns the generator's node prefix.
+func (g *Generator) Node() string { return g.node }
+
+// New returns a tracking ID no other call to New on g has returned.
+func (g *Generator) New() string {
+	n := (g.count.Add(1) - 1) % serials
+	hi, lo := bits.Mul64(n, scramble)
+	_, serial := bits.Div64(hi, lo, serials)
+	serial = (serial + offset) % serials
+
+	digits := fmt.Sprintf("%0*d", serialDigits, serial)
+	body := g.node + digits
+	return fmt.Sprintf("%s-%s-%s%c", g.node, digits[:6], digits[6:], checkDigit(body))
+}
+
+// NodeFor derives a node prefix from name, such as the host name of a
+// replica. Different names usually get different prefixes, but with 676 of
+// them two replicas can share one; set the prefix explicitly to rule that out.
+func NodeFor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	n := h.Sum32() % (26 * 26)
+	return string([]byte{'A' + byte(n/26), 'A' + byte(n%26)})
+}
+
+// Validate checks id is a well-formed tracking ID with the right check digit.
+func Validate(id string) error {
+	if len(id) != Length {
+		return fmt.Errorf("%w: %q has %d characters, want %d", ErrInvalid, id, len(id), Length)
+	}
+	if !isLetter(id[0]) || !isLetter(id[1]) || id[2] != '-' || id[9] != '-' {
+		return fmt.Errorf("%w: %q doesn't look like AB-123456-78901234", ErrInvalid, id)
+	}
+	digits := id[3:9] + id[10:]
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return fmt.Errorf("%w: %q doesn't look like AB-123456-78901234", ErrInvalid, id)
+		}
+	}
+	body := id[:2] + digits[:serialDigits]
+	if want := checkDigit(body); digits[serialDigits] != want {
+		return fmt.Errorf("%w: %q has check digit %c, want %c", ErrInvalid, id, digits[serialDigits], want)
+	}
+	return nil
+}
+
+// checkDigit returns the Luhn check digit of body, which is capital letters
+// and digits.
+func checkDigit(body string) byte {
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if isLetter(body[i]) {
+			fmt.Fprintf(&b, "%d", body[i]-'A'+10)
+		} else {
+			b.WriteByte(body[i])
+		}
+	}
+	s := b.String()
+	sum := 0
+	// double every other digit, starting with the rightmost
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if (len(s)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isLetter(c byte) bool { return c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
@@ -0,0 +1,147 @@
+package trackingid
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func newTestGenerator(t *testing.T, node string) *Generator {
+	t.Helper()
+	g, err := New(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestNew(t *testing.T) {
+	g := newTestGenerator(t, "AB")
+	for i := 0; i < 1000; i++ {
+		id := g.New()
+		if err := Validate(id); err != nil {
+			t.Fatal(err)
+		}
+		if id[:3] != "AB-" {
+			t.Fatalf("%q doesn't start with the node prefix", id)
+		}
+	}
+	for _, node := range []string{"", "A", "ABC", "ab", "A1"} {
+		if _, err := New(node); err == nil {
+			t.Errorf("New accepted the node prefix %q", node)
+		}
+	}
+}
+
+// TestEveryLetter checks node prefixes can use the whole alphabet, Z included.
+func TestEveryLetter(t *testing.T) {
+	seen := map[byte]bool{}
+	for i := 0; i < 5000; i++ {
+		n := NodeFor(string(rune(i)))
+		seen[n[0]], seen[n[1]] = true, true
+	}
+	if len(seen) != 26 || !seen['Z'] {
+		t.Errorf("node prefixes use %d letters, want all 26", len(seen))
+	}
+	if err := Validate(newTestGenerator(t, "ZZ").New()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSerialsDontRepeat(t *testing.T) {
+	g := newTestGenerator(t, "AB")
+	// wrap around the end of the serials
+	g.count.Store(serials - 500)
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id := g.New()
+		if seen[id] {
+			t.Fatalf("%q was made twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestValidate(t *testing.T) {
+	id := newTestGenerator(t, "QZ").New()
+	if err := Validate(id); err != nil {
+		t.Fatal(err)
+	}
+	// every single mistyped digit is caught
+	for i := 3; i < len(id); i++ {
+		for c := byte('0'); c <= '9'; c++ {
+			if id[i] == '-' || c == id[i] {
+				continue
+			}
+			typo := id[:i] + string(c) + id[i+1:]
+			if err := Validate(typo); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Validate(%q) = %v for a typo of %q", typo, err, id)
+			}
+		}
+	}
+	// and most mistyped prefix letters
+	missed := 0
+	for i := 0; i < 2; i++ {
+		for c := byte('A'); c <= 'Z'; c++ {
+			if c != id[i] && Validate(id[:i]+string(c)+id[i+1:]) == nil {
+				missed++
+			}
+		}
+	}
+	if missed > 10 {
+		t.Errorf("%d of 50 mistyped prefix letters of %q were missed", missed, id)
+	}
+	for _, bad := range []string{
+		"",
+		"AB-123456-7890123",
+		"ab-123456-78901234",
+		"AB_123456_78901234",
+		"AB-12345X-78901234",
+		// the original format
+		"XY-20123-101234567",
+	} {
+		if err := Validate(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%q) = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	// the Luhn check digit of 79927398713 is 3
+	if got := checkDigit("7992739871"); got != '3' {
+		t.Errorf("checkDigit(7992739871) = %c, want 3", got)
+	}
+	// A is read as 10
+	if checkDigit("A5") != checkDigit("105") {
+		t.Error("letters aren't read as their two-digit values")
+	}
+}
+
+func TestConcurrentGenerators(t *testing.T) {
+	const workers, each = 16, 2000
+	generators := []*Generator{newTestGenerator(t, "AA"), newTestGenerator(t, "AB")}
+	ids := make(chan string, workers*each)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(g *Generator) {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				ids <- g.New()
+			}
+		}(generators[w%len(generators)])
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, workers*each)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("%q was made twice", id)
+		}
+		seen[id] = true
+		if err := Validate(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
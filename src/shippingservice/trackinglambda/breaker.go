@@ -0,0 +1,91 @@
+package trackinglambda
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the state of a Breaker.
+type State string
+
+const (
+	// Closed lets every call through.
+	Closed State = "closed"
+	// Open fails calls without making them.
+	Open State = "open"
+	// HalfOpen lets one trial call through to see whether to close again.
+	HalfOpen State = "half_open"
+)
+
+// Breaker is a circuit breaker. It opens after a number of failures in a row,
+// stays open for a cooldown, and then lets a single trial call through: if it
+// succeeds the breaker closes, otherwise it opens for another cooldown. It is
+// safe for concurrent use.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	lock     sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewBreaker returns a closed breaker that opens after threshold failures in
+// a row and stays open for cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown, now: time.Now, state: Closed}
+}
+
+// Allow reports whether a call can be made now. Every allowed call must be
+// followed by Success or Failure.
+func (b *Breaker) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case Open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state, b.trial = HalfOpen, true
+		return true
+	case HalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return true
+}
+
+// Success records a call that worked, closing the breaker.
+func (b *Breaker) Success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.state, b.failures, b.trial = Closed, 0, false
+}
+
+// Failure records a call that failed, opening the breaker after a failed
+// trial or too many failures in a row.
+func (b *Breaker) Failure() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt, b.trial = Open, b.now(), false
+	}
+}
+
+// State returns the state of the breaker. An open breaker whose cooldown has
+// passed is reported as half open.
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
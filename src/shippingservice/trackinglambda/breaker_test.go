@@ -0,0 +1,53 @@
+package trackinglambda
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBreaker checks the breaker opens after enough failures, lets one trial
+// through after the cooldown, and closes again when it works.
+func TestBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(3, time.Minute)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatalf("TestBreaker: call %d not allowed before the threshold", i)
+		}
+		b.Failure()
+	}
+	// a success resets the count
+	b.Allow()
+	b.Success()
+	for i := 0; i < 3; i++ {
+		b.Allow()
+		b.Failure()
+	}
+	if b.Allow() || b.State() != Open {
+		t.Fatalf("TestBreaker: got %s, want open after 3 failures in a row", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if b.State() != HalfOpen {
+		t.Errorf("TestBreaker: got %s after the cooldown, want half_open", b.State())
+	}
+	if !b.Allow() {
+		t.Fatal("TestBreaker: trial not allowed after the cooldown")
+	}
+	if b.Allow() {
+		t.Error("TestBreaker: a second call was allowed during the trial")
+	}
+	b.Failure()
+	if b.Allow() || b.State() != Open {
+		t.Fatalf("TestBreaker: got %s after a failed trial, want open", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	b.Allow()
+	b.Success()
+	if b.State() != Closed || !b.Allow() || !b.Allow() {
+		t.Errorf("TestBreaker: got %s after a successful trial, want closed", b.State())
+	}
+}
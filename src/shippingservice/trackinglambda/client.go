@@ -0,0 +1,250 @@
+// Package trackinglambda gets tracking IDs from the external tracking Lambda.
+//
+// The Lambda is called over HTTP with the shipping address as the body and
+// answers with a tracking ID. A Client gives each attempt a timeout, retries
+// failed attempts with jittered exponential backoff, checks the answer looks
+// like a tracking ID, and stops calling the Lambda for a while when it keeps
+// failing, so callers can fall back to making tracking IDs themselves.
+package trackinglambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxResponse is the most bytes of a response that are read.
+const maxResponse = 1024
+
+var (
+	// ErrCircuitOpen is returned without calling the Lambda while it is
+	// failing.
+	ErrCircuitOpen = errors.New("tracking lambda circuit is open")
+	// ErrInvalidResponse is returned, wrapped, when the Lambda answers with
+	// something that isn't a tracking ID.
+	ErrInvalidResponse = errors.New("invalid tracking lambda response")
+	// ErrStatus is returned, wrapped, when the Lambda answers with an HTTP
+	// error.
+	ErrStatus = errors.New("tracking lambda returned an error")
+)
+
+// Config configures a Client. Zero fields take the defaults in brackets.
+type Config struct {
+	// URL is the Lambda's URL.
+	URL string
+	// Timeout is how long each attempt can take [2s].
+	Timeout time.Duration
+	// Attempts is how many times a call tries the Lambda [3].
+	Attempts int
+	// BaseDelay is the most to wait before the first retry, doubling for
+	// each retry after it up to MaxDelay [100ms, 1s].
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// FailureThreshold is how many failed attempts in a row open the
+	// circuit, which stays open for Cooldown [5, 30s].
+	FailureThreshold int
+	Cooldown         time.Duration
+	// Validate checks a tracking ID from the Lambda [Plausible].
+	Validate func(string) error
+	// HTTPClient makes the calls [a client traced with otelhttp].
+	HTTPClient *http.Client
+}
+
+// Client calls the tracking Lambda. It is safe for concurrent use.
+type Client struct {
+	cfg     Config
+	breaker *Breaker
+}
+
+// New returns a client for the Lambda described by cfg.
+func New(cfg Config) (*Client, error) {
+	if !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+		return nil, fmt.Errorf("%q is not an http(s) URL", cfg.URL)
+	}
+	if cfg.Timeout < 0 || cfg.Attempts < 0 || cfg.BaseDelay < 0 || cfg.MaxDelay < 0 || cfg.FailureThreshold < 0 || cfg.Cooldown < 0 {
+		return nil, errors.New("tracking lambda settings can't be negative")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.Attempts == 0 {
+		cfg.Attempts = 3
+	}
+	if cfg.BaseDelay == 0 {
+		cfg.BaseDelay = 100 * time.Millisecond
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = time.Second
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	if cfg.Validate == nil {
+		cfg.Validate = Plausible
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	}
+	return &Client{cfg: cfg, breaker: NewBreaker(cfg.FailureThreshold, cfg.Cooldown)}, nil
+}
+
+// URL returns the Lambda's URL.
+func (c *Client) URL() string { return c.cfg.URL }
+
+// State returns the state of the client's circuit breaker.
+func (c *Client) State() State { return c.breaker.State() }
+
+// TrackingID asks the Lambda for a tracking ID for a shipment to address.
+// Each failed attempt is added as an event to the span in ctx.
+func (c *Client) TrackingID(ctx context.Context, address string) (string, error) {
+	span := trace.SpanFromContext(ctx)
+	var err error
+	for attempt := 1; attempt <= c.cfg.Attempts; attempt++ {
+		if attempt > 1 {
+			if waitErr := sleep(ctx, c.backoff(attempt-1)); waitErr != nil {
+				return "", fmt.Errorf("%w (last attempt: %v)", waitErr, err)
+			}
+		}
+		if !c.breaker.Allow() {
+			if err != nil {
+				return "", fmt.Errorf("%w (last attempt: %v)", ErrCircuitOpen, err)
+			}
+			return "", ErrCircuitOpen
+		}
+		var id string
+		id, err = c.attempt(ctx, address)
+		if err == nil {
+			c.breaker.Success()
+			return id, nil
+		}
+		c.breaker.Failure()
+		span.AddEvent("tracking lambda attempt failed", trace.WithAttributes(
+			attribute.Int("app.tracking_lambda.attempt", attempt),
+			attribute.String("app.tracking_lambda.error", err.Error()),
+		))
+		if !retryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return "", err
+}
+
+// attempt calls the Lambda once.
+func (c *Client) attempt(ctx context.Context, address string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, strings.NewReader(address))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/text")
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse+1))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &statusError{code: resp.StatusCode}
+	}
+	if len(body) > maxResponse {
+		return "", fmt.Errorf("%w: more than %d bytes", ErrInvalidResponse, maxResponse)
+	}
+	id := strings.TrimSpace(string(body))
+	if err := c.cfg.Validate(id); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return id, nil
+}
+
+// backoff returns how long to wait before the retry-th retry: a random time
+// up to BaseDelay doubled for each retry before it, capped at MaxDelay.
+func (c *Client) backoff(retry int) time.Duration {
+	max := c.cfg.BaseDelay
+	for i := 1; i < retry && max < c.cfg.MaxDelay; i++ {
+		max *= 2
+	}
+	if max > c.cfg.MaxDelay {
+		max = c.cfg.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// statusError is an HTTP error from the Lambda.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v: %d %s", ErrStatus, e.code, http.StatusText(e.code))
+}
+
+func (e *statusError) Unwrap() error { return ErrStatus }
+
+// retryable reports whether another attempt might get past err. Server errors,
+// throttling, timeouts and network errors are retried; a bad request or a bad
+// answer would only happen again.
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500 || se.code == http.StatusTooManyRequests || se.code == http.StatusRequestTimeout
+	}
+	return !errors.Is(err, ErrInvalidResponse)
+}
+
+// Reason sums up why a call failed, for telemetry: circuit_open,
+// invalid_response, http_status, timeout or unavailable.
+func Reason(err error) string {
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, ErrInvalidResponse):
+		return "invalid_response"
+	case errors.Is(err, ErrStatus):
+		return "http_status"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "unavailable"
+}
+
+// Plausible checks id could be a tracking ID: 1 to 64 letters, digits, dashes
+// and underscores. The Lambda's IDs predate the trackingid format, so they
+// aren't held to it.
+func Plausible(id string) error {
+	if id == "" || len(id) > 64 {
+		return fmt.Errorf("tracking ID must be 1 to 64 characters, got %d", len(id))
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("tracking ID %q has a %q in it", id, r)
+		}
+	}
+	return nil
+}
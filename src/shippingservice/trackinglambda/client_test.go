@@ -0,0 +1,169 @@
+package trackinglambda
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// lambda is a stand-in for the tracking Lambda that fails its first calls.
+type lambda struct {
+	calls atomic.Int32
+	// failures is how many calls fail before it answers with id
+	failures int32
+	// fail is how a call fails
+	fail func(w http.ResponseWriter)
+	id   string
+}
+
+func (l *lambda) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if r.Method != http.MethodPost || string(body) != "1600 Amphitheatre Parkway, Mountain View, CA" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if l.calls.Add(1) <= l.failures {
+		l.fail(w)
+		return
+	}
+	io.WriteString(w, l.id+"\n")
+}
+
+func newTestClient(t *testing.T, l *lambda, cfg Config) *Client {
+	srv := httptest.NewServer(l)
+	t.Cleanup(srv.Close)
+	cfg.URL = srv.URL
+	cfg.BaseDelay = time.Millisecond
+	cfg.MaxDelay = 5 * time.Millisecond
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+const address = "1600 Amphitheatre Parkway, Mountain View, CA"
+
+// TestTrackingIDRetries checks failures the Lambda might get past are retried,
+// and each one is recorded on the span.
+func TestTrackingIDRetries(t *testing.T) {
+	for name, fail := range map[string]func(w http.ResponseWriter){
+		"server error": func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+		"throttled":    func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+		"slow":         func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) },
+	} {
+		l := &lambda{failures: 2, fail: fail, id: "TRACK-1234"}
+		c := newTestClient(t, l, Config{Timeout: 50 * time.Millisecond})
+
+		recorder := tracetest.NewSpanRecorder()
+		ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("").Start(context.Background(), "ShipOrder")
+		id, err := c.TrackingID(ctx, address)
+		span.End()
+		if err != nil || id != "TRACK-1234" {
+			t.Errorf("TestTrackingIDRetries(%s): got %q, %v, want TRACK-1234", name, id, err)
+		}
+		if got := l.calls.Load(); got != 3 {
+			t.Errorf("TestTrackingIDRetries(%s): %d calls, want 3", name, got)
+		}
+		var attempts, failures int
+		for _, s := range recorder.Ended() {
+			if s.Name() == "ShipOrder" {
+				failures = len(s.Events())
+			} else {
+				attempts++
+			}
+		}
+		if attempts != 3 || failures != 2 {
+			t.Errorf("TestTrackingIDRetries(%s): %d HTTP spans and %d span events, want a span for each attempt and an event for each failure", name, attempts, failures)
+		}
+	}
+}
+
+// TestTrackingIDGivesUp checks the client stops after its attempts, and doesn't
+// retry failures that would only happen again.
+func TestTrackingIDGivesUp(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		fail   func(w http.ResponseWriter)
+		calls  int32
+		reason string
+	}{
+		{"down", func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }, 3, "http_status"},
+		{"slow", func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) }, 3, "timeout"},
+		{"rejected", func(w http.ResponseWriter) { w.WriteHeader(http.StatusForbidden) }, 1, "http_status"},
+		{"garbage", func(w http.ResponseWriter) { io.WriteString(w, "<html>oops</html>") }, 1, "invalid_response"},
+		{"empty", func(w http.ResponseWriter) {}, 1, "invalid_response"},
+		{"huge", func(w http.ResponseWriter) { io.WriteString(w, strings.Repeat("A", 2*maxResponse)) }, 1, "invalid_response"},
+	} {
+		l := &lambda{failures: 100, fail: tc.fail}
+		c := newTestClient(t, l, Config{Timeout: 50 * time.Millisecond})
+		_, err := c.TrackingID(context.Background(), address)
+		if err == nil {
+			t.Errorf("TestTrackingIDGivesUp(%s): got a tracking ID", tc.name)
+			continue
+		}
+		if got := Reason(err); got != tc.reason {
+			t.Errorf("TestTrackingIDGivesUp(%s): got reason %s (%v), want %s", tc.name, got, err, tc.reason)
+		}
+		if got := l.calls.Load(); got != tc.calls {
+			t.Errorf("TestTrackingIDGivesUp(%s): %d calls, want %d", tc.name, got, tc.calls)
+		}
+	}
+}
+
+// TestTrackingIDCircuit checks a failing Lambda stops being called until it
+// has had time to recover.
+func TestTrackingIDCircuit(t *testing.T) {
+	l := &lambda{failures: 4, fail: func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) }, id: "TRACK-1234"}
+	c := newTestClient(t, l, Config{Attempts: 2, FailureThreshold: 4, Cooldown: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.TrackingID(ctx, address); err == nil {
+			t.Fatalf("TestTrackingIDCircuit: call %d got a tracking ID", i)
+		}
+	}
+	if _, err := c.TrackingID(ctx, address); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("TestTrackingIDCircuit: got %v, want ErrCircuitOpen", err)
+	}
+	if got := l.calls.Load(); got != 4 {
+		t.Errorf("TestTrackingIDCircuit: %d calls, want none while the circuit is open", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if id, err := c.TrackingID(ctx, address); err != nil || id != "TRACK-1234" {
+		t.Fatalf("TestTrackingIDCircuit: got %q, %v after the cooldown, want TRACK-1234", id, err)
+	}
+	if c.State() != Closed {
+		t.Errorf("TestTrackingIDCircuit: got %s, want closed", c.State())
+	}
+}
+
+// TestTrackingIDValidate checks the client's validator decides what counts as
+// a tracking ID.
+func TestTrackingIDValidate(t *testing.T) {
+	l := &lambda{id: "not an ID"}
+	c := newTestClient(t, l, Config{})
+	if _, err := c.TrackingID(context.Background(), address); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("TestTrackingIDValidate: got %v, want ErrInvalidResponse", err)
+	}
+
+	strict := errors.New("not from this lambda")
+	c = newTestClient(t, &lambda{id: "TRACK-1234"}, Config{Validate: func(string) error { return strict }})
+	if _, err := c.TrackingID(context.Background(), address); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("TestTrackingIDValidate: got %v, want ErrInvalidResponse", err)
+	}
+
+	if _, err := New(Config{URL: "ftp://example.com"}); err == nil {
+		t.Error("TestTrackingIDValidate: accepted an ftp URL")
+	}
+}